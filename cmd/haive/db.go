@@ -0,0 +1,760 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/commands"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func handleDB(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: haive db <list|stats|migrations|diff|query|dump|import|clone|dumps|snapshot|restore|snapshots|volume-snapshot|volume-restore|trash|undo> [options]\n")
+		os.Exit(1)
+	}
+
+	// Handle help flags
+	if args[0] == "--help" || args[0] == "-h" || args[0] == "help" {
+		printDBHelp()
+		return
+	}
+
+	database := ""
+	source := ""
+	confirm := false
+	dryRun := false
+	anonymize := false
+	showCreate := false
+	withStats := false
+	withMigrations := false
+	withSQL := false
+	asJSON := false
+	limit := 20
+	maxRows := 0
+	var filter types.TableFilter
+	var positional []string
+	for _, arg := range args[1:] {
+		switch {
+		case strings.HasPrefix(arg, "--database="):
+			database = strings.TrimPrefix(arg, "--database=")
+		case strings.HasPrefix(arg, "--source="):
+			source = strings.TrimPrefix(arg, "--source=")
+		case strings.HasPrefix(arg, "--tables="):
+			filter.Tables = append(filter.Tables, splitList(strings.TrimPrefix(arg, "--tables="))...)
+		case strings.HasPrefix(arg, "--exclude-tables="):
+			filter.ExcludeTables = append(filter.ExcludeTables, splitList(strings.TrimPrefix(arg, "--exclude-tables="))...)
+		case strings.HasPrefix(arg, "--structure-only="):
+			filter.StructureOnly = append(filter.StructureOnly, splitList(strings.TrimPrefix(arg, "--structure-only="))...)
+		case arg == "--schema-only":
+			filter.SchemaOnly = true
+		case arg == "--data-only":
+			filter.DataOnly = true
+		case arg == "--anonymize":
+			anonymize = true
+		case arg == "--confirm" || arg == "-y":
+			confirm = true
+		case arg == "--dry-run" || arg == "-n":
+			dryRun = true
+		case arg == "--create":
+			showCreate = true
+		case arg == "--stats":
+			withStats = true
+		case arg == "--migrations":
+			withMigrations = true
+		case arg == "--sql":
+			withSQL = true
+		case arg == "--json":
+			asJSON = true
+		case strings.HasPrefix(arg, "--max-rows="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--max-rows="))
+			if err != nil || n < 1 {
+				fmt.Fprintf(os.Stderr, "Error: --max-rows must be a positive number\n")
+				os.Exit(1)
+			}
+			maxRows = n
+		case strings.HasPrefix(arg, "--limit="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--limit="))
+			if err != nil || n < 0 {
+				fmt.Fprintf(os.Stderr, "Error: --limit must be a number of tables, 0 for all\n")
+				os.Exit(1)
+			}
+			limit = n
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	switch args[0] {
+	case "list":
+		handleDBList(ctx, withStats, withMigrations)
+
+	case "stats":
+		if len(positional) > 0 {
+			database = positional[0]
+		}
+		handleDBStats(ctx, database, limit)
+
+	case "migrations":
+		if len(positional) > 0 {
+			database = positional[0]
+		}
+		handleDBMigrations(ctx, database)
+
+	case "diff":
+		switch len(positional) {
+		case 1:
+			handleDBDiff(ctx, "", positional[0], withSQL)
+		case 2:
+			handleDBDiff(ctx, positional[0], positional[1], withSQL)
+		default:
+			fmt.Fprintf(os.Stderr, "Usage: haive db diff [<from>] <to> [--sql]\n")
+			os.Exit(1)
+		}
+
+	case "query":
+		if len(positional) != 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db query \"<sql>\" [--database=<db>] [--max-rows=<n>] [--json]\n")
+			os.Exit(1)
+		}
+		handleDBQuery(ctx, database, positional[0], maxRows, asJSON)
+
+	case "dump":
+		progress, progressDone := progressPrinter()
+		result, err := commands.Dump(ctx, ".", database, types.DumpOptions{TableFilter: filter, Anonymize: anonymize, Progress: progress})
+		progressDone()
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		suffix := ""
+		if result.Anonymized {
+			suffix = " (anonymized)"
+		}
+		fmt.Printf("✓ Dumped %s%s\n", result.Database, suffix)
+		fmt.Printf("✓ Path: %s (%s)\n", result.Path, formatSize(result.Size))
+		for _, d := range result.Pruned {
+			fmt.Printf("  Pruned %s (%s)\n", d.Name, d.Reason)
+		}
+
+	case "import":
+		if len(positional) < 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db import <file> [--database=<db>] [--tables=<a,b>]\n")
+			os.Exit(1)
+		}
+
+		progress, progressDone := progressPrinter()
+		result, err := commands.ImportDB(ctx, ".", database, positional[0], filter.Tables, progress)
+		progressDone()
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Imported %s into %s\n", result.Path, result.Database)
+		if len(result.Tables) > 0 {
+			fmt.Printf("✓ Tables: %s\n", strings.Join(result.Tables, ", "))
+		}
+		if result.Backup != "" {
+			fmt.Printf("✓ Previous contents: %s (haive db undo restores them)\n", result.Backup)
+		}
+
+	case "clone":
+		if len(positional) < 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db clone <target> [--source=<db>] [--anonymize]\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.CloneDB(ctx, ".", source, positional[0], types.CloneOptions{TableFilter: filter, Anonymize: anonymize})
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		suffix := ""
+		if result.Anonymized {
+			suffix = " (anonymized)"
+		}
+		fmt.Printf("✓ Cloned %s into %s%s\n", result.Source, result.Target, suffix)
+
+	case "dumps":
+		if len(positional) > 0 && positional[0] == "prune" {
+			handleDumpsPrune(dryRun)
+			return
+		}
+		if len(positional) > 0 && positional[0] == "verify" {
+			handleDumpsVerify(ctx)
+			return
+		}
+		if len(positional) > 1 && positional[0] == "inspect" {
+			handleDumpsInspect(ctx, positional[1], showCreate)
+			return
+		}
+		if len(positional) > 0 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db dumps [prune [--dry-run] | verify | inspect <file> [--create]]\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.ListDumps(".")
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(result.Dumps) == 0 {
+			fmt.Println("No dumps found")
+			return
+		}
+
+		for _, d := range result.Dumps {
+			fmt.Printf("  %-45s %-20s %s  %10s\n", d.Name, d.Database, d.Modified, formatSize(d.Size))
+		}
+
+	case "snapshot":
+		if len(positional) < 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db snapshot <name> [--database=<db>]\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.Snapshot(ctx, ".", database, positional[0])
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Saved snapshot '%s' of %s\n", result.Name, result.Database)
+		fmt.Printf("✓ Path: %s (%s)\n", result.Path, formatSize(result.Size))
+
+	case "restore":
+		if len(positional) < 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db restore <name> [--database=<db>] --confirm\n")
+			os.Exit(1)
+		}
+
+		if !confirm {
+			fmt.Fprintf(os.Stderr, "Error: restore replaces the current database contents, re-run with --confirm\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.RestoreSnapshot(ctx, ".", database, positional[0])
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Restored %s from snapshot '%s'\n", result.Database, result.Name)
+
+	case "snapshots":
+		result, err := commands.ListSnapshots(".", database)
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(result.Snapshots) == 0 {
+			fmt.Println("No snapshots found")
+			return
+		}
+
+		current := ""
+		for _, s := range result.Snapshots {
+			if s.Database != current {
+				current = s.Database
+				fmt.Printf("%s:\n", current)
+			}
+			branch := ""
+			if s.GitBranch != "" {
+				branch = "  [" + s.GitBranch + "]"
+			}
+			fmt.Printf("  %-30s %s  %10s%s\n", s.Name, s.Created, formatSize(s.Size), branch)
+		}
+
+	case "volume-snapshot":
+		if len(positional) < 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db volume-snapshot <name>\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.VolumeSnapshot(ctx, ".", positional[0])
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Saved volume snapshot '%s' of %s (service %s)\n", result.Name, result.Volume, result.Service)
+		fmt.Printf("✓ Path: %s (%s)\n", result.Path, formatSize(result.Size))
+
+	case "volume-restore":
+		if len(positional) < 1 {
+			fmt.Fprintf(os.Stderr, "Usage: haive db volume-restore <name> --confirm\n")
+			os.Exit(1)
+		}
+
+		if !confirm {
+			fmt.Fprintf(os.Stderr, "Error: volume-restore replaces all databases of the service, re-run with --confirm\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.VolumeRestore(ctx, ".", positional[0])
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Restored volume %s from volume snapshot '%s'\n", result.Volume, result.Name)
+
+	case "trash":
+		result, err := commands.ListTrash(".")
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(result.Trash) == 0 {
+			fmt.Println("The trash is empty")
+			return
+		}
+
+		for _, t := range result.Trash {
+			branch := ""
+			if t.GitBranch != "" {
+				branch = "  [" + t.GitBranch + "]"
+			}
+			fmt.Printf("  %-30s before %-6s  %s  %10s%s\n", t.Database, t.Operation, t.Created, formatSize(t.Size), branch)
+		}
+
+	case "undo":
+		if len(positional) > 0 {
+			database = positional[0]
+		}
+
+		if !confirm {
+			fmt.Fprintf(os.Stderr, "Error: undo replaces the current database contents with the latest backup in the trash, re-run with --confirm\n")
+			os.Exit(1)
+		}
+
+		result, err := commands.Undo(ctx, ".", database)
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Restored %s from the trash (backup of %s)\n", result.Database, result.Created)
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown db command: %s\n", args[0])
+		fmt.Fprintf(os.Stderr, "Usage: haive db <list|stats|migrations|diff|query|dump|import|clone|dumps|snapshot|restore|snapshots|volume-snapshot|volume-restore|trash|undo> [options]\n")
+		os.Exit(1)
+	}
+}
+
+func handleDBList(ctx context.Context, withStats, withMigrations bool) {
+	result, err := commands.ListDBs(ctx, ".", withStats, withMigrations)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(result.Databases) == 0 {
+		fmt.Println("No databases found")
+		return
+	}
+
+	for _, db := range result.Databases {
+		prefix := "  "
+		if db.IsDefault {
+			prefix = "* "
+		}
+		line := prefix + db.Name
+		if db.Stats != nil {
+			line = fmt.Sprintf("%s%-30s %10s %6d tables %12d rows  %s", prefix, db.Name,
+				formatSize(db.Stats.Size), db.Stats.TableCount, db.Stats.Rows, db.Stats.Modified)
+		}
+		if db.Migrations != nil {
+			line = fmt.Sprintf("%-32s  %s", line, migrationSummary(db.Migrations))
+		}
+		fmt.Println(line)
+	}
+}
+
+// migrationSummary sums up a migration status in a few words
+func migrationSummary(status *types.MigrationStatus) string {
+	switch {
+	case status.NoTable:
+		return "no migrations table"
+	case len(status.Pending) == 0 && len(status.Unknown) == 0:
+		return "migrations up to date"
+	case len(status.Unknown) == 0:
+		return fmt.Sprintf("%d pending migrations", len(status.Pending))
+	case len(status.Pending) == 0:
+		return fmt.Sprintf("%d unknown migrations", len(status.Unknown))
+	}
+	return fmt.Sprintf("%d pending, %d unknown migrations", len(status.Pending), len(status.Unknown))
+}
+
+func handleDBMigrations(ctx context.Context, database string) {
+	result, err := commands.DatabaseMigrations(ctx, ".", database)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s: %s (%d of %d in %s executed)\n", result.Database, migrationSummary(result),
+		result.Available-len(result.Pending), result.Available, result.Path)
+	if result.Current != "" {
+		fmt.Printf("  Current: %s\n", result.Current)
+	}
+	if len(result.Pending) > 0 {
+		fmt.Println()
+		fmt.Println("  Pending:")
+		for _, version := range result.Pending {
+			fmt.Printf("    %s\n", version)
+		}
+	}
+	if len(result.Unknown) > 0 {
+		fmt.Println()
+		fmt.Println("  Executed, but not in the migrations directory:")
+		for _, version := range result.Unknown {
+			fmt.Printf("    %s\n", version)
+		}
+	}
+}
+
+func handleDBStats(ctx context.Context, database string, limit int) {
+	result, err := commands.DatabaseStats(ctx, ".", database, limit)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s (%s, %d tables, ~%d rows)\n", result.Database, formatSize(result.Stats.Size), result.Stats.TableCount, result.Stats.Rows)
+	if result.Stats.Modified != "" {
+		fmt.Printf("  Modified: %s\n", result.Stats.Modified)
+	}
+
+	if len(result.Tables) == 0 {
+		fmt.Println("No tables found")
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-40s %12s  %10s  %10s\n", "Table", "Rows", "Data", "Indexes")
+	for _, table := range result.Tables {
+		fmt.Printf("  %-40s %12d  %10s  %10s\n", table.Name, table.Rows, formatSize(table.DataSize), formatSize(table.IndexSize))
+	}
+	if len(result.Tables) < result.Stats.TableCount {
+		fmt.Printf("  ... %d more tables (--limit=0 lists all)\n", result.Stats.TableCount-len(result.Tables))
+	}
+}
+
+func handleDBDiff(ctx context.Context, from, to string, withSQL bool) {
+	result, err := commands.DiffSchemas(ctx, ".", from, to, withSQL)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(result.Changes) == 0 {
+		fmt.Printf("✓ Schemas of %s and %s are identical\n", result.From, result.To)
+		return
+	}
+
+	fmt.Printf("--- %s\n+++ %s\n", result.From, result.To)
+	table := ""
+	for _, c := range result.Changes {
+		switch c.Kind {
+		case types.SchemaAddTable:
+			fmt.Printf("+ table %s (%s)\n", c.Table, c.To)
+			table = ""
+			continue
+		case types.SchemaDropTable:
+			fmt.Printf("- table %s (%s)\n", c.Table, c.From)
+			table = ""
+			continue
+		}
+
+		if c.Table != table {
+			table = c.Table
+			fmt.Printf("  table %s\n", table)
+		}
+		// add_foreign_key → foreign key
+		_, object, _ := strings.Cut(string(c.Kind), "_")
+		object = strings.ReplaceAll(object, "_", " ")
+		switch {
+		case c.From == "":
+			fmt.Printf("    + %s %s %s\n", object, c.Name, c.To)
+		case c.To == "":
+			fmt.Printf("    - %s %s %s\n", object, c.Name, c.From)
+		default:
+			fmt.Printf("    ~ %s %s %s → %s\n", object, c.Name, c.From, c.To)
+		}
+	}
+	fmt.Printf("%d differences\n", len(result.Changes))
+
+	if withSQL {
+		fmt.Println()
+		for _, statement := range result.Statements {
+			if strings.HasPrefix(statement, "--") {
+				fmt.Println(statement)
+			} else {
+				fmt.Println(statement + ";")
+			}
+		}
+	}
+}
+
+func handleDBQuery(ctx context.Context, database, query string, maxRows int, asJSON bool) {
+	result, err := commands.QueryDB(ctx, ".", database, query, maxRows)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if asJSON {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(result.Columns) > 0 {
+		// Keep every row on one line
+		escape := strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		header := make([]string, len(result.Columns))
+		for i, column := range result.Columns {
+			header[i] = escape.Replace(column)
+		}
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range result.Rows {
+			cells := make([]string, len(row))
+			for i, value := range row {
+				cells[i] = "NULL"
+				if value != nil {
+					cells[i] = escape.Replace(*value)
+				}
+			}
+			fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+		w.Flush()
+	}
+
+	fmt.Printf("%d rows in %s", len(result.Rows), result.Duration.Round(time.Millisecond))
+	if result.Truncated {
+		fmt.Print(", more not shown (--max-rows=<n> shows up to 1000)")
+	}
+	fmt.Println()
+}
+
+func handleDumpsPrune(dryRun bool) {
+	result, err := commands.PruneDumps(".", dryRun)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(result.Removed) == 0 {
+		fmt.Printf("Nothing to prune (%d dumps kept)\n", result.Kept)
+		return
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, d := range result.Removed {
+		fmt.Printf("  %s %s (%s, %s)\n", verb, d.Name, formatSize(d.Size), d.Reason)
+	}
+	fmt.Printf("✓ %s %d dumps, %s freed, %d kept\n", verb, len(result.Removed), formatSize(result.Freed), result.Kept)
+}
+
+func handleDumpsVerify(ctx context.Context) {
+	result, err := commands.VerifyDumps(ctx, ".")
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(result.Dumps) == 0 {
+		fmt.Println("No dumps found")
+		return
+	}
+
+	for _, d := range result.Dumps {
+		if d.Valid {
+			fmt.Printf("  ✓ %-45s %10s  %s\n", d.Name, formatSize(d.Size), strings.Join(d.Checks, ", "))
+		} else {
+			fmt.Printf("  ✗ %-45s %10s  %s\n", d.Name, formatSize(d.Size), d.Problem)
+		}
+	}
+
+	if result.Invalid > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d of %d files failed verification\n", result.Invalid, len(result.Dumps))
+		os.Exit(1)
+	}
+	fmt.Printf("✓ All %d files verified\n", len(result.Dumps))
+}
+
+func handleDumpsInspect(ctx context.Context, file string, showCreate bool) {
+	result, err := commands.InspectDump(ctx, ".", file)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s (%s, %s of SQL)\n", result.Name, formatSize(result.Size), formatSize(result.SQLSize))
+	if result.Engine != "" {
+		fmt.Printf("  Engine:  %s %s\n", result.Engine, result.ServerVersion)
+	}
+	if result.DumpTool != "" {
+		fmt.Printf("  Tool:    %s\n", result.DumpTool)
+	}
+	if result.Charset != "" {
+		fmt.Printf("  Charset: %s\n", result.Charset)
+	}
+	if result.Metadata != nil && result.Metadata.GitBranch != "" {
+		fmt.Printf("  Branch:  %s\n", result.Metadata.GitBranch)
+	}
+
+	if len(result.Tables) == 0 {
+		fmt.Println("No tables found")
+		return
+	}
+
+	fmt.Println()
+	for _, table := range result.Tables {
+		fmt.Printf("  %-40s %12d rows  %10s\n", table.Name, table.Rows, formatSize(table.Bytes))
+		if showCreate && table.Create != "" {
+			fmt.Printf("\n%s\n\n", table.Create)
+		}
+	}
+}
+
+// splitList splits a comma separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// progressPrinter returns a ProgressFunc that keeps the bytes moved so far on
+// one line of stderr, and done to clear that line afterwards. Both do nothing
+// when stderr is no terminal.
+func progressPrinter() (progress types.ProgressFunc, done func()) {
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, func() {}
+	}
+
+	printed := false
+	progress = func(stage types.ProgressStage, detail string) {
+		printed = true
+		fmt.Fprintf(os.Stderr, "\r\033[K  %s: %s", stage, detail)
+	}
+	done = func() {
+		if printed {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	}
+	return progress, done
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func printDBHelp() {
+	var (
+		reset   = "\033[0m"
+		bold    = "\033[1m"
+		cyan    = "\033[36m"
+		green   = "\033[32m"
+		yellow  = "\033[33m"
+		magenta = "\033[35m"
+	)
+
+	fmt.Println()
+	fmt.Println(cyan + "haive db" + reset + " - Manage databases")
+	fmt.Println()
+	fmt.Println(bold + "Usage:" + reset)
+	fmt.Println("  " + green + "haive db <command> [options]" + reset)
+	fmt.Println()
+	fmt.Println(bold + "Commands:" + reset)
+	fmt.Println("  " + yellow + "list" + reset + "                  List databases (--stats adds sizes and table counts)")
+	fmt.Println("  " + yellow + "stats [db]" + reset + "            Show the size of a database and its largest tables")
+	fmt.Println("  " + yellow + "migrations [db]" + reset + "       Show pending and unknown Doctrine migrations")
+	fmt.Println("  " + yellow + "diff [from] <to>" + reset + "      Compare the schemas of two databases")
+	fmt.Println("  " + yellow + "query <sql>" + reset + "           Run a read-only SELECT, SHOW, EXPLAIN or DESCRIBE")
+	fmt.Println("  " + yellow + "dump" + reset + "                  Dump a database into the dumps directory")
+	fmt.Println("  " + yellow + "import <file>" + reset + "         Import a SQL file into a database")
+	fmt.Println("  " + yellow + "clone <target>" + reset + "        Clone a database into <target>")
+	fmt.Println("  " + yellow + "dumps" + reset + "                 List dump files")
+	fmt.Println("  " + yellow + "dumps prune" + reset + "           Remove dumps per database.retention")
+	fmt.Println("  " + yellow + "dumps verify" + reset + "          Check every dump for corruption and truncation")
+	fmt.Println("  " + yellow + "dumps inspect <file>" + reset + "  Show the tables, row counts and header of a dump")
+	fmt.Println("  " + yellow + "snapshot <name>" + reset + "       Save a named snapshot of a database")
+	fmt.Println("  " + yellow + "restore <name>" + reset + "        Restore a database from a snapshot")
+	fmt.Println("  " + yellow + "snapshots" + reset + "             List snapshots per database")
+	fmt.Println("  " + yellow + "volume-snapshot <name>" + reset + " Archive the data volume of the database service")
+	fmt.Println("  " + yellow + "volume-restore <name>" + reset + " Restore the data volume from a volume snapshot")
+	fmt.Println("  " + yellow + "trash" + reset + "                 List backups taken before drops and imports")
+	fmt.Println("  " + yellow + "undo [db]" + reset + "             Restore the latest backup in the trash")
+	fmt.Println()
+	fmt.Println(bold + "Flags:" + reset)
+	fmt.Println("  " + magenta + "--database=<db>" + reset + "       Database to use (default: database from DSN)")
+	fmt.Println("  " + magenta + "--source=<db>" + reset + "         Database to clone from (default: database from DSN)")
+	fmt.Println("  " + magenta + "--tables=<a,b>" + reset + "        Only dump, import or clone these tables")
+	fmt.Println("  " + magenta + "--exclude-tables=<a,b>" + reset + " Leave these tables out, globs allowed (e.g. messenger_*)")
+	fmt.Println("  " + magenta + "--structure-only=<a,b>" + reset + " Dump these tables without their rows, globs allowed")
+	fmt.Println("  " + magenta + "--schema-only" + reset + "         Dump table definitions only")
+	fmt.Println("  " + magenta + "--data-only" + reset + "           Dump rows only (with dump)")
+	fmt.Println("  " + magenta + "--anonymize" + reset + "           Apply database.anonymize rules (with dump and clone)")
+	fmt.Println("  " + magenta + "--confirm, -y" + reset + "         Confirm destructive operation (with restore, volume-restore and undo)")
+	fmt.Println("  " + magenta + "--dry-run, -n" + reset + "         Only show what would be pruned (with dumps prune)")
+	fmt.Println("  " + magenta + "--create" + reset + "              Print the CREATE TABLE statements (with dumps inspect)")
+	fmt.Println("  " + magenta + "--stats" + reset + "               Add size, tables, rows and last change (with list)")
+	fmt.Println("  " + magenta + "--migrations" + reset + "          Add the migration status of each database (with list)")
+	fmt.Println("  " + magenta + "--sql" + reset + "                 Print the ALTER statements turning <from> into <to> (with diff)")
+	fmt.Println("  " + magenta + "--max-rows=<n>" + reset + "        Rows to return, at most 1000 (with query, default: 100)")
+	fmt.Println("  " + magenta + "--json" + reset + "                Print the columns and rows as JSON (with query)")
+	fmt.Println("  " + magenta + "--limit=<n>" + reset + "           Number of tables to show, 0 for all (with stats, default: 20)")
+	fmt.Println()
+	fmt.Println(bold + "Examples:" + reset)
+	fmt.Println("  " + green + "haive db list --stats" + reset + "                         # Databases with their sizes")
+	fmt.Println("  " + green + "haive db stats app_test --limit=5" + reset + "             # Five largest tables of app_test")
+	fmt.Println("  " + green + "haive db migrations app_wt_feature_x" + reset + "          # Is a cloned database behind?")
+	fmt.Println("  " + green + "haive db diff app_feature_x --sql" + reset + "              # How a worktree database drifted")
+	fmt.Println("  " + green + "haive db query \"SELECT id, email FROM user\" --max-rows=10" + reset + " # Peek at a table")
+	fmt.Println("  " + green + "haive db dump --anonymize" + reset + "                     # Dump with PII replaced")
+	fmt.Println("  " + green + "haive db clone app_demo --anonymize" + reset + "           # Anonymized copy for a demo")
+	fmt.Println("  " + green + "haive db import var/dumps/app.sql.gz --tables=product,category" + reset + " # Restore two tables")
+	fmt.Println("  " + green + "haive db dump --exclude-tables='messenger_*' --structure-only=audit_log" + reset)
+	fmt.Println("  " + green + "haive db dumps prune --dry-run" + reset + "                # Preview retention cleanup")
+	fmt.Println("  " + green + "haive db dumps inspect snapshots/app/seeded.sql.gz" + reset + " # What a snapshot holds")
+	fmt.Println("  " + green + "haive db snapshot before-migration" + reset + "            # Snapshot the default database")
+	fmt.Println("  " + green + "haive db restore before-migration --confirm" + reset + "   # Roll back to the snapshot")
+	fmt.Println("  " + green + "haive db snapshots --database=app_test" + reset + "        # List snapshots of app_test")
+	fmt.Println("  " + green + "haive db volume-snapshot seeded" + reset + "               # Archive the whole database volume")
+	fmt.Println("  " + green + "haive db undo --confirm" + reset + "                       # Bring back the last dropped or overwritten database")
+	fmt.Println()
+}
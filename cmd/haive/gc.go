@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/commands"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+)
+
+func handleGC(ctx context.Context, args []string) {
+	confirm := false
+	var keep []string
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			printGCHelp()
+			return
+		case arg == "--confirm" || arg == "-y":
+			confirm = true
+		case strings.HasPrefix(arg, "--keep="):
+			for _, pattern := range strings.Split(strings.TrimPrefix(arg, "--keep="), ",") {
+				if pattern = strings.TrimSpace(pattern); pattern != "" {
+					keep = append(keep, pattern)
+				}
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Usage: haive gc [--keep=<pattern,...>] [--confirm]\n")
+			os.Exit(1)
+		}
+	}
+
+	result, err := commands.OrphanDBs(ctx, ".", keep, confirm)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(result.Orphans) == 0 {
+		fmt.Println("No orphaned databases")
+		return
+	}
+
+	failed := false
+	for _, orphan := range result.Orphans {
+		switch {
+		case orphan.Dropped:
+			fmt.Printf("  Dropped %s (%s database)\n", orphan.Name, orphan.Kind)
+		case orphan.Error != "":
+			failed = true
+			redact.Fprintf(os.Stderr, "  Kept %s (%s database): %s\n", orphan.Name, orphan.Kind, orphan.Error)
+		default:
+			fmt.Printf("  %s (%s database)\n", orphan.Name, orphan.Kind)
+		}
+	}
+
+	if !confirm {
+		fmt.Printf("%d orphaned databases, re-run with --confirm to drop them\n", len(result.Orphans))
+		return
+	}
+	fmt.Printf("✓ Dropped %d of %d orphaned databases\n", result.Dropped, len(result.Orphans))
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printGCHelp() {
+	var (
+		reset   = "\033[0m"
+		bold    = "\033[1m"
+		cyan    = "\033[36m"
+		green   = "\033[32m"
+		magenta = "\033[35m"
+	)
+
+	fmt.Println()
+	fmt.Println(cyan + "haive gc" + reset + " - Find and drop orphaned worktree and branch databases")
+	fmt.Println()
+	fmt.Println(bold + "Usage:" + reset)
+	fmt.Println("  " + green + "haive gc [flags]" + reset)
+	fmt.Println()
+	fmt.Println("A database is orphaned when it is named after a worktree (<worktrees.db_prefix><branch>)")
+	fmt.Println("or a checked out branch (<db>_<branch>, from the git reflog) that no longer exists.")
+	fmt.Println("Other <db>_* databases are left alone. Databases used by a worktree's")
+	fmt.Println(".env.local or haive.database git config, <db>_test* and databases outside")
+	fmt.Println("database.allowed are never reported. Drops run the preDrop hooks.")
+	fmt.Println()
+	fmt.Println(bold + "Flags:" + reset)
+	fmt.Println("  " + magenta + "--keep=<pattern,...>" + reset + "  Leave matching databases alone, glob patterns allowed")
+	fmt.Println("  " + magenta + "--confirm, -y" + reset + "         Drop the orphaned databases")
+	fmt.Println()
+	fmt.Println(bold + "Examples:" + reset)
+	fmt.Println("  " + green + "haive gc" + reset + "                          # List orphaned databases")
+	fmt.Println("  " + green + "haive gc --keep='app_demo*'" + reset + "       # ... except the demo databases")
+	fmt.Println("  " + green + "haive gc --confirm" + reset + "                # Drop them")
+	fmt.Println()
+}
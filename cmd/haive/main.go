@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/commands"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/mcp"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/tui"
 )
@@ -33,12 +37,22 @@ func main() {
 
 	if *mcpFlag {
 		if err := mcp.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "MCP server error: %v\n", err)
+			redact.Fprintf(os.Stderr, "MCP server error: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
+	// Ctrl+C cancels the running command so database tools are stopped and
+	// partial results are cleaned up
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// A second Ctrl+C kills haive without waiting for the cleanup
+		<-ctx.Done()
+		stop()
+	}()
+
 	args := flag.Args()
 	if len(args) > 0 {
 		switch args[0] {
@@ -46,13 +60,19 @@ func main() {
 			handleInit(args[1:])
 			return
 		case "checkout":
-			handleCheckout(args[1:])
+			handleCheckout(ctx, args[1:])
 			return
 		case "switch":
-			handleSwitch(args[1:])
+			handleSwitch(ctx, args[1:])
 			return
 		case "worktree", "wt":
-			handleWorktree(args[1:])
+			handleWorktree(ctx, args[1:])
+			return
+		case "db", "database":
+			handleDB(ctx, args[1:])
+			return
+		case "gc":
+			handleGC(ctx, args[1:])
 			return
 		case "serve":
 			handleServe(args[1:])
@@ -62,7 +82,7 @@ func main() {
 			return
 		case "tui":
 			if err := tui.Run(); err != nil {
-				fmt.Fprintf(os.Stderr, "TUI error: %v\n", err)
+				redact.Fprintf(os.Stderr, "TUI error: %v\n", err)
 				os.Exit(1)
 			}
 			return
@@ -101,6 +121,8 @@ func printHelp() {
 	fmt.Println("  " + yellow + "checkout <branch>" + reset + "     Switch git branch and database")
 	fmt.Println("  " + yellow + "switch" + reset + "                Switch database for current branch")
 	fmt.Println("  " + yellow + "worktree <cmd>" + reset + "        Manage git worktrees")
+	fmt.Println("  " + yellow + "db <cmd>" + reset + "              Manage databases and snapshots")
+	fmt.Println("  " + yellow + "gc" + reset + "                    Find and drop orphaned worktree and branch databases")
 	fmt.Println("  " + yellow + "serve <cmd>" + reset + "           Start/stop worktree containers")
 	fmt.Println("  " + yellow + "mcp <cmd>" + reset + "             Manage MCP server configuration")
 	fmt.Println("  " + yellow + "help" + reset + "                  Show this help message")
@@ -123,6 +145,15 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println(bold + "Worktree Flags:" + reset)
 	fmt.Println("  " + magenta + "--new-branch, -n" + reset + "      Create new branch (with create)")
+	fmt.Println("  " + magenta + "--migrations" + reset + "          Compare each worktree's migrations with its database (with list)")
+	fmt.Println()
+	fmt.Println(bold + "Database Commands:" + reset)
+	fmt.Println("  " + yellow + "db dump" + reset + "               Dump a database (--anonymize for PII rules)")
+	fmt.Println("  " + yellow + "db clone <target>" + reset + "     Clone a database (--anonymize for PII rules)")
+	fmt.Println("  " + yellow + "db dumps [prune]" + reset + "      List or prune dump files")
+	fmt.Println("  " + yellow + "db snapshot <name>" + reset + "    Save a named database snapshot")
+	fmt.Println("  " + yellow + "db restore <name>" + reset + "     Restore a database from a snapshot")
+	fmt.Println("  " + yellow + "db snapshots" + reset + "          List snapshots")
 	fmt.Println()
 	fmt.Println(bold + "Serve Commands:" + reset)
 	fmt.Println("  " + yellow + "serve" + reset + "                  Start containers for current worktree")
@@ -140,6 +171,7 @@ func printHelp() {
 	fmt.Println("  " + green + "haive worktree list" + reset + "                # List worktrees")
 	fmt.Println("  " + green + "haive worktree create feature/x" + reset + "    # Create worktree")
 	fmt.Println("  " + green + "haive worktree remove feature/x" + reset + "    # Remove worktree")
+	fmt.Println("  " + green + "haive gc --confirm" + reset + "                 # Drop databases of removed worktrees")
 	fmt.Println("  " + green + "haive serve" + reset + "                         # Start worktree app")
 	fmt.Println("  " + green + "haive serve stop" + reset + "                      # Stop worktree app")
 	fmt.Println()
@@ -169,7 +201,7 @@ func handleInit(args []string) {
 
 	result, err := commands.Init(".")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -196,7 +228,8 @@ func handleInit(args []string) {
 }
 
 func printAIInstructions() {
-	fmt.Println(`# Haive AI Configuration Instructions
+	// The text ends in a newline; Println would add a second one, which go vet rejects
+	fmt.Print(`# Haive AI Configuration Instructions
 
 You are helping configure haive - a development environment manager for Docker Compose projects.
 
@@ -320,7 +353,8 @@ haive mcp install claude --local
 Available MCP tools:
 - project.info, project.init
 - worktree.list, worktree.create, worktree.remove
-- db.list, db.clone, db.dump, db.create, db.drop, db.import, db.dumps
+- db.list, db.clone, db.dump, db.create, db.drop, db.import, db.dumps, db.dumps_prune
+- db.snapshot, db.restore, db.snapshots
 - workflow.create, workflow.remove
 
 ## Full Reference
@@ -329,7 +363,7 @@ See: https://github.com/mkrowiarz/haive/blob/main/AGENTS.md
 `)
 }
 
-func handleCheckout(args []string) {
+func handleCheckout(ctx context.Context, args []string) {
 	// Handle help flags
 	for _, arg := range args {
 		if arg == "--help" || arg == "-h" {
@@ -356,9 +390,9 @@ func handleCheckout(args []string) {
 		}
 	}
 
-	result, err := commands.Checkout(".", branch, createFlag, cloneFrom)
+	result, err := commands.Checkout(ctx, ".", branch, createFlag, cloneFrom)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -372,7 +406,7 @@ func handleCheckout(args []string) {
 	}
 }
 
-func handleSwitch(args []string) {
+func handleSwitch(ctx context.Context, args []string) {
 	// Handle help flags
 	for _, arg := range args {
 		if arg == "--help" || arg == "-h" {
@@ -388,9 +422,9 @@ func handleSwitch(args []string) {
 		}
 	}
 
-	result, err := commands.Switch(".", cloneFrom)
+	result, err := commands.Switch(ctx, ".", cloneFrom)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		redact.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -411,7 +445,7 @@ func gitBranchExists(branch string) bool {
 	return cmd.Run() == nil
 }
 
-func handleWorktree(args []string) {
+func handleWorktree(ctx context.Context, args []string) {
 	if len(args) == 0 {
 		fmt.Fprintf(os.Stderr, "Usage: haive worktree <list|create|remove> [options]\n")
 		os.Exit(1)
@@ -425,9 +459,10 @@ func handleWorktree(args []string) {
 
 	switch args[0] {
 	case "list", "ls":
-		worktrees, err := commands.List(".")
+		withMigrations := len(args) > 1 && args[1] == "--migrations"
+		worktrees, err := commands.List(ctx, ".", withMigrations)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
@@ -442,7 +477,11 @@ func handleWorktree(args []string) {
 			if wt.IsMain {
 				prefix = "* "
 			}
-			fmt.Printf("%s%s  (%s)\n", prefix, wt.Branch, wt.Path)
+			if wt.Migrations == nil {
+				fmt.Printf("%s%s  (%s)\n", prefix, wt.Branch, wt.Path)
+				continue
+			}
+			fmt.Printf("%s%s  (%s)  %s: %s\n", prefix, wt.Branch, wt.Path, wt.Migrations.Database, migrationSummary(wt.Migrations))
 		}
 
 	case "create", "add":
@@ -467,9 +506,9 @@ func handleWorktree(args []string) {
 			}
 		}
 
-		result, err := commands.Create(".", branch, newBranch)
+		result, err := commands.Create(ctx, ".", branch, newBranch)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
@@ -483,9 +522,9 @@ func handleWorktree(args []string) {
 		}
 
 		branch := args[1]
-		result, err := commands.Remove(".", branch)
+		result, err := commands.Remove(ctx, ".", branch)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			redact.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
@@ -522,9 +561,11 @@ func printWorktreeHelp() {
 	fmt.Println()
 	fmt.Println(bold + "Flags:" + reset)
 	fmt.Println("  " + magenta + "--new-branch, -n" + reset + "      Create new branch (with create)")
+	fmt.Println("  " + magenta + "--migrations" + reset + "          Compare each worktree's migrations with its database (with list)")
 	fmt.Println()
 	fmt.Println(bold + "Examples:" + reset)
 	fmt.Println("  " + green + "haive worktree list" + reset + "                  # List all worktrees")
+	fmt.Println("  " + green + "haive worktree list --migrations" + reset + "     # Which worktree databases are behind")
 	fmt.Println("  " + green + "haive worktree create feature/x" + reset + "      # Create worktree from existing branch")
 	fmt.Println("  " + green + "haive worktree create feature/x -n" + reset + "   # Create worktree with new branch")
 	fmt.Println("  " + green + "haive worktree remove feature/x" + reset + "      # Remove worktree")
@@ -756,6 +797,10 @@ func installMCP(tool string, local bool) {
 	fmt.Println("  • db.drop            - Drop a database")
 	fmt.Println("  • db.import          - Import a database from dump")
 	fmt.Println("  • db.dumps           - List available SQL dump files")
+	fmt.Println("  • db.dumps_prune     - Prune dumps per retention policy")
+	fmt.Println("  • db.snapshot        - Save a named database snapshot")
+	fmt.Println("  • db.restore         - Restore a database from a snapshot")
+	fmt.Println("  • db.snapshots       - List database snapshots")
 	fmt.Println("  • workflow.create    - Create worktree + database")
 	fmt.Println("  • workflow.remove    - Remove worktree + database")
 }
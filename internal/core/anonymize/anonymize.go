@@ -0,0 +1,55 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+)
+
+// Anonymizer applies rules to dump rows. Replacements are derived from the
+// original value with a keyed hash, so the same input always yields the same
+// output for a given seed and relations between tables survive.
+type Anonymizer struct {
+	seed  []byte
+	rules Rules
+}
+
+// New creates an Anonymizer for the given seed and rules
+func New(seed string, rules Rules) *Anonymizer {
+	return &Anonymizer{seed: []byte(seed), rules: rules}
+}
+
+// TableRules returns the rules for a table, matching either the qualified
+// name or the bare table name. It returns nil for tables without rules.
+func (a *Anonymizer) TableRules(table string) map[string]Rule {
+	if rules, ok := a.rules[table]; ok {
+		return rules
+	}
+	return a.rules[sqldump.BaseName(table)]
+}
+
+// Replace returns the anonymized form of a non-NULL value. The second return
+// value is false when the rule turns the value into NULL.
+func (a *Anonymizer) Replace(rule Rule, original string) (string, bool) {
+	switch rule.Kind {
+	case KindEmail:
+		return "user-" + a.token(strings.ToLower(strings.TrimSpace(original)))[:12] + "@example.com", true
+	case KindHash:
+		return a.token(original)[:16], true
+	case KindConstant:
+		return rule.Value, true
+	case KindNull:
+		return "", false
+	default:
+		return original, true
+	}
+}
+
+func (a *Anonymizer) token(value string) string {
+	mac := hmac.New(sha256.New, a.seed)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
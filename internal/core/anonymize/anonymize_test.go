@@ -0,0 +1,229 @@
+package anonymize
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+)
+
+func testAnonymizer(t *testing.T, raw map[string]map[string]string) *Anonymizer {
+	t.Helper()
+	rules, err := ParseRules(raw)
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	return New("test-seed", rules)
+}
+
+func anonymizeString(t *testing.T, a *Anonymizer, dialect sqldump.Dialect, input string) string {
+	t.Helper()
+	var out bytes.Buffer
+	w := a.NewWriter(&out, dialect)
+	if _, err := io.WriteString(w, input); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Rule
+		wantErr bool
+	}{
+		{"email", Rule{Kind: KindEmail}, false},
+		{"HASH", Rule{Kind: KindHash}, false},
+		{"null", Rule{Kind: KindNull}, false},
+		{"keep", Rule{Kind: KindKeep}, false},
+		{"constant:redacted", Rule{Kind: KindConstant, Value: "redacted"}, false},
+		{"constant:", Rule{Kind: KindConstant, Value: ""}, false},
+		{"constant", Rule{}, true},
+		{"email:x", Rule{}, true},
+		{"shuffle", Rule{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRule(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRule(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRule(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseRules_Errors(t *testing.T) {
+	if _, err := ParseRules(map[string]map[string]string{"users": {"email": "bogus"}}); err == nil || !strings.Contains(err.Error(), "users.email") {
+		t.Errorf("expected error naming the column, got %v", err)
+	}
+	if _, err := ParseRules(map[string]map[string]string{"users": {}}); err == nil {
+		t.Error("expected error for table without rules")
+	}
+}
+
+func TestReplace_Deterministic(t *testing.T) {
+	a := New("seed", nil)
+	b := New("other-seed", nil)
+
+	first, _ := a.Replace(Rule{Kind: KindEmail}, "Jane@Example.org")
+	second, _ := a.Replace(Rule{Kind: KindEmail}, "jane@example.org")
+	if first != second {
+		t.Errorf("email replacement should ignore case: %q vs %q", first, second)
+	}
+	if !strings.HasPrefix(first, "user-") || !strings.HasSuffix(first, "@example.com") {
+		t.Errorf("unexpected email %q", first)
+	}
+
+	other, _ := b.Replace(Rule{Kind: KindEmail}, "jane@example.org")
+	if other == first {
+		t.Error("different seeds should give different replacements")
+	}
+
+	hash, _ := a.Replace(Rule{Kind: KindHash}, "Jane")
+	if len(hash) != 16 {
+		t.Errorf("hash = %q", hash)
+	}
+
+	if _, ok := a.Replace(Rule{Kind: KindNull}, "x"); ok {
+		t.Error("null rule should produce NULL")
+	}
+}
+
+func TestWriter_MySQL(t *testing.T) {
+	a := testAnonymizer(t, map[string]map[string]string{
+		"users": {
+			"email":   "email",
+			"name":    "constant:Jane Doe",
+			"phone":   "null",
+			"created": "keep",
+		},
+	})
+
+	dump := "-- MySQL dump\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"  `email` varchar(255) NOT NULL,\n" +
+		"  `name` varchar(255) DEFAULT NULL,\n" +
+		"  `phone` varchar(32) DEFAULT NULL,\n" +
+		"  `created` datetime NOT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB;\n" +
+		"INSERT INTO `users` VALUES (1,'jane@real.com','Jane \\'Real\\' Smith','+48 123',NULL),(2,'bob@real.com',NULL,'555','2024-01-01');\n" +
+		"INSERT INTO `orders` VALUES (1,'jane@real.com');\n"
+
+	got := anonymizeString(t, a, sqldump.DialectMySQL, dump)
+
+	for _, leaked := range []string{"jane@real.com','Jane", "bob@real.com", "Real", "+48 123", "'555'"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("output still contains %q:\n%s", leaked, got)
+		}
+	}
+
+	email, _ := a.Replace(Rule{Kind: KindEmail}, "jane@real.com")
+	if !strings.Contains(got, "(1,'"+email+"','Jane Doe',NULL,NULL)") {
+		t.Errorf("first row not anonymized as expected:\n%s", got)
+	}
+	if !strings.Contains(got, ",NULL,NULL,'2024-01-01')") {
+		t.Errorf("NULL name should stay NULL and keep column untouched:\n%s", got)
+	}
+	if !strings.Contains(got, "INSERT INTO `orders` VALUES (1,'jane@real.com');\n") {
+		t.Errorf("tables without rules must pass through untouched:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "-- MySQL dump\nCREATE TABLE `users` (\n") {
+		t.Errorf("schema should be untouched:\n%s", got)
+	}
+}
+
+func TestWriter_PostgresCopy(t *testing.T) {
+	a := testAnonymizer(t, map[string]map[string]string{
+		"public.users": {"email": "email", "name": "hash"},
+	})
+
+	dump := "COPY public.users (id, email, name) FROM stdin;\n" +
+		"1\tjane@real.com\tJane\\tSmith\n" +
+		"2\t\\N\tBob\n" +
+		"\\.\n" +
+		"COPY public.orders (id, note) FROM stdin;\n" +
+		"1\tjane@real.com\n" +
+		"\\.\n"
+
+	got := anonymizeString(t, a, sqldump.DialectPostgres, dump)
+	lines := strings.Split(got, "\n")
+
+	email, _ := a.Replace(Rule{Kind: KindEmail}, "jane@real.com")
+	name, _ := a.Replace(Rule{Kind: KindHash}, "Jane\tSmith")
+	if want := "1\t" + email + "\t" + name; lines[1] != want {
+		t.Errorf("row 1 = %q, want %q", lines[1], want)
+	}
+	if !strings.HasPrefix(lines[2], "2\t\\N\t") {
+		t.Errorf("NULL should stay NULL: %q", lines[2])
+	}
+	if lines[5] != "1\tjane@real.com" {
+		t.Errorf("tables without rules must pass through untouched: %q", lines[5])
+	}
+}
+
+func TestWriter_PostgresMultilineInsert(t *testing.T) {
+	a := testAnonymizer(t, map[string]map[string]string{
+		"users": {"bio": "constant:redacted"},
+	})
+
+	dump := "INSERT INTO public.users (id, bio) VALUES (1, 'line one\nline two');\n" +
+		"SELECT 1;\n"
+
+	got := anonymizeString(t, a, sqldump.DialectPostgres, dump)
+	want := "INSERT INTO public.users (id, bio) VALUES (1,'redacted');\nSELECT 1;\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriter_SplitWrites(t *testing.T) {
+	a := testAnonymizer(t, map[string]map[string]string{"users": {"email": "email"}})
+	dump := "INSERT INTO users (id, email) VALUES (1,'a@b.c');\n"
+
+	var out bytes.Buffer
+	w := a.NewWriter(&out, sqldump.DialectMySQL)
+	for i := 0; i < len(dump); i++ {
+		if _, err := w.Write([]byte{dump[i]}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if want := anonymizeString(t, a, sqldump.DialectMySQL, dump); out.String() != want {
+		t.Errorf("byte-wise writes = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriter_Errors(t *testing.T) {
+	a := testAnonymizer(t, map[string]map[string]string{"users": {"email": "email"}})
+
+	tests := map[string]string{
+		"unknown columns": "INSERT INTO `users` VALUES (1,'a@b.c');\n",
+		"missing column":  "INSERT INTO users (id, mail) VALUES (1,'a@b.c');\n",
+		"truncated":       "INSERT INTO users (id, email) VALUES (1,'a@b.c\n",
+	}
+	for name, dump := range tests {
+		w := a.NewWriter(io.Discard, sqldump.DialectMySQL)
+		_, err := io.WriteString(w, dump)
+		if err == nil {
+			err = w.Close()
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if closeErr := w.Close(); closeErr == nil {
+			t.Errorf("%s: Close() should report the earlier error", name)
+		}
+	}
+}
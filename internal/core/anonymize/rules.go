@@ -0,0 +1,85 @@
+// Package anonymize rewrites personal data in SQL dumps while they stream
+// from the database container to disk or into another database.
+package anonymize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind is the type of replacement applied to a column
+type Kind string
+
+const (
+	// KindEmail replaces the value with a fake address such as user-1a2b3c4d5e6f@example.com
+	KindEmail Kind = "email"
+	// KindHash replaces the value with a short keyed hash of the original
+	KindHash Kind = "hash"
+	// KindNull replaces the value with NULL
+	KindNull Kind = "null"
+	// KindConstant replaces the value with a fixed string, written as "constant:<value>"
+	KindConstant Kind = "constant"
+	// KindKeep leaves the value untouched, documenting that a column was reviewed
+	KindKeep Kind = "keep"
+)
+
+// Rule describes how one column is anonymized
+type Rule struct {
+	Kind  Kind
+	Value string // replacement for KindConstant
+}
+
+// ParseRule parses the textual form used in the config file
+func ParseRule(s string) (Rule, error) {
+	kind, value, hasValue := strings.Cut(strings.TrimSpace(s), ":")
+
+	switch Kind(strings.ToLower(kind)) {
+	case KindEmail, KindHash, KindNull, KindKeep:
+		if hasValue {
+			return Rule{}, fmt.Errorf("rule %q does not take a value", kind)
+		}
+		return Rule{Kind: Kind(strings.ToLower(kind))}, nil
+	case KindConstant:
+		if !hasValue {
+			return Rule{}, fmt.Errorf("rule %q needs a value, e.g. \"constant:redacted\"", kind)
+		}
+		return Rule{Kind: KindConstant, Value: value}, nil
+	default:
+		return Rule{}, fmt.Errorf("unknown rule %q (expected email, hash, null, constant:<value> or keep)", s)
+	}
+}
+
+// Rules maps table names to column names to rules. Table names may be
+// schema-qualified (public.users) or bare (users).
+type Rules map[string]map[string]Rule
+
+// ParseRules parses the [database.anonymize.rules] table of the config file
+func ParseRules(raw map[string]map[string]string) (Rules, error) {
+	rules := make(Rules, len(raw))
+	for _, table := range sortedKeys(raw) {
+		columns := raw[table]
+		if len(columns) == 0 {
+			return nil, fmt.Errorf("table %q has no column rules", table)
+		}
+
+		rules[table] = make(map[string]Rule, len(columns))
+		for _, column := range sortedKeys(columns) {
+			rule, err := ParseRule(columns[column])
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", table, column, err)
+			}
+			rules[table][column] = rule
+		}
+	}
+	return rules, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
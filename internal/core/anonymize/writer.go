@@ -0,0 +1,260 @@
+package anonymize
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+)
+
+type writerState int
+
+const (
+	stateStatements writerState = iota
+	stateCreateTable
+	stateInsert
+	stateCopy
+)
+
+// Writer anonymizes a SQL dump written to it line by line
+type Writer struct {
+	a       *Anonymizer
+	dialect sqldump.Dialect
+	out     io.Writer
+
+	buf     []byte
+	state   writerState
+	table   string
+	columns []string
+	stmt    strings.Builder
+	copy    []columnRule
+
+	tableColumns map[string][]string // column order learned from CREATE TABLE
+	err          error               // first failure, returned by every later call
+}
+
+type columnRule struct {
+	index int
+	rule  Rule
+}
+
+// NewWriter returns a Writer that anonymizes a SQL dump of the given dialect
+// and writes the result to w. Close must be called to flush the final line;
+// it fails if the dump ended in the middle of a statement.
+func (a *Anonymizer) NewWriter(w io.Writer, dialect sqldump.Dialect) *Writer {
+	return &Writer{a: a, dialect: dialect, out: w, tableColumns: make(map[string][]string)}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx == -1 {
+			break
+		}
+		line := string(w.buf[:idx+1])
+		w.buf = w.buf[idx+1:]
+		if w.err = w.line(line); w.err != nil {
+			return 0, w.err
+		}
+	}
+	return len(p), nil
+}
+
+// Err returns the error that stopped the rewrite, if any
+func (w *Writer) Err() error {
+	return w.err
+}
+
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.buf) > 0 {
+		line := string(w.buf)
+		w.buf = nil
+		if w.err = w.line(line); w.err != nil {
+			return w.err
+		}
+	}
+
+	switch w.state {
+	case stateInsert:
+		w.err = fmt.Errorf("dump ended inside an INSERT into %s", w.table)
+	case stateCopy:
+		w.err = fmt.Errorf("dump ended inside the COPY data of %s", w.table)
+	}
+	return w.err
+}
+
+func (w *Writer) line(line string) error {
+	switch w.state {
+	case stateCreateTable:
+		if sqldump.IsCreateTableEnd(line) {
+			w.tableColumns[w.table] = w.columns
+			w.state = stateStatements
+		} else if column, ok := sqldump.ParseColumnDefinition(line); ok {
+			w.columns = append(w.columns, column)
+		}
+		return w.write(line)
+
+	case stateInsert:
+		w.stmt.WriteString(line)
+		if !sqldump.StatementComplete(w.stmt.String(), w.dialect) {
+			return nil
+		}
+		w.state = stateStatements
+		return w.insert(w.stmt.String())
+
+	case stateCopy:
+		if strings.TrimRight(line, "\r\n") == sqldump.CopyTerminator {
+			w.state = stateStatements
+			return w.write(line)
+		}
+		return w.copyRow(line)
+	}
+
+	if table, ok := sqldump.ParseCreateTable(line); ok {
+		w.state = stateCreateTable
+		w.table = table
+		w.columns = nil
+		return w.write(line)
+	}
+
+	if sqldump.IsInsert(line) {
+		if sqldump.StatementComplete(line, w.dialect) {
+			return w.insert(line)
+		}
+		w.state = stateInsert
+		w.table = insertTable(line)
+		w.stmt.Reset()
+		w.stmt.WriteString(line)
+		return nil
+	}
+
+	if table, columns, ok := sqldump.ParseCopy(line); ok {
+		if rules := w.a.TableRules(table); rules != nil {
+			resolved, err := resolveColumns(table, columns, rules)
+			if err != nil {
+				return err
+			}
+			w.state = stateCopy
+			w.table = table
+			w.copy = resolved
+		}
+		return w.write(line)
+	}
+
+	return w.write(line)
+}
+
+func (w *Writer) insert(stmt string) error {
+	ins, err := sqldump.ParseInsert(stmt, w.dialect)
+	if err != nil {
+		// Statements of tables without rules are passed through untouched
+		if w.a.TableRules(insertTable(stmt)) == nil {
+			return w.write(stmt)
+		}
+		return err
+	}
+
+	rules := w.a.TableRules(ins.Table)
+	if rules == nil {
+		return w.write(stmt)
+	}
+
+	columns := ins.Columns
+	if columns == nil {
+		columns = w.tableColumns[ins.Table]
+	}
+	if columns == nil {
+		return fmt.Errorf("cannot anonymize %s: column names are unknown (the dump has no CREATE TABLE or column list for it)", ins.Table)
+	}
+
+	resolved, err := resolveColumns(ins.Table, columns, rules)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range ins.Rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("cannot anonymize %s: row has %d values but the table has %d columns", ins.Table, len(row), len(columns))
+		}
+		for _, c := range resolved {
+			if row[c.index].IsNull() {
+				continue
+			}
+			value, ok := w.a.Replace(c.rule, row[c.index].Text(w.dialect))
+			if ok {
+				row[c.index] = sqldump.QuoteString(value, w.dialect)
+			} else {
+				row[c.index] = "NULL"
+			}
+		}
+	}
+
+	return w.write(ins.String())
+}
+
+func (w *Writer) copyRow(line string) error {
+	body := strings.TrimRight(line, "\r\n")
+	fields := sqldump.SplitCopyRow(body)
+
+	for _, c := range w.copy {
+		if c.index >= len(fields) {
+			return fmt.Errorf("cannot anonymize %s: COPY row has %d fields", w.table, len(fields))
+		}
+		if fields[c.index] == sqldump.CopyNull {
+			continue
+		}
+		value, ok := w.a.Replace(c.rule, sqldump.UnescapeCopy(fields[c.index]))
+		if ok {
+			fields[c.index] = sqldump.EscapeCopy(value)
+		} else {
+			fields[c.index] = sqldump.CopyNull
+		}
+	}
+
+	return w.write(sqldump.JoinCopyRow(fields) + line[len(body):])
+}
+
+func (w *Writer) write(s string) error {
+	_, err := io.WriteString(w.out, s)
+	return err
+}
+
+// resolveColumns maps every rule of a table to a column position. A rule for a
+// column the table does not have is an error rather than a silent no-op, so a
+// typo in the config can't leak the data it was meant to hide.
+func resolveColumns(table string, columns []string, rules map[string]Rule) ([]columnRule, error) {
+	positions := make(map[string]int, len(columns))
+	for i, column := range columns {
+		positions[column] = i
+	}
+
+	var resolved []columnRule
+	for _, column := range sortedKeys(rules) {
+		idx, ok := positions[column]
+		if !ok {
+			return nil, fmt.Errorf("cannot anonymize %s: column %q does not exist", table, column)
+		}
+		if rules[column].Kind != KindKeep {
+			resolved = append(resolved, columnRule{index: idx, rule: rules[column]})
+		}
+	}
+	return resolved, nil
+}
+
+func insertTable(stmt string) string {
+	rest := strings.TrimSpace(stmt[len("INSERT INTO "):])
+	if idx := strings.IndexAny(rest, " (\t\n"); idx != -1 {
+		rest = rest[:idx]
+	}
+	return sqldump.UnquoteIdent(rest)
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/anonymize"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// newAnonymizer builds the anonymizer for the database.anonymize section
+func newAnonymizer(cfg *config.Config) (*anonymize.Anonymizer, error) {
+	// The rewriter can't follow the single line CREATE TABLE statements of
+	// sqlite3's .dump, so it would let rows through unchanged
+	if dsn.IsSQLite(cfg.Database.DSN) {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "anonymize is not supported for SQLite databases",
+		}
+	}
+
+	if cfg.Database.Anonymize == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "anonymize requested but no [database.anonymize] rules are configured",
+		}
+	}
+
+	anonymizer, err := cfg.Database.Anonymize.NewAnonymizer()
+	if err != nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: err.Error(),
+		}
+	}
+
+	return anonymizer, nil
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,7 +12,6 @@ import (
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
-	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
 )
 
 type CheckoutResult struct {
@@ -29,11 +29,11 @@ type SwitchResult struct {
 }
 
 // Checkout switches to a git branch and sets up the corresponding database
-func Checkout(projectRoot, branch string, create bool, cloneFrom string) (*CheckoutResult, error) {
+func Checkout(ctx context.Context, projectRoot, branch string, create bool, cloneFrom string) (*CheckoutResult, error) {
 	// First, switch git branch
 	if create {
 		// Create new branch
-		cmd := exec.Command("git", "checkout", "-b", branch)
+		cmd := exec.CommandContext(ctx, "git", "checkout", "-b", branch)
 		cmd.Dir = projectRoot
 		output, err := cmd.CombinedOutput()
 		if err != nil {
@@ -41,7 +41,7 @@ func Checkout(projectRoot, branch string, create bool, cloneFrom string) (*Check
 		}
 	} else {
 		// Checkout existing branch
-		cmd := exec.Command("git", "checkout", branch)
+		cmd := exec.CommandContext(ctx, "git", "checkout", branch)
 		cmd.Dir = projectRoot
 		output, err := cmd.CombinedOutput()
 		if err != nil {
@@ -50,7 +50,7 @@ func Checkout(projectRoot, branch string, create bool, cloneFrom string) (*Check
 	}
 
 	// Then switch database for this branch
-	switchResult, err := Switch(projectRoot, cloneFrom)
+	switchResult, err := Switch(ctx, projectRoot, cloneFrom)
 	if err != nil {
 		return nil, err
 	}
@@ -64,18 +64,12 @@ func Checkout(projectRoot, branch string, create bool, cloneFrom string) (*Check
 }
 
 // Switch switches the database for the current branch (without changing git branch)
-func Switch(projectRoot string, cloneFrom string) (*SwitchResult, error) {
+func Switch(ctx context.Context, projectRoot string, cloneFrom string) (*SwitchResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		return nil, err
 	}
 
-	// For docker operations, use the config's project root (handles worktrees)
-	dockerRoot := projectRoot
-	if cfg.ProjectRoot != "" {
-		dockerRoot = cfg.ProjectRoot
-	}
-
 	if cfg.Database == nil {
 		return nil, &types.CommandError{
 			Code:    types.ErrConfigMissing,
@@ -83,8 +77,15 @@ func Switch(projectRoot string, cloneFrom string) (*SwitchResult, error) {
 		}
 	}
 
+	// For docker operations, use the config's project root (handles worktrees).
+	// SQLite files are resolved against the checkout itself.
+	dockerRoot := projectRoot
+	if cfg.ProjectRoot != "" && !dsn.IsSQLite(cfg.Database.DSN) {
+		dockerRoot = cfg.ProjectRoot
+	}
+
 	// Get current branch name
-	branch, err := getCurrentBranch(projectRoot)
+	branch, err := getCurrentBranch(ctx, projectRoot)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current branch: %w", err)
 	}
@@ -101,7 +102,7 @@ func Switch(projectRoot string, cloneFrom string) (*SwitchResult, error) {
 	branchDB := generateBranchDBName(defaultDB, branch)
 
 	// Check if database exists (use dockerRoot for docker operations)
-	dbExists, err := databaseExists(cfg, dockerRoot, branchDB)
+	dbExists, err := databaseExists(ctx, cfg, dockerRoot, branchDB)
 	if err != nil {
 		return nil, err
 	}
@@ -113,20 +114,20 @@ func Switch(projectRoot string, cloneFrom string) (*SwitchResult, error) {
 
 	// Create database if it doesn't exist
 	if !dbExists {
-		if err := createBranchDB(cfg, dockerRoot, branchDB); err != nil {
+		if err := createBranchDB(ctx, cfg, dockerRoot, branchDB); err != nil {
 			return nil, err
 		}
 		result.Created = true
 
 		// Clone data if requested
 		if cloneFrom != "" {
-			if err := cloneDatabaseData(cfg, dockerRoot, cloneFrom, branchDB); err != nil {
+			if err := cloneDatabaseData(ctx, cfg, dockerRoot, cloneFrom, branchDB); err != nil {
 				return nil, err
 			}
 			result.Cloned = true
 		} else if branch != "main" && branch != "master" {
 			// Auto-clone from default db for feature branches
-			if err := cloneDatabaseData(cfg, dockerRoot, defaultDB, branchDB); err != nil {
+			if err := cloneDatabaseData(ctx, cfg, dockerRoot, defaultDB, branchDB); err != nil {
 				return nil, err
 			}
 			result.Cloned = true
@@ -141,8 +142,8 @@ func Switch(projectRoot string, cloneFrom string) (*SwitchResult, error) {
 	return result, nil
 }
 
-func getCurrentBranch(projectRoot string) (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
+func getCurrentBranch(ctx context.Context, projectRoot string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "branch", "--show-current")
 	cmd.Dir = projectRoot
 	output, err := cmd.Output()
 	if err != nil {
@@ -165,20 +166,25 @@ func generateBranchDBName(defaultDB, branch string) string {
 	return defaultDB + "_" + sanitized
 }
 
-func databaseExists(cfg *config.Config, projectRoot, dbName string) (bool, error) {
+func databaseExists(ctx context.Context, cfg *config.Config, projectRoot, dbName string) (bool, error) {
 	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
 	if err != nil {
 		return false, err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
-
-	result, err := dbExecutor.List(cfg.Database.Service, parsedDSN, parsedDSN.Database)
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
 	if err != nil {
 		return false, err
 	}
 
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	result, err := dbExecutor.List(opCtx, cfg.Database.Service, parsedDSN, parsedDSN.Database)
+	if err != nil {
+		return false, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
 	for _, db := range result.Databases {
 		if db.Name == dbName {
 			return true, nil
@@ -187,7 +193,7 @@ func databaseExists(cfg *config.Config, projectRoot, dbName string) (bool, error
 	return false, nil
 }
 
-func createBranchDB(cfg *config.Config, projectRoot, dbName string) error {
+func createBranchDB(ctx context.Context, cfg *config.Config, projectRoot, dbName string) error {
 	// Check if database is allowed
 	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
 		// Auto-add branch pattern to allowed if wildcard exists
@@ -208,16 +214,23 @@ func createBranchDB(cfg *config.Config, projectRoot, dbName string) error {
 		return err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return err
+	}
 
-	_, err = dbExecutor.Create(cfg.Database.Service, parsedDSN, dbName)
-	return err
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	if _, err := dbExecutor.Create(opCtx, cfg.Database.Service, parsedDSN, dbName); err != nil {
+		return interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+	return nil
 }
 
-func cloneDatabaseData(cfg *config.Config, projectRoot, sourceDB, targetDB string) error {
+func cloneDatabaseData(ctx context.Context, cfg *config.Config, projectRoot, sourceDB, targetDB string) error {
 	// Use the existing CloneDB command
-	_, err := CloneDB(projectRoot, sourceDB, targetDB)
+	_, err := CloneDB(ctx, projectRoot, sourceDB, targetDB, types.CloneOptions{})
 	return err
 }
 
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// cleanupTimeout bounds the cleanup that runs after an operation was cancelled
+const cleanupTimeout = time.Minute
+
+// withTimeout bounds ctx by the configured timeout of op, if any
+func withTimeout(ctx context.Context, cfg *config.Config, op config.Operation) (context.Context, context.CancelFunc) {
+	if d := cfg.Database.Timeouts.Duration(op); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// cleanupContext returns a context for undoing a partial operation that
+// outlives the cancellation of ctx
+func cleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+}
+
+// interruptedError replaces err with a CANCELED or TIMEOUT error when ctx
+// ended, since the failure is then only a consequence of the interruption
+func interruptedError(ctx context.Context, cfg *config.Config, op config.Operation, err error) error {
+	switch ctx.Err() {
+	case nil:
+		return err
+	case context.DeadlineExceeded:
+		message := fmt.Sprintf("%s timed out", op)
+		if d := cfg.Database.Timeouts.Duration(op); d > 0 {
+			message = fmt.Sprintf("%s timed out after %s (database.timeouts.%s)", op, d, op)
+		}
+		return &types.CommandError{Code: types.ErrTimeout, Message: message}
+	default:
+		return &types.CommandError{Code: types.ErrCanceled, Message: fmt.Sprintf("%s canceled", op)}
+	}
+}
@@ -1,8 +1,11 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -12,12 +15,14 @@ import (
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/hooks"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+	"gopkg.in/yaml.v3"
 )
 
-func Dump(projectRoot, dbName string, tables []string) (*types.DumpResult, error) {
+func Dump(ctx context.Context, projectRoot, dbName string, opts types.DumpOptions) (*types.DumpResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		return nil, err
@@ -30,30 +35,101 @@ func Dump(projectRoot, dbName string, tables []string) (*types.DumpResult, error
 		}
 	}
 
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
 	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
 		return nil, err
 	}
 
-	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err := validateTableFilter(opts.TableFilter); err != nil {
+		return nil, err
+	}
+
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
 	if err != nil {
 		return nil, err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
+	if opts.Anonymize {
+		anonymizer, err := newAnonymizer(cfg)
+		if err != nil {
+			return nil, err
+		}
+		dbExecutor.SetAnonymizer(anonymizer)
+	}
+	if opts.Progress != nil {
+		dbExecutor.SetProgressFunc(opts.Progress)
+	}
 
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpDump)
+	defer cancel()
 
-	destPath := filepath.Join(cfg.Database.DumpsPath,
-		fmt.Sprintf("%s_%s.sql", dbName, time.Now().Format("2006-01-02T15-04")))
+	destPath := filepath.Join(resolveDumpsPath(cfg, projectRoot),
+		fmt.Sprintf("%s_%s%s", dbName, time.Now().Format("2006-01-02T15-04"), cfg.Database.DumpCompression.Extension()))
 
 	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create dumps directory: %w", err)
 	}
 
-	return dbExecutor.Dump(cfg.Database.Service, parsedDSN, destPath, tables)
+	sourceDSN := *parsedDSN
+	sourceDSN.Database = dbName
+
+	result, err := dbExecutor.Dump(opCtx, cfg.Database.Service, &sourceDSN, destPath, opts.TableFilter)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpDump, err)
+	}
+	result.Anonymized = opts.Anonymize
+
+	meta := buildDumpMetadata(opCtx, dbExecutor, cfg.Database.Service, &sourceDSN, engine.Name(), projectRoot, opts.TableFilter, result)
+	if err := writeDumpMetadata(destPath, meta); err != nil {
+		redact.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		result.MetadataPath = dumpMetadataPath(destPath)
+	}
+
+	if retention := cfg.Database.Retention; retention != nil && retention.AutoPrune && !retention.IsEmpty() {
+		pruned, err := pruneDumps(resolveDumpsPath(cfg, projectRoot), retention, false, time.Now())
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Warning: auto-prune failed: %v\n", err)
+		} else {
+			result.Pruned = pruned.Removed
+		}
+	}
+
+	return result, nil
 }
 
-func CreateDB(projectRoot, dbName string) (*types.CreateResult, error) {
+// validateTableFilter rejects table filters that can't produce a dump
+func validateTableFilter(filter types.TableFilter) error {
+	if filter.SchemaOnly && filter.DataOnly {
+		return &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "schema_only and data_only can't be combined",
+		}
+	}
+
+	for _, list := range [][]string{filter.Tables, filter.ExcludeTables, filter.StructureOnly} {
+		for _, pattern := range list {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return &types.CommandError{
+					Code:    types.ErrConfigInvalid,
+					Message: fmt.Sprintf("invalid table name or pattern %q", pattern),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func CreateDB(ctx context.Context, projectRoot, dbName string) (*types.CreateResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		return nil, err
@@ -75,14 +151,29 @@ func CreateDB(projectRoot, dbName string) (*types.CreateResult, error) {
 		return nil, err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
 
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
 
-	return dbExecutor.Create(cfg.Database.Service, parsedDSN, dbName)
+	result, err := dbExecutor.Create(opCtx, cfg.Database.Service, parsedDSN, dbName)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	return result, nil
 }
 
-func ImportDB(projectRoot, dbName, sourcePath string) (*types.ImportResult, error) {
+// ImportDB imports the SQL file at sourcePath into dbName. The file is read
+// through and verified first, so an invalid dump never touches the target.
+// With tables set only the statements of the matching tables are imported,
+// the rest of dbName is left as it is. dbName defaults to the DSN database.
+// With database.backup_before_destructive set an existing dbName is dumped
+// into the trash first. progress, when set, receives the bytes imported so far.
+func ImportDB(ctx context.Context, projectRoot, dbName, sourcePath string, tables []string, progress types.ProgressFunc) (*types.ImportResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		return nil, err
@@ -95,10 +186,23 @@ func ImportDB(projectRoot, dbName, sourcePath string) (*types.ImportResult, erro
 		}
 	}
 
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
 	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
 		return nil, err
 	}
 
+	if err := validateTableFilter(types.TableFilter{Tables: tables}); err != nil {
+		return nil, err
+	}
+
 	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
 		return nil, &types.CommandError{
 			Code:    types.ErrFileNotFound,
@@ -106,19 +210,41 @@ func ImportDB(projectRoot, dbName, sourcePath string) (*types.ImportResult, erro
 		}
 	}
 
-	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	// A truncated or altered dump would leave the target half imported
+	if err := verifyBeforeImport(ctx, cfg, sourcePath, "import"); err != nil {
+		return nil, err
+	}
+
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
 	if err != nil {
 		return nil, err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
+	backup, err := backupBeforeDestructive(ctx, cfg, dbExecutor, engine, parsedDSN, projectRoot, dbName, trashImport)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set after the backup, which is no part of the import
+	if progress != nil {
+		dbExecutor.SetProgressFunc(progress)
+	}
 
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpImport)
+	defer cancel()
 
-	return dbExecutor.Import(cfg.Database.Service, parsedDSN, sourcePath, dbName)
+	result, err := dbExecutor.Import(opCtx, cfg.Database.Service, parsedDSN, sourcePath, dbName, tables)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpImport, err)
+	}
+	result.Backup = backup
+
+	return result, nil
 }
 
-func DropDB(projectRoot, dbName string) (*types.DropResult, error) {
+// DropDB drops dbName after the preDrop hooks agreed. With
+// database.backup_before_destructive set it is dumped into the trash first.
+func DropDB(ctx context.Context, projectRoot, dbName string) (*types.DropResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		return nil, err
@@ -153,19 +279,38 @@ func DropDB(projectRoot, dbName string) (*types.DropResult, error) {
 			DatabaseURL:  cfg.Database.DSN, // original DSN
 		}
 
-		if err := hookExec.ExecuteHooks(cfg.Database.Hooks.PreDrop, hookCtx, cfg.ProjectRoot, true); err != nil {
+		if err := hookExec.ExecuteHooks(ctx, cfg.Database.Hooks.PreDrop, hookCtx, cfg.ProjectRoot, true); err != nil {
 			return nil, fmt.Errorf("preDrop hook prevented drop: %w", err)
 		}
 	}
 
-	engine := getEngine(parsedDSN.Engine)
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := backupBeforeDestructive(ctx, cfg, dbExecutor, engine, parsedDSN, projectRoot, dbName, trashDrop)
+	if err != nil {
+		return nil, err
+	}
 
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	result, err := dbExecutor.Drop(opCtx, cfg.Database.Service, parsedDSN, dbName)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+	result.Backup = backup
 
-	return dbExecutor.Drop(cfg.Database.Service, parsedDSN, dbName)
+	return result, nil
 }
 
-func ListDBs(projectRoot string) (*types.DatabaseListResult, error) {
+// ListDBs lists the databases of the server. withStats adds the size, table
+// count, row estimate and last change of each database, which takes a query
+// per database. withMigrations compares each database with the migrations of
+// the project, which takes two more.
+func ListDBs(ctx context.Context, projectRoot string, withStats, withMigrations bool) (*types.DatabaseListResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		return nil, err
@@ -183,14 +328,59 @@ func ListDBs(projectRoot string) (*types.DatabaseListResult, error) {
 		return nil, err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
 
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	result, err := dbExecutor.List(opCtx, cfg.Database.Service, parsedDSN, parsedDSN.Database)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	if withStats {
+		for i := range result.Databases {
+			db := &result.Databases[i]
+			stats, err := dbExecutor.Stats(opCtx, cfg.Database.Service, parsedDSN, db.Name)
+			if err != nil {
+				if opCtx.Err() != nil {
+					return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+				}
+				// A database the user can't read shouldn't hide the others
+				redact.Fprintf(os.Stderr, "Warning: no statistics for %s: %v\n", db.Name, err)
+				continue
+			}
+			db.Stats = &stats.Stats
+		}
+	}
+
+	if withMigrations {
+		dir := migrationsDir(cfg, cfg.ProjectRoot)
+		available, err := findMigrations(dir)
+		if err != nil {
+			return nil, err
+		}
+		for i := range result.Databases {
+			db := &result.Databases[i]
+			status, err := migrationStatus(opCtx, cfg, dbExecutor, parsedDSN, db.Name, dir, available)
+			if err != nil {
+				if opCtx.Err() != nil {
+					return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+				}
+				redact.Fprintf(os.Stderr, "Warning: no migration status for %s: %v\n", db.Name, err)
+				continue
+			}
+			db.Migrations = status
+		}
+	}
 
-	return dbExecutor.List(cfg.Database.Service, parsedDSN, parsedDSN.Database)
+	return result, nil
 }
 
-func CloneDB(projectRoot, sourceDB, targetDB string) (*types.CloneResult, error) {
+func CloneDB(ctx context.Context, projectRoot, sourceDB, targetDB string, opts types.CloneOptions) (*types.CloneResult, error) {
 	start := time.Now()
 
 	cfg, err := config.Load(projectRoot)
@@ -222,35 +412,128 @@ func CloneDB(projectRoot, sourceDB, targetDB string) (*types.CloneResult, error)
 		return nil, err
 	}
 
-	engine := getEngine(parsedDSN.Engine)
-
-	dbExecutor := executor.NewDockerDatabaseExecutor(engine, cfg.Docker.ComposeFiles, projectRoot)
+	if err := validateTableFilter(opts.TableFilter); err != nil {
+		return nil, err
+	}
+	if opts.DataOnly {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "data_only can't be used for a clone: the target database is created empty and needs the table definitions",
+		}
+	}
 
-	_, err = dbExecutor.Create(cfg.Database.Service, parsedDSN, targetDB)
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create target database: %w", err)
+		return nil, err
 	}
 
-	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("clone_%s_%d.sql", targetDB, time.Now().UnixNano()))
+	if opts.Anonymize {
+		anonymizer, err := newAnonymizer(cfg)
+		if err != nil {
+			return nil, err
+		}
+		dbExecutor.SetAnonymizer(anonymizer)
+	}
 
-	sourceDSN := *parsedDSN
-	sourceDSN.Database = sourceDB
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpClone)
+	defer cancel()
 
-	dumpResult, err := dbExecutor.Dump(cfg.Database.Service, &sourceDSN, tmpFile, nil)
-	if err != nil {
-		dbExecutor.Drop(cfg.Database.Service, parsedDSN, targetDB)
-		os.Remove(tmpFile)
-		return nil, fmt.Errorf("failed to dump source database: %w", err)
+	// dropTarget removes the partially cloned target, even once opCtx is done
+	dropTarget := func() {
+		cleanupCtx, cancel := cleanupContext(opCtx)
+		defer cancel()
+		if _, err := dbExecutor.Drop(cleanupCtx, cfg.Database.Service, parsedDSN, targetDB); err != nil {
+			redact.Fprintf(os.Stderr, "Warning: failed to drop partial target database %s: %v\n", targetDB, err)
+		}
 	}
 
-	_, err = dbExecutor.Import(cfg.Database.Service, parsedDSN, tmpFile, targetDB)
-	if err != nil {
-		dbExecutor.Drop(cfg.Database.Service, parsedDSN, targetDB)
-		os.Remove(tmpFile)
-		return nil, fmt.Errorf("failed to import into target database: %w", err)
+	mode := cfg.Database.CloneStrategy
+	serverStrategies := serverCloneStrategies(mode)
+	if len(serverStrategies) > 0 && (opts.Anonymize || !opts.TableFilter.IsEmpty()) {
+		// Server-side strategies copy the database as it is
+		if mode == config.CloneTemplate || mode == config.CloneCopy {
+			redact.Fprintf(os.Stderr, "Warning: clone_strategy %s can't filter or anonymize tables, cloning via dump\n", mode)
+		}
+		serverStrategies = nil
+	}
+
+	var size int64
+	var strategy types.CloneStrategy
+	// created tells whether targetDB exists; only the template strategy creates it itself
+	created := false
+	for _, candidate := range serverStrategies {
+		if candidate == types.CloneStrategyTemplate {
+			err = dbExecutor.Template(opCtx, cfg.Database.Service, parsedDSN, sourceDB, targetDB)
+			created = err == nil
+		} else {
+			if !created {
+				if _, err := dbExecutor.Create(opCtx, cfg.Database.Service, parsedDSN, targetDB); err != nil {
+					return nil, interruptedError(opCtx, cfg, config.OpClone, fmt.Errorf("failed to create target database: %w", err))
+				}
+				created = true
+			}
+			size, err = dbExecutor.Copy(opCtx, cfg.Database.Service, parsedDSN, sourceDB, targetDB)
+		}
+		if err == nil {
+			strategy = candidate
+			break
+		}
+		if opCtx.Err() != nil {
+			if created {
+				dropTarget()
+			}
+			return nil, interruptedError(opCtx, cfg, config.OpClone, err)
+		}
+
+		unsupported := errors.Is(err, executor.ErrCloneUnsupported)
+		if created && !unsupported {
+			// Start over from an empty target so the partial copy does not linger
+			if _, err := dbExecutor.Drop(opCtx, cfg.Database.Service, parsedDSN, targetDB); err != nil {
+				return nil, interruptedError(opCtx, cfg, config.OpClone, fmt.Errorf("failed to reset target database: %w", err))
+			}
+			created = false
+		}
+		if unsupported {
+			// Auto just moves on to the strategies the engine has
+			if mode != config.CloneAuto && mode != "" {
+				redact.Fprintf(os.Stderr, "Warning: clone_strategy %s is not supported by %s, cloning via dump\n", mode, engine.Name())
+			}
+		} else {
+			redact.Fprintf(os.Stderr, "Warning: %s clone failed, falling back: %v\n", candidate, err)
+		}
+	}
+
+	if strategy == "" {
+		if !created {
+			if _, err := dbExecutor.Create(opCtx, cfg.Database.Service, parsedDSN, targetDB); err != nil {
+				return nil, interruptedError(opCtx, cfg, config.OpClone, fmt.Errorf("failed to create target database: %w", err))
+			}
+		}
+		strategy = types.CloneStrategyPipe
+		size, err = dbExecutor.Pipe(opCtx, cfg.Database.Service, parsedDSN, sourceDB, targetDB, opts.TableFilter)
 	}
+	if err != nil && opCtx.Err() != nil {
+		dropTarget()
+		return nil, interruptedError(opCtx, cfg, config.OpClone, err)
+	}
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Warning: direct clone failed, retrying via temporary dump file: %v\n", err)
 
-	os.Remove(tmpFile)
+		// Start over from an empty target so the partial pipe import does not linger
+		if _, err := dbExecutor.Drop(opCtx, cfg.Database.Service, parsedDSN, targetDB); err != nil {
+			return nil, interruptedError(opCtx, cfg, config.OpClone, fmt.Errorf("failed to reset target database: %w", err))
+		}
+		if _, err := dbExecutor.Create(opCtx, cfg.Database.Service, parsedDSN, targetDB); err != nil {
+			return nil, interruptedError(opCtx, cfg, config.OpClone, fmt.Errorf("failed to create target database: %w", err))
+		}
+
+		strategy = types.CloneStrategyFile
+		size, err = cloneViaFile(opCtx, dbExecutor, cfg.Database.Service, parsedDSN, sourceDB, targetDB, opts.TableFilter)
+		if err != nil {
+			dropTarget()
+			return nil, interruptedError(opCtx, cfg, config.OpClone, err)
+		}
+	}
 
 	// Run postClone hooks
 	if cfg.Database.Hooks != nil && len(cfg.Database.Hooks.PostClone) > 0 {
@@ -268,19 +551,57 @@ func CloneDB(projectRoot, sourceDB, targetDB string) (*types.CloneResult, error)
 			TargetDatabase: targetDB,
 		}
 
-		if err := hookExec.ExecuteHooks(cfg.Database.Hooks.PostClone, hookCtx, cfg.ProjectRoot, false); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: postClone hook failed: %v\n", err)
+		if err := hookExec.ExecuteHooks(ctx, cfg.Database.Hooks.PostClone, hookCtx, cfg.ProjectRoot, false); err != nil {
+			redact.Fprintf(os.Stderr, "Warning: postClone hook failed: %v\n", err)
 		}
 	}
 
 	return &types.CloneResult{
-		Source:   sourceDB,
-		Target:   targetDB,
-		Size:     dumpResult.Size,
-		Duration: time.Since(start),
+		Source:     sourceDB,
+		Target:     targetDB,
+		Strategy:   strategy,
+		Size:       size,
+		Anonymized: opts.Anonymize,
+		Duration:   time.Since(start),
 	}, nil
 }
 
+// serverCloneStrategies returns the server-side strategies to try, in order,
+// for database.clone_strategy. Auto tries them all; the executor skips those
+// its engine lacks.
+func serverCloneStrategies(mode config.CloneMode) []types.CloneStrategy {
+	switch mode {
+	case config.CloneDump:
+		return nil
+	case config.CloneTemplate:
+		return []types.CloneStrategy{types.CloneStrategyTemplate}
+	case config.CloneCopy:
+		return []types.CloneStrategy{types.CloneStrategyCopy}
+	}
+	return []types.CloneStrategy{types.CloneStrategyTemplate, types.CloneStrategyCopy}
+}
+
+// cloneViaFile copies sourceDB into targetDB through a temporary SQL file.
+// The caller is responsible for creating and, on failure, dropping targetDB.
+func cloneViaFile(ctx context.Context, dbExecutor executor.DatabaseExecutor, service string, parsedDSN *types.DSN, sourceDB, targetDB string, filter types.TableFilter) (int64, error) {
+	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("clone_%s_%d.sql", targetDB, time.Now().UnixNano()))
+	defer os.Remove(tmpFile)
+
+	sourceDSN := *parsedDSN
+	sourceDSN.Database = sourceDB
+
+	dumpResult, err := dbExecutor.Dump(ctx, service, &sourceDSN, tmpFile, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dump source database: %w", err)
+	}
+
+	if _, err := dbExecutor.Import(ctx, service, parsedDSN, tmpFile, targetDB, nil); err != nil {
+		return 0, fmt.Errorf("failed to import into target database: %w", err)
+	}
+
+	return dumpResult.Size, nil
+}
+
 func ListDumps(projectRoot string) (*types.DumpsListResult, error) {
 	cfg, err := config.Load(projectRoot)
 	if err != nil {
@@ -294,33 +615,53 @@ func ListDumps(projectRoot string) (*types.DumpsListResult, error) {
 		}
 	}
 
-	dumpsPath := cfg.Database.DumpsPath
-	if dumpsPath == "" {
-		dumpsPath = "var/dumps"
+	dumps, err := listDumpFiles(resolveDumpsPath(cfg, projectRoot))
+	if err != nil {
+		return nil, err
 	}
 
-	if !filepath.IsAbs(dumpsPath) {
-		dumpsPath = filepath.Join(projectRoot, dumpsPath)
-	}
+	return &types.DumpsListResult{Dumps: dumps}, nil
+}
 
+// listDumpFiles returns the dumps directly inside dumpsPath, newest first
+func listDumpFiles(dumpsPath string) ([]types.DumpFileInfo, error) {
 	if _, err := os.Stat(dumpsPath); os.IsNotExist(err) {
-		return &types.DumpsListResult{Dumps: []types.DumpFileInfo{}}, nil
+		return []types.DumpFileInfo{}, nil
 	}
 
-	files, err := filepath.Glob(filepath.Join(dumpsPath, "*.sql"))
+	entries, err := os.ReadDir(dumpsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read dumps directory: %w", err)
 	}
 
 	var dumps []types.DumpFileInfo
-	for _, file := range files {
-		info, err := os.Stat(file)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
 		if err != nil {
 			continue
 		}
 
-		filename := filepath.Base(file)
-		dbName, _ := parseDumpFilename(filename)
+		filename := entry.Name()
+		if _, _, ok := types.ParseDumpFilename(filename); !ok {
+			continue
+		}
+
+		meta, err := readDumpMetadata(filepath.Join(dumpsPath, filename))
+		if err != nil {
+			redact.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		// Prefer the recorded database; the filename is ambiguous for names containing "_"
+		dbName := ""
+		if meta != nil {
+			dbName = meta.Database
+		} else {
+			dbName, _ = parseDumpFilename(filename)
+		}
 		if dbName == "" {
 			continue
 		}
@@ -330,6 +671,7 @@ func ListDumps(projectRoot string) (*types.DumpsListResult, error) {
 			Database: dbName,
 			Size:     info.Size(),
 			Modified: info.ModTime().Format(time.RFC3339),
+			Metadata: meta,
 		})
 	}
 
@@ -337,17 +679,30 @@ func ListDumps(projectRoot string) (*types.DumpsListResult, error) {
 		return dumps[i].Modified > dumps[j].Modified
 	})
 
-	return &types.DumpsListResult{Dumps: dumps}, nil
+	return dumps, nil
+}
+
+// resolveDumpsPath returns the absolute dumps directory, relative paths being
+// taken from the project root
+func resolveDumpsPath(cfg *config.Config, projectRoot string) string {
+	dumpsPath := cfg.Database.DumpsPath
+	if dumpsPath == "" {
+		dumpsPath = "var/dumps"
+	}
+
+	if !filepath.IsAbs(dumpsPath) {
+		dumpsPath = filepath.Join(projectRoot, dumpsPath)
+	}
+
+	return dumpsPath
 }
 
 func parseDumpFilename(filename string) (dbName, timestamp string) {
-	ext := filepath.Ext(filename)
-	if ext != ".sql" {
+	name, _, ok := types.ParseDumpFilename(filename)
+	if !ok {
 		return "", ""
 	}
 
-	name := strings.TrimSuffix(filename, ext)
-
 	idx := strings.LastIndex(name, "_")
 	if idx == -1 {
 		return "", ""
@@ -359,9 +714,95 @@ func parseDumpFilename(filename string) (dbName, timestamp string) {
 	return dbName, timestamp
 }
 
+// newDatabaseExecutor returns the executor for the configured database and its
+// engine: the compose service, the client tools on the host, or the database
+// files of a SQLite DSN. It fails if no compose runtime is available.
+func newDatabaseExecutor(cfg *config.Config, parsedDSN *types.DSN, projectRoot string) (executor.DatabaseExecutor, engines.DatabaseEngine, error) {
+	if parsedDSN.Engine == "sqlite" {
+		file := sqliteFile(parsedDSN, projectRoot)
+		engine := engines.NewSQLiteEngine(filepath.Dir(file), filepath.Ext(file))
+		return executor.NewFileDatabaseExecutor(engine, projectRoot), engine, nil
+	}
+
+	var composeFiles []string
+	if cfg.Docker != nil {
+		composeFiles = cfg.Docker.ComposeFiles
+	}
+	engine := getEngine(parsedDSN.Engine)
+	if executorMode(cfg, projectRoot, composeFiles) == config.ExecutorHost {
+		return executor.NewHostDatabaseExecutor(engine, projectRoot), engine, nil
+	}
+
+	compose, err := newCompose(cfg, composeFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return executor.NewDockerDatabaseExecutor(engine, compose, projectRoot), engine, nil
+}
+
+// newCompose returns the compose command builder of the configured runtime
+// for the given compose files
+func newCompose(cfg *config.Config, composeFiles []string) (executor.Compose, error) {
+	var runtime types.ComposeRuntime
+	if cfg.Docker != nil {
+		runtime = cfg.Docker.Runtime
+	}
+	return executor.NewCompose(runtime, composeFiles)
+}
+
+// executorMode resolves database.executor. Auto picks the host when there is
+// no service or the compose files don't define it; compose files that can't be
+// read leave the decision to docker compose.
+func executorMode(cfg *config.Config, projectRoot string, composeFiles []string) config.ExecutorMode {
+	switch cfg.Database.Executor {
+	case config.ExecutorDocker, config.ExecutorHost:
+		return cfg.Database.Executor
+	}
+
+	if cfg.Database.Service == "" {
+		return config.ExecutorHost
+	}
+
+	parsed := false
+	for _, composeFile := range composeFiles {
+		data, err := os.ReadFile(filepath.Join(projectRoot, composeFile))
+		if err != nil {
+			continue
+		}
+		var dc DockerCompose
+		if err := yaml.Unmarshal(data, &dc); err != nil {
+			continue
+		}
+		parsed = true
+		if _, ok := dc.Services[cfg.Database.Service]; ok {
+			return config.ExecutorDocker
+		}
+	}
+
+	if parsed {
+		return config.ExecutorHost
+	}
+	return config.ExecutorDocker
+}
+
+// sqliteFile returns the absolute path of the database file of a SQLite DSN.
+// %kernel.project_dir% and relative paths are resolved against projectRoot,
+// so inside a worktree the DSN points at the worktree's own copy.
+func sqliteFile(parsedDSN *types.DSN, projectRoot string) string {
+	file := strings.ReplaceAll(parsedDSN.Path, "%kernel.project_dir%", projectRoot)
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(projectRoot, file)
+	}
+	return filepath.Clean(file)
+}
+
 func getEngine(engineType string) engines.DatabaseEngine {
-	if engineType == "mariadb" {
+	switch engineType {
+	case "postgresql":
+		return engines.NewPostgresEngine()
+	case "mariadb":
 		return engines.NewMySQLEngine(true)
+	default:
+		return engines.NewMySQLEngine(false)
 	}
-	return engines.NewMySQLEngine(false)
 }
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/schemadiff"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// DiffSchemas compares the tables, columns, indexes and foreign keys of two
+// databases. fromDB defaults to the DSN database, and both must be allowed.
+// The changes, and with withStatements the statements applying them, turn the
+// schema of fromDB into that of toDB. Nothing is changed on the server.
+func DiffSchemas(ctx context.Context, projectRoot, fromDB, toDB string, withStatements bool) (*types.SchemaDiffResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for diff operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromDB == "" {
+		fromDB = parsedDSN.Database
+	}
+
+	if err := core.IsDatabaseAllowed(fromDB, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	if err := core.IsDatabaseAllowed(toDB, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	fromSchema, err := dbExecutor.Schema(opCtx, cfg.Database.Service, parsedDSN, fromDB)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	toSchema, err := dbExecutor.Schema(opCtx, cfg.Database.Service, parsedDSN, toDB)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	result := &types.SchemaDiffResult{
+		From:    fromDB,
+		To:      toDB,
+		Changes: schemadiff.Diff(fromSchema, toSchema),
+	}
+	if result.Changes == nil {
+		result.Changes = []types.SchemaChange{}
+	}
+	if withStatements {
+		result.Statements = engine.BuildAlterStatements(result.Changes)
+	}
+
+	return result, nil
+}
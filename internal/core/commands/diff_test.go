@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+const diffDocker = `case "$*" in
+  *"TABLE_SCHEMA = 'app_feature'"*) printf '%s\n' \
+    'column	users	id	int	NO	0		auto_increment' \
+    'column	users	email	varchar(255)	NO	0		' \
+    'column	tags	name	varchar(64)	NO	0		' \
+    'index	users	PRIMARY	primary	id	BTREE		' ;;
+  *"TABLE_SCHEMA = 'app'"*) printf '%s\n' \
+    'column	users	id	int	NO	0		auto_increment' \
+    'column	users	email	varchar(180)	NO	0		' \
+    'column	users	legacy	tinyint(1)	YES	0		' \
+    'index	users	PRIMARY	primary	id	BTREE		' ;;
+esac`
+
+func TestDiffSchemas(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, diffDocker)
+
+	result, err := DiffSchemas(context.Background(), projectRoot, "", "app_feature", true)
+	if err != nil {
+		t.Fatalf("DiffSchemas() error = %v", err)
+	}
+
+	if result.From != "app" || result.To != "app_feature" {
+		t.Errorf("expected app against app_feature, got %s against %s", result.From, result.To)
+	}
+
+	var kinds []string
+	for _, change := range result.Changes {
+		kinds = append(kinds, string(change.Kind)+" "+change.Table+"."+change.Name)
+	}
+	expected := []string{"add_table tags.", "modify_column users.email", "drop_column users.legacy"}
+	if strings.Join(kinds, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("expected changes %v, got %v", expected, kinds)
+	}
+
+	statements := strings.Join(result.Statements, "\n")
+	for _, statement := range []string{
+		"ALTER TABLE `users` DROP COLUMN `legacy`",
+		"CREATE TABLE `tags` (\n  `name` varchar(64) NOT NULL\n)",
+		"ALTER TABLE `users` MODIFY COLUMN `email` varchar(255) NOT NULL",
+	} {
+		if !strings.Contains(statements, statement) {
+			t.Errorf("expected statement %q, got:\n%s", statement, statements)
+		}
+	}
+
+	calls, _ := os.ReadFile(logPath)
+	if strings.Contains(string(calls), "ALTER TABLE") {
+		t.Errorf("expected the statements not to be run, got calls:\n%s", calls)
+	}
+}
+
+func TestDiffSchemasIdentical(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	fakeDocker(t, diffDocker)
+
+	result, err := DiffSchemas(context.Background(), projectRoot, "app", "app", false)
+	if err != nil {
+		t.Fatalf("DiffSchemas() error = %v", err)
+	}
+	if result.Changes == nil || len(result.Changes) != 0 || result.Statements != nil {
+		t.Errorf("expected no changes and no statements, got %+v", result)
+	}
+}
+
+func TestDiffSchemasNotAllowed(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, diffDocker)
+
+	for _, dbs := range [][2]string{{"app", "other"}, {"other", "app"}} {
+		_, err := DiffSchemas(context.Background(), projectRoot, dbs[0], dbs[1], false)
+		if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrDbNotAllowed {
+			t.Errorf("DiffSchemas(%s, %s) expected ErrDbNotAllowed, got %v", dbs[0], dbs[1], err)
+		}
+	}
+
+	if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+		t.Errorf("expected no docker calls, got:\n%s", calls)
+	}
+}
+
+func TestDiffSchemasSQLiteStatementsApply(t *testing.T) {
+	projectRoot := setupSQLiteProject(t)
+	ctx := context.Background()
+
+	feature := "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL DEFAULT '');" +
+		"CREATE INDEX idx_users_email ON users (email);" +
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id) ON DELETE CASCADE, UNIQUE (user_id, id));"
+	if out, err := exec.Command("sqlite3", filepath.Join(projectRoot, "var", "data_feature.db"), feature).CombinedOutput(); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+
+	result, err := DiffSchemas(ctx, projectRoot, "data", "data_feature", true)
+	if err != nil {
+		t.Fatalf("DiffSchemas() error = %v", err)
+	}
+	if len(result.Changes) != 3 {
+		t.Fatalf("expected a new table, column and index, got %+v", result.Changes)
+	}
+
+	script := strings.Join(result.Statements, ";\n") + ";"
+	if out, err := exec.Command("sqlite3", "-bail", filepath.Join(projectRoot, "var", "data.db"), script).CombinedOutput(); err != nil {
+		t.Fatalf("applying the statements failed: %v: %s\n%s", err, out, script)
+	}
+
+	result, err = DiffSchemas(ctx, projectRoot, "data", "data_feature", false)
+	if err != nil {
+		t.Fatalf("DiffSchemas() after applying error = %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("expected identical schemas after applying the statements, got %+v", result.Changes)
+	}
+}
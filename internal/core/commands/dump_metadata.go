@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+)
+
+// dumpMetadataSuffix is appended to a dump filename to name its metadata sidecar
+const dumpMetadataSuffix = ".meta.json"
+
+func dumpMetadataPath(dumpPath string) string {
+	return dumpPath + dumpMetadataSuffix
+}
+
+// buildDumpMetadata describes a finished dump. Server version, table list and
+// git details are best effort: a failed lookup leaves the field empty, and
+// table patterns are recorded as given when the tables can't be listed.
+func buildDumpMetadata(ctx context.Context, dbExecutor executor.DatabaseExecutor, service string, parsedDSN *types.DSN, engineName, projectRoot string, filter types.TableFilter, result *types.DumpResult) *types.DumpMetadata {
+	meta := &types.DumpMetadata{
+		Database:      result.Database,
+		Engine:        engineName,
+		Tables:        filter.Tables,
+		ExcludeTables: filter.ExcludeTables,
+		StructureOnly: filter.StructureOnly,
+		SchemaOnly:    filter.SchemaOnly,
+		DataOnly:      filter.DataOnly,
+		Anonymized:    result.Anonymized,
+		Size:          result.Size,
+		Checksum:      result.Checksum,
+		ToolVersion:   core.Version,
+		Duration:      result.Duration,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if _, compression, ok := types.ParseDumpFilename(filepath.Base(result.Path)); ok {
+		meta.Compression = compression
+	}
+
+	if version, err := dbExecutor.ServerVersion(ctx, service, parsedDSN); err == nil {
+		meta.ServerVersion = version
+	}
+
+	if all, err := dbExecutor.Tables(ctx, service, parsedDSN, result.Database); err == nil {
+		included := all
+		if len(filter.Tables) > 0 {
+			included = executor.ExpandTablePatterns(filter.Tables, all)
+		}
+		meta.ExcludeTables = executor.ExpandTablePatterns(filter.ExcludeTables, all)
+		meta.StructureOnly = executor.ExpandTablePatterns(filter.StructureOnly, all)
+
+		excluded := make(map[string]bool, len(meta.ExcludeTables))
+		for _, table := range meta.ExcludeTables {
+			excluded[table] = true
+		}
+		meta.Tables = nil
+		for _, table := range included {
+			if !excluded[table] {
+				meta.Tables = append(meta.Tables, table)
+			}
+		}
+	}
+
+	if branch, commit, err := executor.GitHead(ctx, projectRoot); err == nil {
+		meta.GitBranch = branch
+		meta.GitCommit = commit
+	}
+
+	return meta
+}
+
+func writeDumpMetadata(dumpPath string, meta *types.DumpMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dump metadata: %w", err)
+	}
+
+	if err := os.WriteFile(dumpMetadataPath(dumpPath), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write dump metadata: %w", err)
+	}
+
+	return nil
+}
+
+// readDumpMetadata loads the sidecar of dumpPath. It returns nil without an
+// error when the dump has no sidecar, e.g. because it predates metadata.
+func readDumpMetadata(dumpPath string) (*types.DumpMetadata, error) {
+	data, err := os.ReadFile(dumpMetadataPath(dumpPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump metadata: %w", err)
+	}
+
+	var meta types.DumpMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse dump metadata %s: %w", dumpMetadataPath(dumpPath), err)
+	}
+
+	return &meta, nil
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// InspectDump reads a dump in the dumps directory and reports its tables with
+// their row counts, sizes and CREATE statements, and what its header tells
+// about the dumped server. file is relative to the dumps directory, as listed
+// by ListDumps. No database is needed.
+func InspectDump(ctx context.Context, projectRoot, file string) (*types.DumpInspectResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for dump inspect operations",
+		}
+	}
+
+	dumpsPath := resolveDumpsPath(cfg, projectRoot)
+	path := filepath.Join(dumpsPath, file)
+	if err := core.CheckPathTraversal(path, dumpsPath); err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil || !stat.Mode().IsRegular() || !isDumpFile(path) {
+		return nil, &types.CommandError{
+			Code:    types.ErrFileNotFound,
+			Message: fmt.Sprintf("dump file not found: %s", file),
+		}
+	}
+
+	meta, err := readDumpMetadata(path)
+	if err != nil {
+		redact.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	reader, err := executor.OpenDump(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	summary, err := sqldump.Inspect(reader, dumpDialect(cfg, meta))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, &types.CommandError{Code: types.ErrCanceled, Message: "dump inspect canceled"}
+		}
+		return nil, fmt.Errorf("failed to read dump file: %w", err)
+	}
+
+	result := &types.DumpInspectResult{
+		Name:          filepath.ToSlash(filepath.Clean(file)),
+		Path:          path,
+		Size:          stat.Size(),
+		SQLSize:       summary.Size,
+		Engine:        summary.Engine,
+		DumpTool:      summary.Tool,
+		ServerVersion: summary.ServerVersion,
+		Charset:       summary.Charset,
+		Tables:        make([]types.DumpTableInfo, 0, len(summary.Tables)),
+		Metadata:      meta,
+	}
+	if result.Engine == "" && meta != nil {
+		result.Engine = meta.Engine
+	}
+	if result.ServerVersion == "" && meta != nil {
+		result.ServerVersion = meta.ServerVersion
+	}
+	for _, table := range summary.Tables {
+		result.Tables = append(result.Tables, types.DumpTableInfo{
+			Name:   table.Name,
+			Rows:   table.Rows,
+			Bytes:  table.Bytes,
+			Create: table.Create,
+		})
+	}
+
+	return result, nil
+}
+
+// dumpDialect returns the dialect of a dump whose header doesn't name its
+// dump tool: that of the engine its metadata records, or else of the
+// configured database
+func dumpDialect(cfg *config.Config, meta *types.DumpMetadata) sqldump.Dialect {
+	if meta != nil {
+		known := []engines.DatabaseEngine{
+			engines.NewMySQLEngine(false),
+			engines.NewMySQLEngine(true),
+			engines.NewPostgresEngine(),
+			engines.NewSQLiteEngine("", ""),
+		}
+		for _, engine := range known {
+			if engine.Name() == meta.Engine {
+				return engine.Dialect()
+			}
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	switch {
+	case err != nil:
+		return sqldump.DialectMySQL
+	case parsedDSN.Engine == "sqlite":
+		return sqldump.DialectPostgres
+	default:
+		return getEngine(parsedDSN.Engine).Dialect()
+	}
+}
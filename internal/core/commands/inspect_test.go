@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func TestInspectDump(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, `exit 0`)
+	dumpsPath := filepath.Join(projectRoot, "var", "dumps")
+
+	writeDumpFile(t, dumpsPath, "app_2024-05-01T10-00.sql.gz", completeMySQLDump, true)
+
+	result, err := InspectDump(context.Background(), projectRoot, "app_2024-05-01T10-00.sql.gz")
+	if err != nil {
+		t.Fatalf("InspectDump() error = %v", err)
+	}
+
+	if result.Engine != "MySQL" || !strings.HasPrefix(result.DumpTool, "MySQL dump 10.13") {
+		t.Errorf("engine = %q, tool = %q", result.Engine, result.DumpTool)
+	}
+	if result.SQLSize != int64(len(completeMySQLDump)) || result.Size == 0 {
+		t.Errorf("size = %d, sql size = %d", result.Size, result.SQLSize)
+	}
+	if result.Metadata == nil || result.Metadata.Database != "app" {
+		t.Errorf("metadata = %+v", result.Metadata)
+	}
+	if len(result.Tables) != 1 {
+		t.Fatalf("tables = %+v", result.Tables)
+	}
+	if users := result.Tables[0]; users.Name != "users" || users.Rows != 2 || users.Create != "CREATE TABLE `users` (`id` int);" {
+		t.Errorf("users = %+v", users)
+	}
+
+	if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+		t.Errorf("docker called for an inspection:\n%s", calls)
+	}
+}
+
+func TestInspectDump_FallsBackToMetadataDialect(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	dumpsPath := filepath.Join(projectRoot, "var", "dumps")
+
+	// Without a header the backslash only escapes the quote in MySQL
+	path := writeDumpFile(t, dumpsPath, "snapshots/app/seeded.sql", "INSERT INTO t VALUES ('a\\'),('b');\n", false)
+	if err := writeDumpMetadata(path, &types.DumpMetadata{Database: "app", Engine: "PostgreSQL"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := InspectDump(context.Background(), projectRoot, "snapshots/app/seeded.sql")
+	if err != nil {
+		t.Fatalf("InspectDump() error = %v", err)
+	}
+	if result.Engine != "PostgreSQL" || len(result.Tables) != 1 || result.Tables[0].Rows != 2 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestInspectDump_Errors(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	writeDumpFile(t, filepath.Join(projectRoot, "var"), "outside.sql", completeMySQLDump, false)
+
+	tests := []struct {
+		name string
+		file string
+		code types.ErrCode
+	}{
+		{"missing dump", "app_1.sql", types.ErrFileNotFound},
+		{"outside of the dumps directory", "../outside.sql", types.ErrPathTraversal},
+		{"not a dump file", "app_1.txt", types.ErrFileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := InspectDump(context.Background(), projectRoot, tt.file)
+			cmdErr, ok := err.(*types.CommandError)
+			if !ok || cmdErr.Code != tt.code {
+				t.Errorf("expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
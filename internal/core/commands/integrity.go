@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// VerifyDumps checks every dump, snapshot and volume snapshot in the dumps
+// directory. Dumps are read through and compared with the checksum in their
+// metadata and checked for the footer of their dump tool, volume snapshots
+// are read as archives. Invalid files are reported, not removed.
+func VerifyDumps(ctx context.Context, projectRoot string) (*types.DumpVerifyResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for dumps verify operations",
+		}
+	}
+
+	dumpsPath := resolveDumpsPath(cfg, projectRoot)
+	var paths []string
+	err = filepath.WalkDir(dumpsPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dumpsPath && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.Type().IsRegular() && (isVolumeArchive(path) || isDumpFile(path)) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dumps directory: %w", err)
+	}
+	sort.Strings(paths)
+
+	result := &types.DumpVerifyResult{Dumps: []types.DumpVerification{}}
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return nil, &types.CommandError{Code: types.ErrCanceled, Message: "dumps verify canceled"}
+		}
+
+		var verification types.DumpVerification
+		if isVolumeArchive(path) {
+			verification = verifyVolumeArchive(path)
+		} else {
+			verification = verifyDump(ctx, path)
+		}
+		if ctx.Err() != nil {
+			return nil, &types.CommandError{Code: types.ErrCanceled, Message: "dumps verify canceled"}
+		}
+		if rel, err := filepath.Rel(dumpsPath, path); err == nil {
+			verification.Name = filepath.ToSlash(rel)
+		}
+		if !verification.Valid {
+			result.Invalid++
+		}
+		result.Dumps = append(result.Dumps, verification)
+	}
+
+	return result, nil
+}
+
+func isDumpFile(path string) bool {
+	_, _, ok := types.ParseDumpFilename(filepath.Base(path))
+	return ok
+}
+
+func isVolumeArchive(path string) bool {
+	return strings.HasSuffix(path, volumeArchiveExt)
+}
+
+// verifyDump reads the dump at path through and checks it against the
+// checksum in its metadata and for the footer of its dump tool. A file that
+// neither has metadata nor starts like the output of a dump tool, e.g. a
+// hand-written fixture, is only checked to be readable. Once ctx is done the
+// dump is reported unreadable; callers tell that apart by ctx.Err().
+func verifyDump(ctx context.Context, path string) types.DumpVerification {
+	verification := types.DumpVerification{Name: filepath.Base(path), Path: path, Checks: []string{}}
+	if stat, err := os.Stat(path); err == nil {
+		verification.Size = stat.Size()
+	}
+
+	meta, err := readDumpMetadata(path)
+	if err != nil {
+		verification.Problem = err.Error()
+		return verification
+	}
+
+	contents, err := executor.ReadDump(ctx, path)
+	if err != nil {
+		verification.Problem = err.Error()
+		return verification
+	}
+	verification.Checks = append(verification.Checks, "readable")
+
+	if meta != nil && meta.Checksum != "" {
+		verification.Checks = append(verification.Checks, "checksum")
+		if contents.Checksum != meta.Checksum {
+			verification.Problem = fmt.Sprintf("checksum %s does not match the recorded %s", contents.Checksum, meta.Checksum)
+			return verification
+		}
+	}
+
+	if markers := dumpMarkers(meta, contents.Head); markers.Footer != "" {
+		verification.Checks = append(verification.Checks, "complete")
+		if !markers.Complete(contents.Tail) {
+			verification.Problem = fmt.Sprintf("dump is truncated, it does not end with %q", markers.Footer)
+			return verification
+		}
+	}
+
+	verification.Valid = true
+	return verification
+}
+
+// verifyBeforeImport refuses the dump at path when verifyDump finds it
+// invalid, before verb imports it. Reading a dump of many GB takes a while,
+// so it runs under the import timeout and stops once ctx is done.
+func verifyBeforeImport(ctx context.Context, cfg *config.Config, path, verb string) error {
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpImport)
+	defer cancel()
+
+	verification := verifyDump(opCtx, path)
+	if opCtx.Err() != nil {
+		return interruptedError(opCtx, cfg, config.OpImport, opCtx.Err())
+	}
+	if !verification.Valid {
+		return &types.CommandError{
+			Code:    types.ErrDumpInvalid,
+			Message: fmt.Sprintf("refusing to %s %s: %s", verb, path, verification.Problem),
+		}
+	}
+	return nil
+}
+
+func verifyVolumeArchive(path string) types.DumpVerification {
+	verification := types.DumpVerification{Name: filepath.Base(path), Path: path, Checks: []string{"archive"}}
+	if stat, err := os.Stat(path); err == nil {
+		verification.Size = stat.Size()
+	}
+
+	if err := executor.VerifyArchive(path); err != nil {
+		verification.Problem = err.Error()
+		return verification
+	}
+
+	verification.Valid = true
+	return verification
+}
+
+// dumpMarkers returns the markers a dump must carry: those of the engine its
+// metadata records, or else of the dump tool whose header it starts with
+func dumpMarkers(meta *types.DumpMetadata, head []byte) engines.DumpMarkers {
+	known := []engines.DatabaseEngine{
+		engines.NewMySQLEngine(false),
+		engines.NewMySQLEngine(true),
+		engines.NewPostgresEngine(),
+	}
+
+	for _, engine := range known {
+		markers := engine.DumpMarkers()
+		if meta != nil && meta.Engine == engine.Name() || meta == nil && markers.IsDump(head) {
+			return markers
+		}
+	}
+	return engines.DumpMarkers{}
+}
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+const completeMySQLDump = "-- MySQL dump 10.13  Distrib 8.0.36\n" +
+	"CREATE TABLE `users` (`id` int);\n" +
+	"INSERT INTO `users` VALUES (1),(2);\n" +
+	"-- Dump completed on 2024-05-01 10:00:00\n"
+
+// writeDumpFile writes content to dumps/name, gzipped for a .gz name, and a
+// metadata sidecar with the matching checksum if withMeta is set
+func writeDumpFile(t *testing.T, dumpsPath, name, content string, withMeta bool) string {
+	t.Helper()
+	path := filepath.Join(dumpsPath, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasSuffix(name, ".gz") {
+		gz := gzip.NewWriter(file)
+		gz.Write([]byte(content))
+		gz.Close()
+	} else {
+		file.Write([]byte(content))
+	}
+	file.Close()
+
+	if withMeta {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		meta := &types.DumpMetadata{Database: "app", Engine: "MySQL", Checksum: "sha256:" + hex.EncodeToString(sum[:])}
+		if err := writeDumpMetadata(path, meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestVerifyDump(t *testing.T) {
+	dumpsPath := t.TempDir()
+	truncated := completeMySQLDump[:strings.Index(completeMySQLDump, "(2)")]
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		meta    bool
+		valid   bool
+		checks  string
+		problem string
+	}{
+		{"complete dump with metadata", "app_1.sql.gz", completeMySQLDump, true, true, "readable,checksum,complete", ""},
+		{"complete dump without metadata", "app_2.sql", completeMySQLDump, false, true, "readable,complete", ""},
+		{"truncated dump", "app_3.sql", truncated, false, false, "readable,complete", "truncated"},
+		{"hand-written fixture", "fixture.sql", "INSERT INTO users VALUES (1);\n", false, true, "readable", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeDumpFile(t, dumpsPath, tt.file, tt.content, tt.meta)
+
+			verification := verifyDump(context.Background(), path)
+			if verification.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v (problem %q)", verification.Valid, tt.valid, verification.Problem)
+			}
+			if checks := strings.Join(verification.Checks, ","); checks != tt.checks {
+				t.Errorf("Checks = %s, want %s", checks, tt.checks)
+			}
+			if !strings.Contains(verification.Problem, tt.problem) {
+				t.Errorf("Problem = %q, want it to contain %q", verification.Problem, tt.problem)
+			}
+		})
+	}
+
+	t.Run("altered dump", func(t *testing.T) {
+		path := writeDumpFile(t, dumpsPath, "app_4.sql", completeMySQLDump, true)
+		if err := os.WriteFile(path, []byte(strings.Replace(completeMySQLDump, "(2)", "(3)", 1)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		verification := verifyDump(context.Background(), path)
+		if verification.Valid || !strings.Contains(verification.Problem, "checksum") {
+			t.Errorf("expected a checksum mismatch, got %+v", verification)
+		}
+	})
+}
+
+func TestVerifyDumps(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	dumpsPath := filepath.Join(projectRoot, "var", "dumps")
+
+	writeDumpFile(t, dumpsPath, "app_2024-05-01T10-00.sql.gz", completeMySQLDump, true)
+	writeDumpFile(t, dumpsPath, "snapshots/app/seeded.sql", completeMySQLDump[:40], false)
+	if err := os.MkdirAll(filepath.Join(dumpsPath, "volumes", "shop_db-data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dumpsPath, "volumes", "shop_db-data", "seeded.tar.gz"), []byte("not an archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyDumps(context.Background(), projectRoot)
+	if err != nil {
+		t.Fatalf("VerifyDumps() error = %v", err)
+	}
+
+	if len(result.Dumps) != 3 || result.Invalid != 2 {
+		t.Fatalf("expected 3 files with 2 invalid, got %+v", result)
+	}
+	valid := map[string]bool{}
+	for _, d := range result.Dumps {
+		valid[d.Name] = d.Valid
+	}
+	expected := map[string]bool{
+		"app_2024-05-01T10-00.sql.gz":        true,
+		"snapshots/app/seeded.sql":           false,
+		"volumes/shop_db-data/seeded.tar.gz": false,
+	}
+	for name, want := range expected {
+		if got, ok := valid[name]; !ok || got != want {
+			t.Errorf("%s: valid = %v (listed %v), want %v", name, got, ok, want)
+		}
+	}
+}
+
+func TestVerifyDumpsWithoutDumpsDirectory(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+
+	result, err := VerifyDumps(context.Background(), projectRoot)
+	if err != nil {
+		t.Fatalf("VerifyDumps() error = %v", err)
+	}
+	if len(result.Dumps) != 0 || result.Invalid != 0 {
+		t.Errorf("expected no dumps, got %+v", result)
+	}
+}
+
+func TestImportDBRefusesInvalidDump(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, `exit 0`)
+
+	path := writeDumpFile(t, filepath.Join(projectRoot, "var", "dumps"), "app_1.sql", completeMySQLDump[:60], false)
+
+	_, err := ImportDB(context.Background(), projectRoot, "app_test", path, nil, nil)
+	cmdErr, ok := err.(*types.CommandError)
+	if !ok || cmdErr.Code != types.ErrDumpInvalid {
+		t.Fatalf("expected ErrDumpInvalid, got %v", err)
+	}
+
+	if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+		t.Errorf("docker called for an invalid dump:\n%s", calls)
+	}
+}
+
+func TestImportDBVerificationStopsWithContext(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, `exit 0`)
+
+	path := writeDumpFile(t, filepath.Join(projectRoot, "var", "dumps"), "app_1.sql", completeMySQLDump, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ImportDB(ctx, projectRoot, "app_test", path, nil, nil)
+	cmdErr, ok := err.(*types.CommandError)
+	if !ok || cmdErr.Code != types.ErrCanceled {
+		t.Fatalf("expected ErrCanceled, got %v", err)
+	}
+
+	if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+		t.Errorf("docker called after the import was canceled:\n%s", calls)
+	}
+}
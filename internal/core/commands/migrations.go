@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+)
+
+// maxMigrationRows bounds the rows read from a migrations table
+const maxMigrationRows = 100000
+
+var (
+	// migrationFileRegex is the file name pattern of Doctrine's migration finder
+	migrationFileRegex = regexp.MustCompile(`(?i)^Version.{1,255}\.php$`)
+	namespaceRegex     = regexp.MustCompile(`(?m)^\s*namespace\s+([A-Za-z0-9_\\]+)\s*;`)
+)
+
+// DatabaseMigrations compares the Doctrine migrations executed on dbName, the
+// DSN database by default, with the migration classes of the project.
+// Read-only, no allowed check.
+func DatabaseMigrations(ctx context.Context, projectRoot, dbName string) (*types.MigrationStatus, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for migration operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
+	dir := migrationsDir(cfg, cfg.ProjectRoot)
+	available, err := findMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	status, err := migrationStatus(opCtx, cfg, dbExecutor, parsedDSN, dbName, dir, available)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	return status, nil
+}
+
+// migrationStatus reads the executed versions of dbName and compares them
+// with the classes available in dir
+func migrationStatus(ctx context.Context, cfg *config.Config, dbExecutor executor.DatabaseExecutor, parsedDSN *types.DSN, dbName, dir string, available []string) (*types.MigrationStatus, error) {
+	executed, found, err := executedMigrations(ctx, cfg, dbExecutor, parsedDSN, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := compareMigrations(available, executed)
+	status.Database = dbName
+	status.Path = dir
+	status.NoTable = !found
+	return status, nil
+}
+
+// worktreeMigrations compares the migrations of a worktree with the database
+// its application connects to
+func worktreeMigrations(ctx context.Context, cfg *config.Config, parsedDSN *types.DSN, worktreePath string) (*types.MigrationStatus, error) {
+	dir := migrationsDir(cfg, worktreePath)
+	available, err := findMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// A SQLite database lives in the worktree, a server is reached through the
+	// compose project of the main checkout
+	wtDSN := worktreeDSN(parsedDSN, worktreePath)
+	root := cfg.ProjectRoot
+	if wtDSN.Engine == "sqlite" {
+		root = worktreePath
+	}
+	dbExecutor, _, err := newDatabaseExecutor(cfg, wtDSN, root)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrationStatus(ctx, cfg, dbExecutor, wtDSN, wtDSN.Database, dir, available)
+}
+
+// worktreeDSN returns the DSN of the database a worktree's application uses:
+// the DATABASE_URL of its .env.local, as written when the worktree got its
+// own database, or the configured DSN. Only the database is taken from
+// .env.local, the host there may only resolve inside the containers.
+func worktreeDSN(parsedDSN *types.DSN, worktreePath string) *types.DSN {
+	value := config.EnvFileValue(filepath.Join(worktreePath, ".env.local"), "DATABASE_URL")
+	if value == "" {
+		return parsedDSN
+	}
+
+	local, err := dsn.ParseDSN(value)
+	if err != nil || local.Engine != parsedDSN.Engine {
+		return parsedDSN
+	}
+
+	wtDSN := *parsedDSN
+	wtDSN.Database = local.Database
+	wtDSN.Path = local.Path
+	return &wtDSN
+}
+
+// migrationsDir resolves database.migrations.path against root, the project
+// root or the root of a worktree
+func migrationsDir(cfg *config.Config, root string) string {
+	path := cfg.Database.Migrations.MigrationsPath()
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// findMigrations returns the fully qualified class names of the migrations in
+// dir and its subdirectories, sorted the way Doctrine orders them
+func findMigrations(dir string) ([]string, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, &types.CommandError{
+			Code:    types.ErrFileNotFound,
+			Message: fmt.Sprintf("migrations directory %s not found, set database.migrations.path", dir),
+		}
+	}
+
+	var classes []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !migrationFileRegex.MatchString(d.Name()) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		class := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		if match := namespaceRegex.FindSubmatch(data); match != nil {
+			class = string(match[1]) + `\` + class
+		}
+		classes = append(classes, class)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	sort.Strings(classes)
+	return classes, nil
+}
+
+// executedMigrations reads the versions recorded in the migrations table of
+// dbName. found is false when the database has no such table.
+func executedMigrations(ctx context.Context, cfg *config.Config, dbExecutor executor.DatabaseExecutor, parsedDSN *types.DSN, dbName string) ([]string, bool, error) {
+	table := cfg.Database.Migrations.MigrationsTable()
+
+	tables, err := dbExecutor.Tables(ctx, cfg.Database.Service, parsedDSN, dbName)
+	if err != nil {
+		return nil, false, err
+	}
+	found := false
+	for _, name := range tables {
+		// PostgreSQL lists tables of the public schema unqualified
+		if strings.EqualFold(name, table) || strings.EqualFold("public."+name, table) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	// The table name is validated by config.Load, it needs no quoting
+	result, err := dbExecutor.Query(ctx, cfg.Database.Service, parsedDSN, dbName, "SELECT * FROM "+table,
+		maxMigrationRows, cfg.Database.Timeouts.Duration(config.OpQuery))
+	if err != nil {
+		return nil, false, err
+	}
+	if result.Truncated {
+		return nil, false, fmt.Errorf("migrations table %s has more than %d rows", table, maxMigrationRows)
+	}
+
+	column := -1
+	for i, name := range result.Columns {
+		if strings.EqualFold(name, "version") {
+			column = i
+		}
+	}
+	if column < 0 && len(result.Rows) > 0 {
+		return nil, false, fmt.Errorf("migrations table %s has no version column", table)
+	}
+
+	versions := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if row[column] != nil {
+			versions = append(versions, *row[column])
+		}
+	}
+	return versions, true, nil
+}
+
+// compareMigrations matches executed versions with available classes. Doctrine
+// Migrations 3 records the class name; version 2 recorded only the part after
+// "Version", which is matched against the class name without its namespace.
+func compareMigrations(available, executed []string) *types.MigrationStatus {
+	status := &types.MigrationStatus{
+		Available: len(available),
+		Executed:  len(executed),
+		Pending:   []string{},
+		Unknown:   []string{},
+	}
+
+	done := make(map[string]bool, len(executed))
+	for _, version := range executed {
+		done[version] = true
+	}
+	known := make(map[string]bool, 2*len(available))
+	for _, class := range available {
+		known[class] = true
+		known[legacyVersion(class)] = true
+		if !done[class] && !done[legacyVersion(class)] {
+			status.Pending = append(status.Pending, class)
+		}
+	}
+
+	sorted := append([]string(nil), executed...)
+	sort.Strings(sorted)
+	for _, version := range sorted {
+		if !known[version] {
+			status.Unknown = append(status.Unknown, version)
+		}
+	}
+	if len(sorted) > 0 {
+		status.Current = sorted[len(sorted)-1]
+	}
+
+	return status
+}
+
+// legacyVersion is the version Doctrine Migrations 2 recorded for class
+func legacyVersion(class string) string {
+	name := class[strings.LastIndex(class, `\`)+1:]
+	return name[len("Version"):]
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+)
+
+// OrphanDBs finds the databases named after a worktree or branch that no
+// longer exists, as left behind by git worktree remove or a deleted branch.
+// With drop set, each orphan goes through DropDB, preDrop hooks included; a
+// failed drop is recorded on the orphan and the others are still dropped.
+// Databases matching one of the keep patterns are never reported.
+func OrphanDBs(ctx context.Context, projectRoot string, keep []string, drop bool) (*types.OrphanResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for orphan detection",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range keep {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, &types.CommandError{
+				Code:    types.ErrConfigInvalid,
+				Message: "invalid keep pattern " + pattern,
+			}
+		}
+	}
+
+	// Without the worktrees and branches every database would look orphaned
+	used, err := usedDatabases(ctx, cfg, parsedDSN)
+	if err != nil {
+		return nil, err
+	}
+	branchDBs, err := branchDatabases(ctx, cfg, parsedDSN.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := ListDBs(ctx, projectRoot, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.OrphanResult{Orphans: []types.OrphanDatabase{}}
+	for _, db := range list.Databases {
+		if used[db.Name] || keepDatabase(cfg, parsedDSN.Database, db.Name, keep) {
+			continue
+		}
+		if kind, ok := orphanKind(cfg, parsedDSN.Database, db.Name, branchDBs); ok {
+			result.Orphans = append(result.Orphans, types.OrphanDatabase{Name: db.Name, Kind: kind})
+		}
+	}
+
+	if !drop {
+		return result, nil
+	}
+
+	for i := range result.Orphans {
+		orphan := &result.Orphans[i]
+		if _, err := DropDB(ctx, projectRoot, orphan.Name); err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			orphan.Error = redact.String(err.Error())
+			continue
+		}
+		orphan.Dropped = true
+		result.Dropped++
+	}
+
+	return result, nil
+}
+
+// usedDatabases collects the databases the worktrees and local branches of
+// the project may still use
+func usedDatabases(ctx context.Context, cfg *config.Config, parsedDSN *types.DSN) (map[string]bool, error) {
+	worktrees, err := executor.GitWorktreeList(ctx, cfg.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := executor.GitBranches(ctx, cfg.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{parsedDSN.Database: true}
+	for _, branch := range branches {
+		used[generateBranchDBName(parsedDSN.Database, branch)] = true
+	}
+
+	prefix := worktreeDBPrefix(cfg, parsedDSN.Database)
+	for _, wt := range worktrees {
+		if wt.Branch != "" && wt.Branch != "detached" {
+			_, dbName := core.SanitizeWorktreeName(wt.Branch)
+			used[prefix+dbName] = true
+			used[generateBranchDBName(parsedDSN.Database, wt.Branch)] = true
+		}
+
+		// A worktree whose directory was deleted by hand is still listed
+		// until git worktree prune, its branch keeps its databases
+		if _, err := os.Stat(wt.Path); err != nil {
+			continue
+		}
+		used[worktreeDSN(parsedDSN, wt.Path).Database] = true
+		configured, err := executor.GitConfigValue(ctx, wt.Path, "haive.database")
+		if err != nil {
+			return nil, err
+		}
+		if configured != "" {
+			used[configured] = true
+		}
+	}
+
+	return used, nil
+}
+
+// branchDatabases returns the checkout databases of the branches the project
+// was ever on. Any <db>_<suffix> could be a staging or archive copy, only
+// these are known to be named after a branch.
+func branchDatabases(ctx context.Context, cfg *config.Config, defaultDB string) (map[string]bool, error) {
+	branches, err := executor.GitCheckedOutBranches(ctx, cfg.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	dbs := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		dbs[generateBranchDBName(defaultDB, branch)] = true
+	}
+	return dbs, nil
+}
+
+// worktreeDBPrefix returns worktrees.db_prefix, or its default when the
+// project has no worktrees section
+func worktreeDBPrefix(cfg *config.Config, defaultDB string) string {
+	if cfg.Worktrees != nil && cfg.Worktrees.DBPrefix != "" {
+		return cfg.Worktrees.DBPrefix
+	}
+	return defaultDB + "_wt_"
+}
+
+// keepDatabase tells whether name is off limits for garbage collection
+// whatever its name suggests
+func keepDatabase(cfg *config.Config, defaultDB, name string, keep []string) bool {
+	if name == defaultDB || core.IsDatabaseAllowed(name, cfg.Database.Allowed) != nil {
+		return true
+	}
+	// Symfony's test environment appends _test to the database name, ParaTest
+	// a token after that
+	if strings.HasPrefix(name, defaultDB+"_test") {
+		return true
+	}
+	// Scratch and backup databases of a restore that may still be running
+	if strings.Contains(name, "_haive_") {
+		return true
+	}
+	for _, pattern := range keep {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// orphanKind tells which naming scheme name follows, ok is false for
+// databases haive did not name after a branch. branchDBs are the checkout
+// databases of the known branches.
+func orphanKind(cfg *config.Config, defaultDB, name string, branchDBs map[string]bool) (kind types.OrphanKind, ok bool) {
+	prefix := worktreeDBPrefix(cfg, defaultDB)
+	switch {
+	case strings.HasPrefix(name, prefix) && len(name) > len(prefix):
+		return types.OrphanWorktree, true
+	case name != defaultDB && branchDBs[name]:
+		return types.OrphanBranch, true
+	}
+	return "", false
+}
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func TestOrphanKind(t *testing.T) {
+	cfg := &config.Config{
+		Database:  &config.Database{Allowed: []string{"app", "app_*"}},
+		Worktrees: &config.Worktrees{DBPrefix: "wt_"},
+	}
+	branchDBs := map[string]bool{"app_feature_login": true, "app_wt_feature": true, "app": true}
+
+	tests := []struct {
+		name string
+		kind types.OrphanKind
+		ok   bool
+	}{
+		{"wt_feature_login", types.OrphanWorktree, true},
+		{"app_feature_login", types.OrphanBranch, true},
+		{"app_wt_feature", types.OrphanBranch, true},
+		{"wt_", "", false},
+		{"app_", "", false},
+		{"app", "", false},
+		{"other", "", false},
+		// Suffixes that are no known branch
+		{"app_staging", "", false},
+		{"app_archive", "", false},
+		{"app_haive_restore", "", false},
+		{"app_feature_logout", "", false},
+	}
+
+	for _, tt := range tests {
+		kind, ok := orphanKind(cfg, "app", tt.name, branchDBs)
+		if kind != tt.kind || ok != tt.ok {
+			t.Errorf("orphanKind(%q) = %q, %v, want %q, %v", tt.name, kind, ok, tt.kind, tt.ok)
+		}
+	}
+
+	if prefix := worktreeDBPrefix(&config.Config{}, "app"); prefix != "app_wt_" {
+		t.Errorf("expected the default worktree prefix, got %q", prefix)
+	}
+}
+
+func TestKeepDatabase(t *testing.T) {
+	cfg := &config.Config{Database: &config.Database{Allowed: []string{"app", "app_*"}}}
+
+	for name, expected := range map[string]bool{
+		"app":                    true,
+		"app_test":               true,
+		"app_test1":              true,
+		"app_feature_haive_old":  true,
+		"app_demo":               true,
+		"app_feature":            false,
+		"app_wt_feature":         false,
+		"shop_feature":           true,
+		"app_feature_haive":      false,
+		"app_feature_restore":    false,
+		"app_feature_haive_copy": true,
+	} {
+		if got := keepDatabase(cfg, "app", name, []string{"app_demo*"}); got != expected {
+			t.Errorf("keepDatabase(%q) = %v, want %v", name, got, expected)
+		}
+	}
+}
+
+// git runs a git command in dir
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestOrphanDBs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	projectRoot := setupSQLiteProject(t)
+	ctx := context.Background()
+
+	cfgContent := `{
+		"database": {
+			"dsn": "sqlite:///%kernel.project_dir%/var/data.db",
+			"allowed": ["data", "data_*"],
+			"hooks": {"preDrop": ["test \"$DATABASE_NAME\" != data_locked"]}
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".haive.json"), []byte(cfgContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	git(t, projectRoot, "init", "-b", "main")
+	git(t, projectRoot, "-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", "initial")
+	git(t, projectRoot, "branch", "feature/login")
+	// Branches that were checked out and deleted since
+	for _, branch := range []string{"removed-branch", "locked"} {
+		git(t, projectRoot, "checkout", "-q", "-b", branch)
+		git(t, projectRoot, "checkout", "-q", "main")
+		git(t, projectRoot, "branch", "-D", branch)
+	}
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	git(t, projectRoot, "worktree", "add", "-b", "fix/cart", worktreePath)
+	git(t, worktreePath, "config", "--local", "haive.database", "data_configured")
+
+	// The worktrees are those of the project, not of the working directory
+	otherRepo := t.TempDir()
+	git(t, otherRepo, "init", "-b", "main")
+	t.Chdir(otherRepo)
+
+	for _, name := range []string{
+		"data_feature_login",  // checkout database of an existing branch
+		"data_fix_cart",       // checkout database of the worktree's branch
+		"data_wt_fix_cart",    // database of the worktree
+		"data_configured",     // set in git config
+		"data_test",           // Symfony test database
+		"data_keep",           // kept by pattern
+		"data_staging",        // not named after a branch
+		"data_removed_branch", // orphans
+		"data_wt_gone",
+		"data_locked",
+	} {
+		if err := os.WriteFile(filepath.Join(projectRoot, "var", name+".db"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := OrphanDBs(ctx, projectRoot, []string{"data_kee*"}, false)
+	if err != nil {
+		t.Fatalf("OrphanDBs() error = %v", err)
+	}
+	kinds := map[string]types.OrphanKind{}
+	for _, orphan := range result.Orphans {
+		kinds[orphan.Name] = orphan.Kind
+		if orphan.Dropped {
+			t.Errorf("expected nothing dropped without drop, got %+v", orphan)
+		}
+	}
+	expected := map[string]types.OrphanKind{
+		"data_removed_branch": types.OrphanBranch,
+		"data_wt_gone":        types.OrphanWorktree,
+		"data_locked":         types.OrphanBranch,
+	}
+	if len(kinds) != len(expected) {
+		t.Fatalf("orphans = %v, want %v", kinds, expected)
+	}
+	for name, kind := range expected {
+		if kinds[name] != kind {
+			t.Errorf("expected %s to be a %s orphan, got %q", name, kind, kinds[name])
+		}
+	}
+
+	result, err = OrphanDBs(ctx, projectRoot, []string{"data_kee*"}, true)
+	if err != nil {
+		t.Fatalf("OrphanDBs() with drop error = %v", err)
+	}
+	if result.Dropped != 2 {
+		t.Errorf("expected two databases dropped, got %+v", result)
+	}
+	for _, orphan := range result.Orphans {
+		if orphan.Name == "data_locked" {
+			if orphan.Dropped || !strings.Contains(orphan.Error, "preDrop hook prevented drop") {
+				t.Errorf("expected the preDrop hook to keep data_locked, got %+v", orphan)
+			}
+		} else if !orphan.Dropped {
+			t.Errorf("expected %s to be dropped, got %+v", orphan.Name, orphan)
+		}
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(projectRoot, "var"))
+	var remaining []string
+	for _, entry := range entries {
+		remaining = append(remaining, strings.TrimSuffix(entry.Name(), ".db"))
+	}
+	sort.Strings(remaining)
+	if strings.Join(remaining, ",") != "data,data_configured,data_feature_login,data_fix_cart,data_keep,data_locked,data_staging,data_test,data_wt_fix_cart" {
+		t.Errorf("unexpected databases left: %v", remaining)
+	}
+}
+
+func TestOrphanDBsOutsideRepository(t *testing.T) {
+	projectRoot := setupSQLiteProject(t)
+	if err := os.WriteFile(filepath.Join(projectRoot, "var", "data_feature.db"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without git nothing can be told apart, nothing is reported
+	if _, err := OrphanDBs(context.Background(), projectRoot, nil, true); err == nil {
+		t.Fatal("expected an error outside a git repository")
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "var", "data_feature.db")); err != nil {
+		t.Errorf("expected data_feature to be left alone: %v", err)
+	}
+}
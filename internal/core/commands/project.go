@@ -111,14 +111,78 @@ func findComposeFiles(projectRoot string) []string {
 }
 
 type DockerCompose struct {
+	Name     string `yaml:"name"`
 	Services map[string]struct {
-		Image string `yaml:"image"`
+		Image   string          `yaml:"image"`
+		Volumes []ServiceVolume `yaml:"volumes"`
 	} `yaml:"services"`
+	Volumes map[string]*ComposeVolume `yaml:"volumes"`
 }
 
-func detectDockerServices(projectRoot string, composeFiles []string) (map[string]string, error) {
-	services := make(map[string]string)
+// ComposeVolume is a top-level volume declaration
+type ComposeVolume struct {
+	Name     string          `yaml:"name"`
+	External composeExternal `yaml:"external"`
+}
+
+// composeExternal is the external flag of a volume, which older compose files
+// write as a mapping holding the name of the volume
+type composeExternal struct {
+	Set  bool
+	Name string
+}
+
+func (e *composeExternal) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var legacy struct {
+			Name string `yaml:"name"`
+		}
+		if err := node.Decode(&legacy); err != nil {
+			return err
+		}
+		e.Set, e.Name = true, legacy.Name
+		return nil
+	}
+	return node.Decode(&e.Set)
+}
+
+// ServiceVolume is a volume mount of a service, in the short "source:target"
+// or the long mapping syntax
+type ServiceVolume struct {
+	Type   string `yaml:"type"`
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+}
+
+func (v *ServiceVolume) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parts := strings.Split(node.Value, ":")
+		if len(parts) == 1 {
+			// An anonymous volume, there is no source
+			v.Target = parts[0]
+			return nil
+		}
+		v.Source, v.Target = parts[0], parts[1]
+		return nil
+	}
+
+	type plain ServiceVolume
+	return node.Decode((*plain)(v))
+}
+
+// IsNamed reports whether the mount refers to a named volume rather than a
+// bind mount or an anonymous volume
+func (v ServiceVolume) IsNamed() bool {
+	if v.Type != "" {
+		return v.Type == "volume" && v.Source != ""
+	}
+	return v.Source != "" && !strings.ContainsAny(v.Source[:1], "/.~$")
+}
 
+// readComposeFiles parses the compose files that exist, skipping those that
+// can't be read or parsed
+func readComposeFiles(projectRoot string, composeFiles []string) []DockerCompose {
+	var parsed []DockerCompose
 	for _, composeFile := range composeFiles {
 		fullPath := filepath.Join(projectRoot, composeFile)
 		data, err := os.ReadFile(fullPath)
@@ -130,7 +194,15 @@ func detectDockerServices(projectRoot string, composeFiles []string) (map[string
 		if err := yaml.Unmarshal(data, &dc); err != nil {
 			continue
 		}
+		parsed = append(parsed, dc)
+	}
+	return parsed
+}
+
+func detectDockerServices(projectRoot string, composeFiles []string) (map[string]string, error) {
+	services := make(map[string]string)
 
+	for _, dc := range readComposeFiles(projectRoot, composeFiles) {
 		for name, svc := range dc.Services {
 			services[name] = svc.Image
 		}
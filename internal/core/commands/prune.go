@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// PruneDumps removes the dumps that violate the configured database.retention
+// policy. With dryRun set it only reports what would be removed. The latest
+// dump of each database and snapshots are never pruned.
+func PruneDumps(projectRoot string, dryRun bool) (*types.PruneResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for dumps prune operations",
+		}
+	}
+
+	if cfg.Database.Retention.IsEmpty() {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database.retention must set keep_last, max_age or max_total_size to prune dumps",
+		}
+	}
+
+	return pruneDumps(resolveDumpsPath(cfg, projectRoot), cfg.Database.Retention, dryRun, time.Now())
+}
+
+func pruneDumps(dumpsPath string, retention *config.Retention, dryRun bool, now time.Time) (*types.PruneResult, error) {
+	maxAge, err := retention.MaxAgeDuration()
+	if err != nil {
+		return nil, err
+	}
+	maxTotalSize, err := retention.MaxTotalSizeBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	dumps, err := listDumpFiles(dumpsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(dumps, func(i, j int) bool {
+		return dumpTime(dumps[i]).After(dumpTime(dumps[j]))
+	})
+
+	// Walk newest first so every rule keeps the most recent dumps
+	reasons := make([]string, len(dumps))
+	perDatabase := make(map[string]int)
+	var totalSize int64
+	for i, d := range dumps {
+		perDatabase[d.Database]++
+
+		switch {
+		case perDatabase[d.Database] == 1:
+			// The latest dump of a database is always kept
+			totalSize += d.Size
+		case retention.KeepLast > 0 && perDatabase[d.Database] > retention.KeepLast:
+			reasons[i] = fmt.Sprintf("keep_last %d", retention.KeepLast)
+		case maxAge > 0 && now.Sub(dumpTime(d)) > maxAge:
+			reasons[i] = fmt.Sprintf("older than %s", retention.MaxAge)
+		case maxTotalSize > 0 && totalSize+d.Size > maxTotalSize:
+			reasons[i] = fmt.Sprintf("max_total_size %s", retention.MaxTotalSize)
+		default:
+			totalSize += d.Size
+		}
+	}
+
+	result := &types.PruneResult{DryRun: dryRun, Removed: []types.PrunedDump{}}
+	for i, d := range dumps {
+		if reasons[i] == "" {
+			result.Kept++
+			continue
+		}
+
+		if !dryRun {
+			path := filepath.Join(dumpsPath, d.Name)
+			if err := os.Remove(path); err != nil {
+				return result, fmt.Errorf("failed to remove dump %s: %w", d.Name, err)
+			}
+			if err := os.Remove(dumpMetadataPath(path)); err != nil && !os.IsNotExist(err) {
+				redact.Fprintf(os.Stderr, "Warning: failed to remove metadata of %s: %v\n", d.Name, err)
+			}
+		}
+
+		result.Removed = append(result.Removed, types.PrunedDump{
+			Name:     d.Name,
+			Database: d.Database,
+			Size:     d.Size,
+			Reason:   reasons[i],
+		})
+		result.Freed += d.Size
+	}
+
+	return result, nil
+}
+
+// dumpTime returns when a dump was taken, preferring its recorded metadata
+func dumpTime(d types.DumpFileInfo) time.Time {
+	if d.Metadata != nil && !d.Metadata.CreatedAt.IsZero() {
+		return d.Metadata.CreatedAt
+	}
+	t, _ := time.Parse(time.RFC3339, d.Modified)
+	return t
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+const (
+	// defaultQueryRows is how many rows a query returns unless asked otherwise
+	defaultQueryRows = 100
+	// maxQueryRows bounds the rows of a query, which mostly end up in the
+	// context of an agent
+	maxQueryRows = 1000
+	// defaultQueryTimeout bounds a query while database.timeouts.query is unset
+	defaultQueryTimeout = 30 * time.Second
+)
+
+// QueryDB runs a single SELECT, SHOW, EXPLAIN or DESCRIBE statement on dbName,
+// the DSN database by default, which must be allowed. Statements that could
+// write are rejected before they reach the server, and the rest run in a
+// read-only transaction. maxRows, 100 when 0 and at most 1000, caps the rows
+// returned; database.timeouts.query, or 30s, caps the runtime.
+func QueryDB(ctx context.Context, projectRoot, dbName, query string, maxRows int) (*types.QueryResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for query operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
+	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := core.CheckReadOnlyQuery(query, engine.Dialect())
+	if err != nil {
+		return nil, err
+	}
+
+	if maxRows <= 0 {
+		maxRows = defaultQueryRows
+	}
+	maxRows = min(maxRows, maxQueryRows)
+
+	timeout := cfg.Database.Timeouts.Duration(config.OpQuery)
+	if timeout == 0 {
+		timeout = defaultQueryTimeout
+	}
+	opCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := dbExecutor.Query(opCtx, cfg.Database.Service, parsedDSN, dbName, statement, maxRows, timeout)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	return result, nil
+}
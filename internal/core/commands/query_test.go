@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func TestQueryDB(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, `printf 'id\temail\n1\ta@example.com\n'`)
+
+	result, err := QueryDB(context.Background(), projectRoot, "", "-- recent users\nSELECT id, email FROM user;", 0)
+	if err != nil {
+		t.Fatalf("QueryDB() error = %v", err)
+	}
+
+	if result.Database != "app" || result.MaxRows != 100 || result.Truncated {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if strings.Join(result.Columns, ",") != "id,email" || len(result.Rows) != 1 || *result.Rows[0][1] != "a@example.com" {
+		t.Errorf("unexpected columns %v and rows %v", result.Columns, result.Rows)
+	}
+
+	calls, _ := os.ReadFile(logPath)
+	for _, part := range []string{
+		"SET SESSION sql_select_limit = 101",
+		"SET SESSION max_execution_time = 30000",
+		"START TRANSACTION READ ONLY; SELECT id, email FROM user; ROLLBACK",
+	} {
+		if !strings.Contains(string(calls), part) {
+			t.Errorf("expected %q in docker calls:\n%s", part, calls)
+		}
+	}
+}
+
+func TestQueryDBMaxRowsCapped(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, `printf 'id\n'`)
+
+	result, err := QueryDB(context.Background(), projectRoot, "app_feature", "SELECT id FROM user", 5000)
+	if err != nil {
+		t.Fatalf("QueryDB() error = %v", err)
+	}
+	if result.Database != "app_feature" || result.MaxRows != 1000 {
+		t.Errorf("expected at most 1000 rows of app_feature, got %+v", result)
+	}
+	if calls, _ := os.ReadFile(logPath); !strings.Contains(string(calls), "sql_select_limit = 1001") {
+		t.Errorf("expected the row limit to be capped, got calls:\n%s", calls)
+	}
+}
+
+func TestQueryDBRejected(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, `printf 'id\n'`)
+
+	for _, query := range []string{
+		"DELETE FROM user",
+		"SELECT 1; DROP TABLE user",
+		"SELECT * FROM user FOR UPDATE",
+		"",
+	} {
+		_, err := QueryDB(context.Background(), projectRoot, "", query, 0)
+		if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrQueryRejected {
+			t.Errorf("QueryDB(%q) expected ErrQueryRejected, got %v", query, err)
+		}
+	}
+
+	_, err := QueryDB(context.Background(), projectRoot, "other", "SELECT 1", 0)
+	if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrDbNotAllowed {
+		t.Errorf("expected ErrDbNotAllowed, got %v", err)
+	}
+
+	if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+		t.Errorf("expected no docker calls, got:\n%s", calls)
+	}
+}
+
+func TestQueryDBSQLite(t *testing.T) {
+	projectRoot := setupSQLiteProject(t)
+	ctx := context.Background()
+
+	insert := "WITH RECURSIVE n(i) AS (SELECT 2 UNION ALL SELECT i + 1 FROM n WHERE i < 20) INSERT INTO users SELECT i FROM n"
+	if out, err := exec.Command("sqlite3", filepath.Join(projectRoot, "var", "data.db"), insert).CombinedOutput(); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+
+	result, err := QueryDB(ctx, projectRoot, "", "SELECT id, NULL AS note FROM users ORDER BY id", 5)
+	if err != nil {
+		t.Fatalf("QueryDB() error = %v", err)
+	}
+	if len(result.Rows) != 5 || !result.Truncated || *result.Rows[4][0] != "5" || result.Rows[4][1] != nil {
+		t.Errorf("expected the first 5 of 20 rows, got %+v", result)
+	}
+
+	_, err = QueryDB(ctx, projectRoot, "", "SELECT * FROM missing", 0)
+	if err == nil || !strings.Contains(err.Error(), "no such table") {
+		t.Errorf("expected the query to fail, got %v", err)
+	}
+}
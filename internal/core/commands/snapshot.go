@@ -0,0 +1,334 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// restoreScratchSuffix names the database a snapshot is imported into before
+// it replaces the real one
+const restoreScratchSuffix = "_haive_restore"
+
+// Snapshot stores a labelled dump of dbName under <dumps_path>/snapshots/<db>/<name>.
+// An empty dbName means the default database from the DSN.
+func Snapshot(ctx context.Context, projectRoot, dbName, name string) (*types.SnapshotResult, error) {
+	start := time.Now()
+
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for snapshot operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
+	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	if err := core.ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+
+	dir := snapshotDir(resolveDumpsPath(cfg, projectRoot), dbName)
+	if _, ok := findSnapshot(dir, name); ok {
+		return nil, &types.CommandError{
+			Code:    types.ErrAlreadyExists,
+			Message: fmt.Sprintf("snapshot '%s' already exists for database '%s'", name, dbName),
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+
+	dbExecutor, engine, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDSN := *parsedDSN
+	sourceDSN.Database = dbName
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpDump)
+	defer cancel()
+
+	destPath := filepath.Join(dir, name+cfg.Database.DumpCompression.Extension())
+	dumpResult, err := dbExecutor.Dump(opCtx, cfg.Database.Service, &sourceDSN, destPath, types.TableFilter{})
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpDump, fmt.Errorf("failed to dump database: %w", err))
+	}
+
+	meta := buildDumpMetadata(opCtx, dbExecutor, cfg.Database.Service, &sourceDSN, engine.Name(), projectRoot, types.TableFilter{}, dumpResult)
+	if err := writeDumpMetadata(destPath, meta); err != nil {
+		redact.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	return &types.SnapshotResult{
+		Name:     name,
+		Database: dbName,
+		Path:     destPath,
+		Size:     dumpResult.Size,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// RestoreSnapshot replaces dbName with the contents of a snapshot. The snapshot
+// is imported into a scratch database first and only swapped in once the
+// import succeeded, so a broken snapshot never touches dbName. The scratch
+// database is also dropped when the restore is cancelled or times out.
+func RestoreSnapshot(ctx context.Context, projectRoot, dbName, name string) (*types.RestoreResult, error) {
+	start := time.Now()
+
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for restore operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
+	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	if err := core.ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+
+	snapshotPath, ok := findSnapshot(snapshotDir(resolveDumpsPath(cfg, projectRoot), dbName), name)
+	if !ok {
+		return nil, &types.CommandError{
+			Code:    types.ErrFileNotFound,
+			Message: fmt.Sprintf("snapshot '%s' not found for database '%s'", name, dbName),
+		}
+	}
+
+	if err := restoreDump(ctx, cfg, parsedDSN, projectRoot, dbName, snapshotPath, "snapshot"); err != nil {
+		return nil, err
+	}
+
+	return &types.RestoreResult{
+		Name:     name,
+		Database: dbName,
+		Path:     snapshotPath,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// restoreDump replaces dbName with the contents of the dump at dumpPath through
+// a scratch database, creating dbName if it doesn't exist. label names the
+// dump in errors. The scratch database and the one the swap parks the old
+// data in must be allowed like any other.
+func restoreDump(ctx context.Context, cfg *config.Config, parsedDSN *types.DSN, projectRoot, dbName, dumpPath, label string) error {
+	scratchDB := dbName + restoreScratchSuffix
+	for _, name := range []string{scratchDB, dbName + engines.SwapBackupSuffix} {
+		if core.IsDatabaseAllowed(name, cfg.Database.Allowed) != nil {
+			return &types.CommandError{
+				Code: types.ErrDbNotAllowed,
+				Message: fmt.Sprintf("restoring '%s' needs its scratch database '%s' in database.allowed, e.g. with the pattern '%s_haive_*'",
+					dbName, name, dbName),
+			}
+		}
+	}
+
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return err
+	}
+	service := cfg.Database.Service
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpImport)
+	defer cancel()
+
+	existing, err := dbExecutor.List(opCtx, service, parsedDSN, parsedDSN.Database)
+	if err != nil {
+		return interruptedError(opCtx, cfg, config.OpImport, err)
+	}
+
+	targetExists := false
+	for _, db := range existing.Databases {
+		switch db.Name {
+		case dbName:
+			targetExists = true
+		case scratchDB:
+			// Left behind by an interrupted restore
+			if _, err := dbExecutor.Drop(opCtx, service, parsedDSN, scratchDB); err != nil {
+				return interruptedError(opCtx, cfg, config.OpImport, fmt.Errorf("failed to remove stale scratch database: %w", err))
+			}
+		}
+	}
+
+	// dropScratch removes the scratch database, even once opCtx is done
+	dropScratch := func() {
+		cleanupCtx, cancel := cleanupContext(opCtx)
+		defer cancel()
+		dbExecutor.Drop(cleanupCtx, service, parsedDSN, scratchDB)
+	}
+
+	if _, err := dbExecutor.Create(opCtx, service, parsedDSN, scratchDB); err != nil {
+		return interruptedError(opCtx, cfg, config.OpImport, fmt.Errorf("failed to create scratch database: %w", err))
+	}
+
+	if _, err := dbExecutor.Import(opCtx, service, parsedDSN, dumpPath, scratchDB, nil); err != nil {
+		dropScratch()
+		return interruptedError(opCtx, cfg, config.OpImport, fmt.Errorf("failed to import %s, '%s' was left unchanged: %w", label, dbName, err))
+	}
+
+	if !targetExists {
+		if _, err := dbExecutor.Create(opCtx, service, parsedDSN, dbName); err != nil {
+			dropScratch()
+			return interruptedError(opCtx, cfg, config.OpImport, fmt.Errorf("failed to create target database: %w", err))
+		}
+	}
+
+	if err := dbExecutor.Swap(opCtx, service, parsedDSN, scratchDB, dbName); err != nil {
+		dropScratch()
+		return interruptedError(opCtx, cfg, config.OpImport, fmt.Errorf("failed to swap in restored database: %w", err))
+	}
+
+	return nil
+}
+
+// ListSnapshots lists the snapshots of dbName, or of every database when dbName is empty
+func ListSnapshots(projectRoot, dbName string) (*types.SnapshotListResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for snapshot operations",
+		}
+	}
+
+	dumpsPath := resolveDumpsPath(cfg, projectRoot)
+
+	var databases []string
+	if dbName != "" {
+		if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
+			return nil, err
+		}
+		if err := core.CheckPathTraversal(snapshotDir(dumpsPath, dbName), filepath.Join(dumpsPath, "snapshots")); err != nil {
+			return nil, err
+		}
+		databases = []string{dbName}
+	} else {
+		entries, err := os.ReadDir(filepath.Join(dumpsPath, "snapshots"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				databases = append(databases, entry.Name())
+			}
+		}
+	}
+
+	snapshots := []types.SnapshotInfo{}
+	for _, db := range databases {
+		dir := snapshotDir(dumpsPath, db)
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+		}
+
+		for _, entry := range entries {
+			name, _, ok := types.ParseDumpFilename(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			snapshot := types.SnapshotInfo{
+				Name:     name,
+				Database: db,
+				Path:     path,
+				Size:     info.Size(),
+				Created:  info.ModTime().Format(time.RFC3339),
+			}
+			if meta, err := readDumpMetadata(path); err == nil && meta != nil {
+				snapshot.Created = meta.CreatedAt.Format(time.RFC3339)
+				snapshot.GitBranch = meta.GitBranch
+			}
+
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Database != snapshots[j].Database {
+			return snapshots[i].Database < snapshots[j].Database
+		}
+		return snapshots[i].Created > snapshots[j].Created
+	})
+
+	return &types.SnapshotListResult{Snapshots: snapshots}, nil
+}
+
+func snapshotDir(dumpsPath, dbName string) string {
+	return filepath.Join(dumpsPath, "snapshots", dbName)
+}
+
+// findSnapshot returns the dump file of snapshot name in dir, whatever its compression
+func findSnapshot(dir, name string) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if base, _, ok := types.ParseDumpFilename(entry.Name()); ok && base == name && !entry.IsDir() {
+			return filepath.Join(dir, entry.Name()), true
+		}
+	}
+
+	return "", false
+}
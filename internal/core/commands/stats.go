@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// DatabaseStats reports the size of dbName and lists its tables, largest
+// first. dbName defaults to the DSN database. limit > 0 keeps only that many
+// tables in the list; the totals still cover all of them.
+func DatabaseStats(ctx context.Context, projectRoot, dbName string, limit int) (*types.DatabaseStatsResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for stats operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName == "" {
+		dbName = parsedDSN.Database
+	}
+
+	if err := core.IsDatabaseAllowed(dbName, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	result, err := dbExecutor.Stats(opCtx, cfg.Database.Service, parsedDSN, dbName)
+	if err != nil {
+		return nil, interruptedError(opCtx, cfg, config.OpQuery, err)
+	}
+
+	if limit > 0 && len(result.Tables) > limit {
+		result.Tables = result.Tables[:limit]
+	}
+
+	return result, nil
+}
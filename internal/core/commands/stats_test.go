@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+const statsDocker = `case "$*" in
+  *"SHOW DATABASES"*) printf 'Database\napp\napp_broken\n' ;;
+  *"'app_broken'"*) echo "access denied" >&2; exit 1 ;;
+  *information_schema.TABLES*) printf 'posts\t10\t16384\t0\t0\nusers\t1200\t163840\t32768\t1714557600\n' ;;
+esac`
+
+func TestDatabaseStats(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, statsDocker)
+
+	result, err := DatabaseStats(context.Background(), projectRoot, "", 1)
+	if err != nil {
+		t.Fatalf("DatabaseStats() error = %v", err)
+	}
+
+	if result.Database != "app" {
+		t.Errorf("expected the DSN database, got %s", result.Database)
+	}
+	if len(result.Tables) != 1 || result.Tables[0].Name != "users" {
+		t.Errorf("expected only the largest table, got %+v", result.Tables)
+	}
+	if result.Stats.TableCount != 2 || result.Stats.Rows != 1210 || result.Stats.Size != 212992 {
+		t.Errorf("expected totals over all tables, got %+v", result.Stats)
+	}
+
+	calls, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(calls), "TABLE_SCHEMA = 'app'") {
+		t.Errorf("expected a query of app, got:\n%s", calls)
+	}
+}
+
+func TestDatabaseStatsNotAllowed(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, statsDocker)
+
+	_, err := DatabaseStats(context.Background(), projectRoot, "shop", 0)
+	if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrDbNotAllowed {
+		t.Fatalf("expected ErrDbNotAllowed, got %v", err)
+	}
+
+	if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+		t.Errorf("expected no query of shop, got:\n%s", calls)
+	}
+}
+
+func TestListDBsWithStats(t *testing.T) {
+	projectRoot := setupSnapshotProject(t)
+	logPath := fakeDocker(t, statsDocker)
+
+	result, err := ListDBs(context.Background(), projectRoot, false, false)
+	if err != nil {
+		t.Fatalf("ListDBs() error = %v", err)
+	}
+	if len(result.Databases) != 2 || result.Databases[0].Stats != nil {
+		t.Errorf("expected databases without stats, got %+v", result.Databases)
+	}
+	if calls, _ := os.ReadFile(logPath); strings.Contains(string(calls), "information_schema") {
+		t.Errorf("expected no statistics queries without stats, got:\n%s", calls)
+	}
+
+	result, err = ListDBs(context.Background(), projectRoot, true, false)
+	if err != nil {
+		t.Fatalf("ListDBs() with stats error = %v", err)
+	}
+	if len(result.Databases) != 2 {
+		t.Fatalf("expected 2 databases, got %+v", result.Databases)
+	}
+
+	app := result.Databases[0]
+	if !app.IsDefault || app.Stats == nil || app.Stats.TableCount != 2 || app.Stats.Modified != "2024-05-01T10:00:00Z" {
+		t.Errorf("unexpected app %+v (stats %+v)", app, app.Stats)
+	}
+	// A database whose statistics can't be read is still listed
+	if broken := result.Databases[1]; broken.Name != "app_broken" || broken.Stats != nil {
+		t.Errorf("unexpected app_broken %+v", broken)
+	}
+}
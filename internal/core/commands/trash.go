@@ -0,0 +1,246 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// trashDirName is the directory under dumps_path that holds the backups taken
+// before destructive operations. It is kept out of dumps lists and retention.
+const trashDirName = ".trash"
+
+// Operations a backup in the trash is taken before
+const (
+	trashDrop   = "drop"
+	trashImport = "import"
+)
+
+func trashDir(cfg *config.Config, projectRoot string) string {
+	return filepath.Join(resolveDumpsPath(cfg, projectRoot), trashDirName)
+}
+
+// backupBeforeDestructive dumps dbName into the trash before operation
+// replaces or removes it, when database.backup_before_destructive is set and
+// dbName exists. It returns the path of the backup, "" when none was taken. An
+// error means dbName must be left alone.
+func backupBeforeDestructive(ctx context.Context, cfg *config.Config, dbExecutor executor.DatabaseExecutor, engine engines.DatabaseEngine, parsedDSN *types.DSN, projectRoot, dbName, operation string) (string, error) {
+	if !cfg.Database.BackupBeforeDestructive {
+		return "", nil
+	}
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpDump)
+	defer cancel()
+
+	existing, err := dbExecutor.List(opCtx, cfg.Database.Service, parsedDSN, parsedDSN.Database)
+	if err != nil {
+		return "", interruptedError(opCtx, cfg, config.OpDump, fmt.Errorf("backup before %s failed: %w", operation, err))
+	}
+	found := false
+	for _, db := range existing.Databases {
+		if db.Name == dbName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", nil
+	}
+
+	dir := trashDir(cfg, projectRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	destPath := filepath.Join(dir,
+		fmt.Sprintf("%s_%s%s", dbName, time.Now().Format("2006-01-02T15-04-05"), cfg.Database.DumpCompression.Extension()))
+
+	sourceDSN := *parsedDSN
+	sourceDSN.Database = dbName
+
+	result, err := dbExecutor.Dump(opCtx, cfg.Database.Service, &sourceDSN, destPath, types.TableFilter{})
+	if err != nil {
+		return "", interruptedError(opCtx, cfg, config.OpDump,
+			fmt.Errorf("backup before %s failed, '%s' was left unchanged: %w", operation, dbName, err))
+	}
+
+	meta := buildDumpMetadata(opCtx, dbExecutor, cfg.Database.Service, &sourceDSN, engine.Name(), projectRoot, types.TableFilter{}, result)
+	meta.Trashed = operation
+	if err := writeDumpMetadata(destPath, meta); err != nil {
+		redact.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if err := expireTrash(dir, cfg.Database.Trash, time.Now()); err != nil {
+		redact.Fprintf(os.Stderr, "Warning: trash expiry failed: %v\n", err)
+	}
+
+	return destPath, nil
+}
+
+// ListTrash lists the backups taken before destructive operations, newest first
+func ListTrash(projectRoot string) (*types.TrashListResult, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for trash operations",
+		}
+	}
+
+	trash, err := listTrash(trashDir(cfg, projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TrashListResult{Trash: trash}, nil
+}
+
+// Undo restores the latest backup in the trash, of dbName when set, into the
+// database it was taken of and removes it from the trash. Whatever that
+// database holds now is replaced; the next Undo brings back the backup before.
+func Undo(ctx context.Context, projectRoot, dbName string) (*types.UndoResult, error) {
+	start := time.Now()
+
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for undo operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	trash, err := listTrash(trashDir(cfg, projectRoot))
+	if err != nil {
+		return nil, err
+	}
+
+	var backup *types.TrashedDatabase
+	for i := range trash {
+		if dbName == "" || trash[i].Database == dbName {
+			backup = &trash[i]
+			break
+		}
+	}
+	if backup == nil {
+		message := "the trash is empty, nothing to undo"
+		if dbName != "" {
+			message = fmt.Sprintf("no backup of database '%s' in the trash", dbName)
+		}
+		return nil, &types.CommandError{
+			Code:    types.ErrFileNotFound,
+			Message: message,
+		}
+	}
+
+	if err := core.IsDatabaseAllowed(backup.Database, cfg.Database.Allowed); err != nil {
+		return nil, err
+	}
+
+	if err := verifyBeforeImport(ctx, cfg, backup.Path, "restore"); err != nil {
+		return nil, err
+	}
+
+	if err := restoreDump(ctx, cfg, parsedDSN, projectRoot, backup.Database, backup.Path, "backup"); err != nil {
+		return nil, err
+	}
+
+	removeDump(backup.Path)
+
+	return &types.UndoResult{
+		TrashedDatabase: *backup,
+		Duration:        time.Since(start),
+	}, nil
+}
+
+// listTrash returns the backups in dir, newest first
+func listTrash(dir string) ([]types.TrashedDatabase, error) {
+	dumps, err := listDumpFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(dumps, func(i, j int) bool {
+		return dumpTime(dumps[i]).After(dumpTime(dumps[j]))
+	})
+
+	trash := make([]types.TrashedDatabase, 0, len(dumps))
+	for _, d := range dumps {
+		backup := types.TrashedDatabase{
+			Database: d.Database,
+			Path:     filepath.Join(dir, d.Name),
+			Size:     d.Size,
+			Created:  dumpTime(d).Format(time.RFC3339),
+		}
+		if d.Metadata != nil {
+			backup.Operation = d.Metadata.Trashed
+			backup.GitBranch = d.Metadata.GitBranch
+		}
+		trash = append(trash, backup)
+	}
+
+	return trash, nil
+}
+
+// expireTrash removes the backups in dir that are older than the trash
+// max_age or beyond its keep_last
+func expireTrash(dir string, trash *config.Trash, now time.Time) error {
+	maxAge, err := trash.MaxAgeDuration()
+	if err != nil {
+		return err
+	}
+
+	dumps, err := listDumpFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(dumps, func(i, j int) bool {
+		return dumpTime(dumps[i]).After(dumpTime(dumps[j]))
+	})
+
+	perDatabase := make(map[string]int)
+	for _, d := range dumps {
+		perDatabase[d.Database]++
+		keepLast := trash.KeepLastCount()
+		if (keepLast > 0 && perDatabase[d.Database] > keepLast) || now.Sub(dumpTime(d)) > maxAge {
+			removeDump(filepath.Join(dir, d.Name))
+		}
+	}
+
+	return nil
+}
+
+// removeDump deletes a dump file and its metadata, warning on failure
+func removeDump(path string) {
+	if err := os.Remove(path); err != nil {
+		redact.Fprintf(os.Stderr, "Warning: failed to remove %s: %v\n", path, err)
+		return
+	}
+	if err := os.Remove(dumpMetadataPath(path)); err != nil && !os.IsNotExist(err) {
+		redact.Fprintf(os.Stderr, "Warning: failed to remove metadata of %s: %v\n", path, err)
+	}
+}
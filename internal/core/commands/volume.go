@@ -0,0 +1,382 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
+)
+
+// volumeArchiveExt is the extension of volume snapshots, gzipped tarballs
+const volumeArchiveExt = ".tar.gz"
+
+// dataDirs are the data directories of the official database images. A
+// service mounting several named volumes is snapshotted by the one at these.
+var dataDirs = []string{"/var/lib/mysql", "/var/lib/postgresql/data", "/var/lib/postgresql"}
+
+// volumeTarget is the service and volume a volume snapshot works on
+type volumeTarget struct {
+	cfg      *config.Config
+	service  string
+	volume   string
+	dir      string
+	executor *executor.VolumeExecutor
+}
+
+// VolumeSnapshot stops the database service and archives its data volume to
+// <dumps_path>/volumes/<volume>/<name>.tar.gz. Unlike a dump it copies the
+// server's files, so restoring it takes as long as unpacking them. The
+// service is started again afterwards, also when archiving failed.
+func VolumeSnapshot(ctx context.Context, projectRoot, name string) (*types.VolumeSnapshotResult, error) {
+	start := time.Now()
+
+	if err := core.ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+
+	target, err := resolveVolumeTarget(ctx, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	destPath := filepath.Join(target.dir, name+volumeArchiveExt)
+	if _, err := os.Stat(destPath); err == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrAlreadyExists,
+			Message: fmt.Sprintf("volume snapshot '%s' already exists for volume '%s'", name, target.volume),
+		}
+	}
+
+	if err := os.MkdirAll(target.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create volume snapshots directory: %w", err)
+	}
+
+	opCtx, cancel := withTimeout(ctx, target.cfg, config.OpDump)
+	defer cancel()
+
+	if err := target.stopService(opCtx); err != nil {
+		return nil, interruptedError(opCtx, target.cfg, config.OpDump, err)
+	}
+	defer target.startService(opCtx)
+
+	size, err := target.executor.Archive(opCtx, target.volume, destPath)
+	if err != nil {
+		return nil, interruptedError(opCtx, target.cfg, config.OpDump, fmt.Errorf("failed to archive volume: %w", err))
+	}
+
+	return &types.VolumeSnapshotResult{
+		Name:     name,
+		Service:  target.service,
+		Volume:   target.volume,
+		Path:     destPath,
+		Size:     size,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// VolumeRestore stops the database service and replaces the contents of its
+// data volume with a volume snapshot. The archive is checked before the
+// service is stopped, so a corrupt snapshot leaves the volume untouched.
+func VolumeRestore(ctx context.Context, projectRoot, name string) (*types.VolumeRestoreResult, error) {
+	start := time.Now()
+
+	if err := core.ValidateSnapshotName(name); err != nil {
+		return nil, err
+	}
+
+	target, err := resolveVolumeTarget(ctx, projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	sourcePath := filepath.Join(target.dir, name+volumeArchiveExt)
+	if _, err := os.Stat(sourcePath); err != nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrFileNotFound,
+			Message: fmt.Sprintf("volume snapshot '%s' not found for volume '%s'", name, target.volume),
+		}
+	}
+
+	if err := executor.VerifyArchive(sourcePath); err != nil {
+		return nil, err
+	}
+
+	opCtx, cancel := withTimeout(ctx, target.cfg, config.OpImport)
+	defer cancel()
+
+	if err := target.stopService(opCtx); err != nil {
+		return nil, interruptedError(opCtx, target.cfg, config.OpImport, err)
+	}
+	defer target.startService(opCtx)
+
+	if err := target.executor.Extract(opCtx, target.volume, sourcePath); err != nil {
+		return nil, interruptedError(opCtx, target.cfg, config.OpImport, fmt.Errorf("failed to restore volume: %w", err))
+	}
+
+	return &types.VolumeRestoreResult{
+		Name:     name,
+		Service:  target.service,
+		Volume:   target.volume,
+		Path:     sourcePath,
+		Duration: time.Since(start),
+	}, nil
+}
+
+// stopService stops the service after making sure its volume exists, since
+// compose only creates it on the first start
+func (t *volumeTarget) stopService(ctx context.Context) error {
+	exists, err := t.executor.VolumeExists(ctx, t.volume)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("volume '%s' of service '%s' does not exist, start the service once first", t.volume, t.service)
+	}
+
+	if err := t.executor.StopService(ctx, t.service); err != nil {
+		return fmt.Errorf("failed to stop service '%s': %w", t.service, err)
+	}
+	return nil
+}
+
+// startService starts the service again, even once ctx is done
+func (t *volumeTarget) startService(ctx context.Context) {
+	cleanupCtx, cancel := cleanupContext(ctx)
+	defer cancel()
+	if err := t.executor.StartService(cleanupCtx, t.service); err != nil {
+		redact.Fprintf(os.Stderr, "Warning: failed to start service '%s' again: %v\n", t.service, err)
+	}
+}
+
+// resolveVolumeTarget finds the named volume of the database service in the
+// compose files and refuses when other worktrees share the service
+func resolveVolumeTarget(ctx context.Context, projectRoot string) (*volumeTarget, error) {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Database == nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for volume snapshots",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	var composeFiles []string
+	if cfg.Docker != nil {
+		composeFiles = cfg.Docker.ComposeFiles
+	}
+	if parsedDSN.Engine == "sqlite" || cfg.Database.Service == "" ||
+		executorMode(cfg, projectRoot, composeFiles) != config.ExecutorDocker {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "volume snapshots need a database running in a compose service (database.service)",
+		}
+	}
+	service := cfg.Database.Service
+
+	docs := readComposeFiles(projectRoot, composeFiles)
+	if len(docs) == 0 {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "volume snapshots need readable compose files (docker.compose_files)",
+		}
+	}
+
+	volume, err := serviceVolume(docs, service, composeProjectName(docs, projectRoot, composeFiles))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkServiceNotShared(ctx, projectRoot, service); err != nil {
+		return nil, err
+	}
+
+	compose, err := newCompose(cfg, composeFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &volumeTarget{
+		cfg:      cfg,
+		service:  service,
+		volume:   volume,
+		dir:      filepath.Join(resolveDumpsPath(cfg, projectRoot), "volumes", volume),
+		executor: executor.NewVolumeExecutor(compose, projectRoot),
+	}, nil
+}
+
+// serviceVolume returns the runtime name of the named volume holding the data
+// of service. Mounts of later compose files override those of earlier ones at
+// the same target, like compose merges them.
+func serviceVolume(docs []DockerCompose, service, project string) (string, error) {
+	mounts := make(map[string]ServiceVolume)
+	declared := make(map[string]*ComposeVolume)
+	found := false
+	for _, dc := range docs {
+		if svc, ok := dc.Services[service]; ok {
+			found = true
+			for _, mount := range svc.Volumes {
+				mounts[mount.Target] = mount
+			}
+		}
+		for key, volume := range dc.Volumes {
+			declared[key] = volume
+		}
+	}
+
+	if !found {
+		return "", &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: fmt.Sprintf("service '%s' is not defined in the compose files", service),
+		}
+	}
+
+	var named []ServiceVolume
+	for _, mount := range mounts {
+		if mount.IsNamed() {
+			named = append(named, mount)
+		}
+	}
+	sort.Slice(named, func(i, j int) bool { return named[i].Target < named[j].Target })
+
+	var chosen *ServiceVolume
+	for _, dir := range dataDirs {
+		for i := range named {
+			if named[i].Target == dir {
+				chosen = &named[i]
+				break
+			}
+		}
+		if chosen != nil {
+			break
+		}
+	}
+
+	switch {
+	case chosen != nil:
+	case len(named) == 1:
+		chosen = &named[0]
+	case len(named) == 0:
+		return "", &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: fmt.Sprintf("service '%s' mounts no named volume", service),
+		}
+	default:
+		var sources []string
+		for _, mount := range named {
+			sources = append(sources, mount.Source+":"+mount.Target)
+		}
+		return "", &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: fmt.Sprintf("service '%s' mounts several named volumes (%s) and none at a known data directory", service, strings.Join(sources, ", ")),
+		}
+	}
+
+	volume := declared[chosen.Source]
+	switch {
+	case volume != nil && volume.Name != "":
+		return volume.Name, nil
+	case volume != nil && volume.External.Name != "":
+		return volume.External.Name, nil
+	case volume != nil && volume.External.Set:
+		return chosen.Source, nil
+	default:
+		return project + "_" + chosen.Source, nil
+	}
+}
+
+// composeProjectName returns the project name compose prefixes volumes with:
+// COMPOSE_PROJECT_NAME, the top-level name of the compose files or the
+// directory of the first compose file
+func composeProjectName(docs []DockerCompose, projectRoot string, composeFiles []string) string {
+	if name := os.Getenv("COMPOSE_PROJECT_NAME"); name != "" {
+		return normalizeProjectName(name)
+	}
+
+	name := ""
+	for _, dc := range docs {
+		if dc.Name != "" {
+			name = dc.Name
+		}
+	}
+	if name != "" {
+		return normalizeProjectName(name)
+	}
+
+	dir := projectRoot
+	if len(composeFiles) > 0 {
+		dir = filepath.Dir(filepath.Join(projectRoot, composeFiles[0]))
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return normalizeProjectName(filepath.Base(dir))
+}
+
+// normalizeProjectName keeps the characters compose allows in project names
+func normalizeProjectName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		default:
+			return -1
+		}
+	}, strings.ToLower(name))
+}
+
+// checkServiceNotShared refuses while the repository has other worktrees.
+// They use the same compose service, so stopping it or replacing its data
+// would pull their databases from under them. Outside of a git repository
+// nothing shares the service; any other failure to list the worktrees
+// refuses, since the service may be shared.
+func checkServiceNotShared(ctx context.Context, projectRoot, service string) error {
+	worktrees, err := executor.GitWorktreeList(ctx, projectRoot)
+	if errors.Is(err, executor.ErrNotGitRepository) {
+		return nil
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("can't tell whether service '%s' is shared with other worktrees: %w", service, err)
+	}
+
+	if others := otherWorktrees(worktrees); len(others) > 0 {
+		return &types.CommandError{
+			Code: types.ErrServiceShared,
+			Message: fmt.Sprintf("service '%s' is shared with the worktrees %s, remove them before snapshotting or restoring its volume",
+				service, strings.Join(others, ", ")),
+		}
+	}
+	return nil
+}
+
+// otherWorktrees returns the paths of the worktrees besides the current one
+func otherWorktrees(worktrees []types.WorktreeInfo) []string {
+	var others []string
+	for _, wt := range worktrees {
+		if !wt.IsMain {
+			others = append(others, wt.Path)
+		}
+	}
+	return others
+}
@@ -0,0 +1,422 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"gopkg.in/yaml.v3"
+)
+
+func parseCompose(t *testing.T, content string) DockerCompose {
+	t.Helper()
+	var dc DockerCompose
+	if err := yaml.Unmarshal([]byte(content), &dc); err != nil {
+		t.Fatal(err)
+	}
+	return dc
+}
+
+func TestServiceVolume(t *testing.T) {
+	tests := []struct {
+		name     string
+		compose  string
+		expected string
+		errPart  string
+	}{
+		{
+			name: "short syntax gets the project prefix",
+			compose: `
+services:
+  database:
+    volumes:
+      - db-data:/var/lib/mysql:rw
+      - ./docker/my.cnf:/etc/mysql/conf.d/my.cnf
+volumes:
+  db-data:
+`,
+			expected: "shop_db-data",
+		},
+		{
+			name: "long syntax with an explicit name",
+			compose: `
+services:
+  database:
+    volumes:
+      - type: volume
+        source: pgdata
+        target: /var/lib/postgresql/data
+volumes:
+  pgdata:
+    name: shared-pgdata
+`,
+			expected: "shared-pgdata",
+		},
+		{
+			name: "external volume keeps its name",
+			compose: `
+services:
+  database:
+    volumes:
+      - db-data:/var/lib/mysql
+volumes:
+  db-data:
+    external: true
+`,
+			expected: "db-data",
+		},
+		{
+			name: "legacy external name",
+			compose: `
+services:
+  database:
+    volumes:
+      - db-data:/var/lib/mysql
+volumes:
+  db-data:
+    external:
+      name: legacy-data
+`,
+			expected: "legacy-data",
+		},
+		{
+			name: "data directory wins over other volumes",
+			compose: `
+services:
+  database:
+    volumes:
+      - db-logs:/var/log/mysql
+      - db-data:/var/lib/mysql
+`,
+			expected: "shop_db-data",
+		},
+		{
+			name: "several volumes without a data directory",
+			compose: `
+services:
+  database:
+    volumes:
+      - a:/data/a
+      - b:/data/b
+`,
+			errPart: "several named volumes",
+		},
+		{
+			name: "bind mounts only",
+			compose: `
+services:
+  database:
+    volumes:
+      - ./var/mysql:/var/lib/mysql
+      - /var/lib/mysql-files
+`,
+			errPart: "no named volume",
+		},
+		{
+			name: "service not defined",
+			compose: `
+services:
+  php:
+    image: php:8.3
+`,
+			errPart: "not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volume, err := serviceVolume([]DockerCompose{parseCompose(t, tt.compose)}, "database", "shop")
+			if tt.errPart != "" {
+				cmdErr, ok := err.(*types.CommandError)
+				if !ok || cmdErr.Code != types.ErrConfigInvalid || !strings.Contains(cmdErr.Message, tt.errPart) {
+					t.Errorf("serviceVolume() error = %v, want ErrConfigInvalid containing %q", err, tt.errPart)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("serviceVolume() error = %v", err)
+			}
+			if volume != tt.expected {
+				t.Errorf("serviceVolume() = %q, want %q", volume, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServiceVolumeOverride(t *testing.T) {
+	base := parseCompose(t, `
+services:
+  database:
+    volumes:
+      - db-data:/var/lib/postgresql/data
+`)
+	override := parseCompose(t, `
+services:
+  database:
+    volumes:
+      - db-test:/var/lib/postgresql/data
+`)
+
+	volume, err := serviceVolume([]DockerCompose{base, override}, "database", "shop")
+	if err != nil {
+		t.Fatalf("serviceVolume() error = %v", err)
+	}
+	if volume != "shop_db-test" {
+		t.Errorf("serviceVolume() = %q, want the overriding shop_db-test", volume)
+	}
+}
+
+func TestComposeProjectName(t *testing.T) {
+	t.Setenv("COMPOSE_PROJECT_NAME", "")
+	projectRoot := filepath.Join(t.TempDir(), "My.Shop")
+
+	if got := composeProjectName(nil, projectRoot, []string{"compose.yaml"}); got != "myshop" {
+		t.Errorf("directory name: got %q, want myshop", got)
+	}
+	if got := composeProjectName(nil, projectRoot, []string{"docker/compose.yaml"}); got != "docker" {
+		t.Errorf("directory of the first file: got %q, want docker", got)
+	}
+
+	docs := []DockerCompose{{Name: "shop"}, {}}
+	if got := composeProjectName(docs, projectRoot, []string{"compose.yaml"}); got != "shop" {
+		t.Errorf("top-level name: got %q, want shop", got)
+	}
+
+	t.Setenv("COMPOSE_PROJECT_NAME", "Override")
+	if got := composeProjectName(docs, projectRoot, []string{"compose.yaml"}); got != "override" {
+		t.Errorf("COMPOSE_PROJECT_NAME: got %q, want override", got)
+	}
+}
+
+func TestOtherWorktrees(t *testing.T) {
+	worktrees := []types.WorktreeInfo{
+		{Path: "/src/shop", Branch: "main", IsMain: true},
+		{Path: "/src/shop-worktrees/feature-a", Branch: "feature/a"},
+	}
+
+	others := otherWorktrees(worktrees)
+	if len(others) != 1 || others[0] != "/src/shop-worktrees/feature-a" {
+		t.Errorf("otherWorktrees() = %v", others)
+	}
+	if others := otherWorktrees(worktrees[:1]); len(others) != 0 {
+		t.Errorf("otherWorktrees() of a single worktree = %v, want none", others)
+	}
+}
+
+// setupVolumeProject writes a project whose database service mounts the
+// volume shop_db-data, outside of any git repository
+func setupVolumeProject(t *testing.T) string {
+	t.Helper()
+	t.Setenv("COMPOSE_PROJECT_NAME", "")
+	t.Chdir(t.TempDir())
+	projectRoot := setupSnapshotProject(t)
+
+	compose := `name: shop
+services:
+  database:
+    image: mysql:8.0
+    volumes:
+      - db-data:/var/lib/mysql
+volumes:
+  db-data:
+`
+	if err := os.WriteFile(filepath.Join(projectRoot, "docker-compose.yaml"), []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return projectRoot
+}
+
+// volumeArchive returns the path of a valid gzipped tarball
+func volumeArchive(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ibdata1"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "volume.tar.gz")
+	if output, err := exec.Command("tar", "czf", path, "-C", dir, ".").CombinedOutput(); err != nil {
+		t.Skipf("tar not available: %v %s", err, output)
+	}
+	return path
+}
+
+func TestVolumeSnapshot(t *testing.T) {
+	projectRoot := setupVolumeProject(t)
+	archive := volumeArchive(t)
+	logPath := fakeDocker(t, `case "$*" in
+  *" tar czf "*) cat "`+archive+`" ;;
+esac`)
+
+	result, err := VolumeSnapshot(context.Background(), projectRoot, "seeded")
+	if err != nil {
+		t.Fatalf("VolumeSnapshot() error = %v", err)
+	}
+
+	expectedPath := filepath.Join(projectRoot, "var", "dumps", "volumes", "shop_db-data", "seeded.tar.gz")
+	if result.Path != expectedPath || result.Volume != "shop_db-data" || result.Service != "database" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := os.Stat(expectedPath); err != nil {
+		t.Errorf("archive not written: %v", err)
+	}
+
+	calls, _ := os.ReadFile(logPath)
+	lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+	expected := []string{"volume inspect shop_db-data", "stop database", "run --rm -v shop_db-data:/volume:ro", "start database"}
+	if len(lines) != len(expected) {
+		t.Fatalf("unexpected calls:\n%s", calls)
+	}
+	for i, part := range expected {
+		if !strings.Contains(lines[i], part) {
+			t.Errorf("call %d = %q, want it to contain %q", i, lines[i], part)
+		}
+	}
+
+	if _, err := VolumeSnapshot(context.Background(), projectRoot, "seeded"); err == nil {
+		t.Error("second snapshot with the same name succeeded")
+	} else if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrAlreadyExists {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestVolumeSnapshotStartsServiceAfterFailure(t *testing.T) {
+	projectRoot := setupVolumeProject(t)
+	logPath := fakeDocker(t, `case "$*" in
+  *" tar czf "*) echo "disk full" >&2; exit 1 ;;
+esac`)
+
+	if _, err := VolumeSnapshot(context.Background(), projectRoot, "seeded"); err == nil {
+		t.Fatal("VolumeSnapshot() succeeded, want error")
+	}
+
+	calls, _ := os.ReadFile(logPath)
+	if !strings.HasSuffix(strings.TrimSpace(string(calls)), "start database") {
+		t.Errorf("service not started again:\n%s", calls)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "var", "dumps", "volumes", "shop_db-data", "seeded.tar.gz")); err == nil {
+		t.Error("failed snapshot left an archive behind")
+	}
+}
+
+func TestVolumeRestore(t *testing.T) {
+	projectRoot := setupVolumeProject(t)
+	dir := filepath.Join(projectRoot, "var", "dumps", "volumes", "shop_db-data")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(volumeArchive(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "seeded.tar.gz"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.tar.gz"), data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	logPath := fakeDocker(t, `cat > /dev/null`)
+
+	t.Run("missing snapshot", func(t *testing.T) {
+		_, err := VolumeRestore(context.Background(), projectRoot, "missing")
+		if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrFileNotFound {
+			t.Errorf("expected ErrFileNotFound, got %v", err)
+		}
+	})
+
+	t.Run("corrupt archive leaves the service running", func(t *testing.T) {
+		if _, err := VolumeRestore(context.Background(), projectRoot, "broken"); err == nil {
+			t.Fatal("VolumeRestore() succeeded, want error")
+		}
+		if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+			t.Errorf("docker called for a corrupt archive:\n%s", calls)
+		}
+	})
+
+	t.Run("restores the volume", func(t *testing.T) {
+		result, err := VolumeRestore(context.Background(), projectRoot, "seeded")
+		if err != nil {
+			t.Fatalf("VolumeRestore() error = %v", err)
+		}
+		if result.Volume != "shop_db-data" {
+			t.Errorf("unexpected result %+v", result)
+		}
+
+		calls, _ := os.ReadFile(logPath)
+		lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+		expected := []string{"volume inspect shop_db-data", "stop database", "run --rm -i -v shop_db-data:/volume", "start database"}
+		if len(lines) != len(expected) {
+			t.Fatalf("unexpected calls:\n%s", calls)
+		}
+		for i, part := range expected {
+			if !strings.Contains(lines[i], part) {
+				t.Errorf("call %d = %q, want it to contain %q", i, lines[i], part)
+			}
+		}
+	})
+}
+
+func TestVolumeSnapshotRequiresComposeService(t *testing.T) {
+	t.Chdir(t.TempDir())
+	projectRoot := t.TempDir()
+	cfgContent := `{"database": {"dsn": "sqlite:///var/data.db"}}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".haive.json"), []byte(cfgContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := VolumeSnapshot(context.Background(), projectRoot, "seeded")
+	if cmdErr, ok := err.(*types.CommandError); !ok || cmdErr.Code != types.ErrConfigInvalid {
+		t.Errorf("expected ErrConfigInvalid, got %v", err)
+	}
+}
+
+func TestVolumeRestoreRefusedWhenGitFails(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, projectRoot string)
+	}{
+		{"unreadable .git", func(t *testing.T, projectRoot string) {
+			if err := os.Mkdir(filepath.Join(projectRoot, ".git"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"failing git", func(t *testing.T, projectRoot string) {
+			binDir := t.TempDir()
+			script := "#!/bin/sh\necho 'fatal: unable to read index' >&2\nexit 128\n"
+			if err := os.WriteFile(filepath.Join(binDir, "git"), []byte(script), 0755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRoot := setupVolumeProject(t)
+			dir := filepath.Join(projectRoot, "var", "dumps", "volumes", "shop_db-data")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(volumeArchive(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "seeded.tar.gz"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			logPath := fakeDocker(t, `cat > /dev/null`)
+			tt.setup(t, projectRoot)
+
+			_, err = VolumeRestore(context.Background(), projectRoot, "seeded")
+			if err == nil || !strings.Contains(err.Error(), "can't tell whether service 'database' is shared") {
+				t.Fatalf("expected the restore to be refused, got %v", err)
+			}
+			if calls, _ := os.ReadFile(logPath); len(calls) != 0 {
+				t.Errorf("docker called although the worktrees are unknown:\n%s", calls)
+			}
+		})
+	}
+}
@@ -1,22 +1,26 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
 )
 
-func CreateIsolatedWorktree(projectRoot, branch, newBranch, newDB string) (*types.WorkflowCreateResult, error) {
+func CreateIsolatedWorktree(ctx context.Context, projectRoot, branch, newBranch, newDB string) (*types.WorkflowCreateResult, error) {
 	_ = newDB // unused - controlled by config.Worktrees.DBPerWorktree
 
 	newBranchBool, _ := strconv.ParseBool(newBranch)
-	result, err := Create(projectRoot, branch, newBranchBool)
+	result, err := Create(ctx, projectRoot, branch, newBranchBool)
 	if err != nil {
 		return nil, err
 	}
@@ -35,10 +39,14 @@ func CreateIsolatedWorktree(projectRoot, branch, newBranch, newDB string) (*type
 		return workflowResult, nil
 	}
 
+	if dsn.IsSQLite(cfg.Database.DSN) {
+		return isolateSQLiteDatabase(ctx, cfg, result.Path, workflowResult)
+	}
+
 	_, dbName := core.SanitizeWorktreeName(branch)
 	targetDB := cfg.Worktrees.DBPrefix + dbName
 
-	cloneResult, err := CloneDB(projectRoot, "", targetDB)
+	cloneResult, err := CloneDB(ctx, projectRoot, "", targetDB, types.CloneOptions{})
 	if err != nil {
 		return workflowResult, fmt.Errorf("worktree created but database clone failed: %w", err)
 	}
@@ -59,8 +67,47 @@ func CreateIsolatedWorktree(projectRoot, branch, newBranch, newDB string) (*type
 	return workflowResult, nil
 }
 
-func RemoveIsolatedWorktree(projectRoot, branch string, dropDB bool) (*types.WorkflowRemoveResult, error) {
-	result, err := Remove(projectRoot, branch)
+// isolateSQLiteDatabase copies the default SQLite database to the same place
+// inside the worktree and points the worktree's DATABASE_URL at that copy
+func isolateSQLiteDatabase(ctx context.Context, cfg *config.Config, worktreePath string, workflowResult *types.WorkflowCreateResult) (*types.WorkflowCreateResult, error) {
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return workflowResult, fmt.Errorf("worktree created but DSN parsing failed: %w", err)
+	}
+
+	source := sqliteFile(parsedDSN, cfg.ProjectRoot)
+	rel, err := filepath.Rel(cfg.ProjectRoot, source)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return workflowResult, fmt.Errorf("worktree created but the SQLite database %s is outside the project and can't be copied into it", source)
+	}
+
+	dbExecutor, _, err := newDatabaseExecutor(cfg, parsedDSN, cfg.ProjectRoot)
+	if err != nil {
+		return workflowResult, fmt.Errorf("worktree created but database setup failed: %w", err)
+	}
+	fileExecutor := dbExecutor.(*executor.FileDatabaseExecutor)
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpClone)
+	defer cancel()
+
+	if _, err := fileExecutor.CopyFile(opCtx, parsedDSN, parsedDSN.Database, filepath.Join(worktreePath, rel)); err != nil {
+		return workflowResult, fmt.Errorf("worktree created but database copy failed: %w", interruptedError(opCtx, cfg, config.OpClone, err))
+	}
+
+	envPath := filepath.Join(worktreePath, ".env.local")
+	newDSN := "sqlite:///%kernel.project_dir%/" + filepath.ToSlash(rel)
+	if err := os.WriteFile(envPath, []byte("DATABASE_URL="+newDSN+"\n"), 0644); err != nil {
+		return workflowResult, fmt.Errorf("worktree and DB created but .env.local patch failed: %w", err)
+	}
+
+	workflowResult.DatabaseName = parsedDSN.Database
+	workflowResult.ClonedFrom = parsedDSN.Database
+
+	return workflowResult, nil
+}
+
+func RemoveIsolatedWorktree(ctx context.Context, projectRoot, branch string, dropDB bool) (*types.WorkflowRemoveResult, error) {
+	result, err := Remove(ctx, projectRoot, branch)
 	if err != nil {
 		return nil, err
 	}
@@ -78,14 +125,15 @@ func RemoveIsolatedWorktree(projectRoot, branch string, dropDB bool) (*types.Wor
 		return workflowResult, nil
 	}
 
-	if cfg.Worktrees == nil || cfg.Database == nil {
+	// A SQLite copy lives inside the worktree and went away with it
+	if cfg.Worktrees == nil || cfg.Database == nil || dsn.IsSQLite(cfg.Database.DSN) {
 		return workflowResult, nil
 	}
 
 	_, dbName := core.SanitizeWorktreeName(branch)
 	targetDB := cfg.Worktrees.DBPrefix + dbName
 
-	_, err = DropDB(projectRoot, targetDB)
+	dropResult, err := DropDB(ctx, projectRoot, targetDB)
 	if err != nil {
 		if cmdErr, ok := err.(*types.CommandError); ok && cmdErr.Code == types.ErrDbNotAllowed {
 			return workflowResult, nil
@@ -94,6 +142,7 @@ func RemoveIsolatedWorktree(projectRoot, branch string, dropDB bool) (*types.Wor
 	}
 
 	workflowResult.DatabaseName = targetDB
+	workflowResult.DatabaseBackup = dropResult.Backup
 
 	return workflowResult, nil
 }
@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
 func TestCreateIsolatedWorktreeNoDB(t *testing.T) {
@@ -24,7 +28,7 @@ func TestCreateIsolatedWorktreeNoDB(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = CreateIsolatedWorktree(tmpDir, "feature/test", "true", "")
+	_, err = CreateIsolatedWorktree(context.Background(), tmpDir, "feature/test", "true", "")
 	if err == nil {
 		t.Error("expected error (git not available)")
 	}
@@ -48,7 +52,7 @@ func TestRemoveIsolatedWorktreeNoDB(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = RemoveIsolatedWorktree(tmpDir, "feature/test", false)
+	_, err = RemoveIsolatedWorktree(context.Background(), tmpDir, "feature/test", false)
 	if err == nil {
 		t.Error("expected error (git not available)")
 	}
@@ -80,8 +84,36 @@ func TestRemoveIsolatedWorktreeWithDB(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = RemoveIsolatedWorktree(tmpDir, "feature/test", true)
+	_, err = RemoveIsolatedWorktree(context.Background(), tmpDir, "feature/test", true)
 	if err == nil {
 		t.Error("expected error (git not available)")
 	}
 }
+
+func TestIsolateSQLiteDatabase(t *testing.T) {
+	projectRoot := setupSQLiteProject(t)
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktreePath := filepath.Join(projectRoot, "wt", "feature-test")
+
+	result, err := isolateSQLiteDatabase(context.Background(), cfg, worktreePath, &types.WorkflowCreateResult{WorktreePath: worktreePath})
+	if err != nil {
+		t.Fatalf("isolateSQLiteDatabase() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(worktreePath, "var", "data.db")); err != nil {
+		t.Errorf("expected the database copy in the worktree: %v", err)
+	}
+	env, err := os.ReadFile(filepath.Join(worktreePath, ".env.local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(env) != "DATABASE_URL=sqlite:///%kernel.project_dir%/var/data.db\n" {
+		t.Errorf("unexpected .env.local %q", env)
+	}
+	if result.DatabaseName != "data" || result.ClonedFrom != "data" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,7 +11,9 @@ import (
 
 	pmcore "github.com/mkrowiarz/mcp-symfony-stack/internal/core"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/hooks"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 	worktreepkg "github.com/mkrowiarz/mcp-symfony-stack/internal/core/worktree"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
@@ -25,8 +28,10 @@ func isTerminal() bool {
 	return fileInfo.Mode()&os.ModeCharDevice != 0
 }
 
-func List(projectRoot string) ([]types.WorktreeInfo, error) {
-	externalWorktrees, err := executor.GitWorktreeList()
+// List lists the git worktrees. withMigrations compares the migrations of each
+// worktree with the database it uses.
+func List(ctx context.Context, projectRoot string, withMigrations bool) ([]types.WorktreeInfo, error) {
+	externalWorktrees, err := executor.GitWorktreeList(ctx, projectRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -40,11 +45,55 @@ func List(projectRoot string) ([]types.WorktreeInfo, error) {
 		}
 	}
 
+	if withMigrations {
+		if err := addWorktreeMigrations(ctx, projectRoot, result); err != nil {
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
+func addWorktreeMigrations(ctx context.Context, projectRoot string, worktrees []types.WorktreeInfo) error {
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Database == nil {
+		return &types.CommandError{
+			Code:    types.ErrConfigMissing,
+			Message: "database configuration is required for migration operations",
+		}
+	}
+
+	parsedDSN, err := dsn.ParseDSN(cfg.Database.DSN)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := withTimeout(ctx, cfg, config.OpQuery)
+	defer cancel()
+
+	for i := range worktrees {
+		wt := &worktrees[i]
+		status, err := worktreeMigrations(opCtx, cfg, parsedDSN, wt.Path)
+		if err != nil {
+			if opCtx.Err() != nil {
+				return interruptedError(opCtx, cfg, config.OpQuery, err)
+			}
+			// A branch without migrations shouldn't hide the others
+			redact.Fprintf(os.Stderr, "Warning: no migration status for %s: %v\n", wt.Path, err)
+			continue
+		}
+		wt.Migrations = status
+	}
+
+	return nil
+}
+
 // PreValidateWorktree checks if worktree creation prerequisites are met
-func PreValidateWorktree(cfg *config.HaiveConfig, branch string) error {
+func PreValidateWorktree(ctx context.Context, cfg *config.HaiveConfig, branch string) error {
 	// Check branch name is valid
 	if branch == "" {
 		return &types.CommandError{
@@ -72,7 +121,7 @@ func PreValidateWorktree(cfg *config.HaiveConfig, branch string) error {
 	}
 
 	// Check if worktree already exists (git or directory)
-	gitWorktrees, err := executor.GitWorktreeList()
+	gitWorktrees, err := executor.GitWorktreeList(ctx, cfg.ProjectRoot)
 	if err != nil {
 		return err
 	}
@@ -104,7 +153,7 @@ func PreValidateWorktree(cfg *config.HaiveConfig, branch string) error {
 	return nil
 }
 
-func Create(projectRoot string, branch string, newBranch bool) (*types.WorktreeCreateResult, error) {
+func Create(ctx context.Context, projectRoot string, branch string, newBranch bool) (*types.WorktreeCreateResult, error) {
 	cfg, err := config.LoadHaive(projectRoot)
 	if err != nil {
 		return nil, err
@@ -118,7 +167,7 @@ func Create(projectRoot string, branch string, newBranch bool) (*types.WorktreeC
 	}
 
 	// Pre-validation
-	if err := PreValidateWorktree(cfg, branch); err != nil {
+	if err := PreValidateWorktree(ctx, cfg, branch); err != nil {
 		return nil, err
 	}
 
@@ -129,14 +178,14 @@ func Create(projectRoot string, branch string, newBranch bool) (*types.WorktreeC
 		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
 	}
 
-	if err := executor.GitWorktreeAdd(worktreePath, branch, newBranch); err != nil {
+	if err := executor.GitWorktreeAdd(ctx, worktreePath, branch, newBranch); err != nil {
 		return nil, err
 	}
 
 	// 1. Copy files
 	if cfg.Worktree.Copy != nil {
 		if err := worktreepkg.CopyFiles(cfg.ProjectRoot, worktreePath, cfg.Worktree.Copy); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to copy worktree files: %v\n", err)
+			redact.Fprintf(os.Stderr, "Warning: failed to copy worktree files: %v\n", err)
 		}
 	}
 
@@ -150,8 +199,8 @@ func Create(projectRoot string, branch string, newBranch bool) (*types.WorktreeC
 			Branch:       branch,
 		}
 
-		if err := hookExec.ExecuteHooks(cfg.Worktree.Hooks.PostCreate, hookCtx, worktreePath, false); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: postCreate hook failed: %v\n", err)
+		if err := hookExec.ExecuteHooks(ctx, cfg.Worktree.Hooks.PostCreate, hookCtx, worktreePath, false); err != nil {
+			redact.Fprintf(os.Stderr, "Warning: postCreate hook failed: %v\n", err)
 		}
 	}
 
@@ -161,7 +210,7 @@ func Create(projectRoot string, branch string, newBranch bool) (*types.WorktreeC
 	}, nil
 }
 
-func Remove(projectRoot string, branch string) (*types.WorktreeRemoveResult, error) {
+func Remove(ctx context.Context, projectRoot string, branch string) (*types.WorktreeRemoveResult, error) {
 	cfg, err := config.LoadHaive(projectRoot)
 	if err != nil {
 		return nil, err
@@ -195,12 +244,12 @@ func Remove(projectRoot string, branch string) (*types.WorktreeRemoveResult, err
 			Branch:       branch,
 		}
 
-		if err := hookExec.ExecuteHooks(cfg.Worktree.Hooks.PreRemove, hookCtx, cfg.ProjectRoot, true); err != nil {
+		if err := hookExec.ExecuteHooks(ctx, cfg.Worktree.Hooks.PreRemove, hookCtx, cfg.ProjectRoot, true); err != nil {
 			return nil, fmt.Errorf("preRemove hook prevented removal: %w", err)
 		}
 	}
 
-	if err := executor.GitWorktreeRemove(worktreePath); err != nil {
+	if err := executor.GitWorktreeRemove(ctx, worktreePath); err != nil {
 		return nil, err
 	}
 
@@ -213,8 +262,8 @@ func Remove(projectRoot string, branch string) (*types.WorktreeRemoveResult, err
 			Branch:       branch,
 		}
 
-		if err := hookExec.ExecuteHooks(cfg.Worktree.Hooks.PostRemove, hookCtx, cfg.ProjectRoot, false); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: postRemove hook failed: %v\n", err)
+		if err := hookExec.ExecuteHooks(ctx, cfg.Worktree.Hooks.PostRemove, hookCtx, cfg.ProjectRoot, false); err != nil {
+			redact.Fprintf(os.Stderr, "Warning: postRemove hook failed: %v\n", err)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -75,7 +76,7 @@ func TestCreateWorktree_WithCopyAndHooks(t *testing.T) {
 	cmd.Run()
 
 	// Create worktree using the Create command
-	result, err := Create(repoDir, "feature/test", true)
+	result, err := Create(context.Background(), repoDir, "feature/test", true)
 	if err != nil {
 		t.Fatalf("failed to create worktree: %v", err)
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/config"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor"
 )
 
 type ServeResult struct {
@@ -65,8 +66,13 @@ func Serve(projectRoot string) (*ServeResult, error) {
 	// 5. Generate unique project name
 	projectName := generateProjectName(cfg, branch)
 
+	compose, err := newCompose(cfg, composeFiles)
+	if err != nil {
+		return nil, err
+	}
+
 	// 6. Start containers
-	if err := startContainers(projectRoot, projectName, composeFiles); err != nil {
+	if err := startContainers(projectRoot, projectName, compose); err != nil {
 		return nil, fmt.Errorf("failed to start containers: %w", err)
 	}
 
@@ -117,14 +123,13 @@ func Stop(projectRoot string) error {
 	// Generate project name
 	projectName := generateProjectName(cfg, branch)
 
-	// Build docker compose command with all compose files
-	args := []string{"compose"}
-	for _, f := range composeFiles {
-		args = append(args, "-f", f)
+	compose, err := newCompose(cfg, composeFiles)
+	if err != nil {
+		return err
 	}
-	args = append(args, "-p", projectName, "down")
 
-	cmd := exec.Command("docker", args...)
+	args := compose.Command("-p", projectName, "down")
+	cmd := exec.Command(args[0], args[1:]...)
 	cmd.Dir = projectRoot
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -178,15 +183,10 @@ func generateProjectName(cfg *config.Config, branch string) string {
 	return fmt.Sprintf("%s-wt-%s", baseProject, sanitized)
 }
 
-// startContainers starts containers with docker compose using configured files
-func startContainers(projectRoot, projectName string, composeFiles []string) error {
-	args := []string{"compose"}
-	for _, f := range composeFiles {
-		args = append(args, "-f", f)
-	}
-	args = append(args, "-p", projectName, "up", "-d")
-
-	cmd := exec.Command("docker", args...)
+// startContainers starts the containers of the compose project
+func startContainers(projectRoot, projectName string, compose executor.Compose) error {
+	args := compose.Command("-p", projectName, "up", "-d")
+	cmd := exec.Command(args[0], args[1:]...)
 	cmd.Dir = projectRoot
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
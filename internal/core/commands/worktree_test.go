@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -98,14 +99,14 @@ func TestWorktreeCreateValidation(t *testing.T) {
 	}
 
 	t.Run("empty branch name", func(t *testing.T) {
-		_, err := Create(tmpDir, "", false)
+		_, err := Create(context.Background(), tmpDir, "", false)
 		if err == nil {
 			t.Error("expected error for empty branch name")
 		}
 	})
 
 	t.Run("invalid branch name", func(t *testing.T) {
-		_, err := Create(tmpDir, "test;rm -rf", false)
+		_, err := Create(context.Background(), tmpDir, "test;rm -rf", false)
 		if err == nil {
 			t.Error("expected error for invalid branch name")
 		}
@@ -130,14 +131,14 @@ func TestWorktreeRemoveValidation(t *testing.T) {
 	}
 
 	t.Run("empty branch name", func(t *testing.T) {
-		_, err := Remove(tmpDir, "")
+		_, err := Remove(context.Background(), tmpDir, "")
 		if err == nil {
 			t.Error("expected error for empty branch name")
 		}
 	})
 
 	t.Run("invalid branch name", func(t *testing.T) {
-		_, err := Remove(tmpDir, "test;rm -rf")
+		_, err := Remove(context.Background(), tmpDir, "test;rm -rf")
 		if err == nil {
 			t.Error("expected error for invalid branch name")
 		}
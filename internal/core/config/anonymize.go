@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/anonymize"
+)
+
+// Anonymize declares how personal data is rewritten when a dump or clone is
+// run with --anonymize
+type Anonymize struct {
+	// Seed keys the replacement values so they are stable across dumps; may reference ${ENV_VARS}
+	Seed string `json:"seed" toml:"seed"`
+	// Rules maps table -> column -> rule (email, hash, null, constant:<value> or keep)
+	Rules map[string]map[string]string `json:"rules" toml:"rules"`
+}
+
+// NewAnonymizer parses the rules and returns an anonymizer keyed with the seed
+func (a *Anonymize) NewAnonymizer() (*anonymize.Anonymizer, error) {
+	rules, err := anonymize.ParseRules(a.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return anonymize.New(a.Seed, rules), nil
+}
+
+func (a *Anonymize) validate() error {
+	if len(a.Rules) == 0 {
+		return fmt.Errorf("database.anonymize.rules must declare at least one table")
+	}
+	if a.Seed == "" {
+		return fmt.Errorf("database.anonymize.seed is required so anonymized values stay consistent between dumps")
+	}
+	if _, err := anonymize.ParseRules(a.Rules); err != nil {
+		return fmt.Errorf("database.anonymize.rules: %w", err)
+	}
+	return nil
+}
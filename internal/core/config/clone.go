@@ -0,0 +1,25 @@
+package config
+
+// CloneMode selects how database clones copy the data
+type CloneMode string
+
+const (
+	// CloneAuto tries the server-side strategies the engine supports and falls
+	// back to dump. It is the default.
+	CloneAuto CloneMode = "auto"
+	// CloneDump always pipes a dump of the source into the target
+	CloneDump CloneMode = "dump"
+	// CloneTemplate creates the target from the source as a PostgreSQL template
+	CloneTemplate CloneMode = "template"
+	// CloneCopy copies the tables on the MySQL server, or the SQLite file
+	CloneCopy CloneMode = "copy"
+)
+
+// IsValid reports whether m is a known mode; empty means auto
+func (m CloneMode) IsValid() bool {
+	switch m {
+	case "", CloneAuto, CloneDump, CloneTemplate, CloneCopy:
+		return true
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
@@ -22,17 +23,29 @@ type Config struct {
 }
 
 type Docker struct {
-	ComposeFiles []string `json:"compose_files,omitempty" toml:"compose_files,omitempty"`
-	ProjectName  string   `json:"project_name,omitempty" toml:"project_name,omitempty"`
+	ComposeFiles []string             `json:"compose_files,omitempty" toml:"compose_files,omitempty"`
+	ProjectName  string               `json:"project_name,omitempty" toml:"project_name,omitempty"`
+	Runtime      types.ComposeRuntime `json:"runtime,omitempty" toml:"runtime,omitempty"`
 }
 
 type Database struct {
-	Service   string         `json:"service" toml:"service"`
-	DSN       string         `json:"dsn" toml:"dsn"`
-	Allowed   []string       `json:"allowed" toml:"allowed"`
-	DumpsPath string         `json:"dumps_path,omitempty" toml:"dumps_path,omitempty"`
-	Prefix    string         `json:"prefix,omitempty" toml:"prefix,omitempty"`
-	Hooks     *DatabaseHooks `json:"hooks,omitempty" toml:"hooks,omitempty"`
+	Service         string            `json:"service" toml:"service"`
+	Executor        ExecutorMode      `json:"executor,omitempty" toml:"executor,omitempty"`
+	DSN             string            `json:"dsn" toml:"dsn"`
+	Allowed         []string          `json:"allowed" toml:"allowed"`
+	DumpsPath       string            `json:"dumps_path,omitempty" toml:"dumps_path,omitempty"`
+	DumpCompression types.Compression `json:"dump_compression,omitempty" toml:"dump_compression,omitempty"`
+	CloneStrategy   CloneMode         `json:"clone_strategy,omitempty" toml:"clone_strategy,omitempty"`
+	Prefix          string            `json:"prefix,omitempty" toml:"prefix,omitempty"`
+	Hooks           *DatabaseHooks    `json:"hooks,omitempty" toml:"hooks,omitempty"`
+	Retention       *Retention        `json:"retention,omitempty" toml:"retention,omitempty"`
+	Anonymize       *Anonymize        `json:"anonymize,omitempty" toml:"anonymize,omitempty"`
+	Timeouts        *Timeouts         `json:"timeouts,omitempty" toml:"timeouts,omitempty"`
+	Migrations      *Migrations       `json:"migrations,omitempty" toml:"migrations,omitempty"`
+	// BackupBeforeDestructive dumps a database into <dumps_path>/.trash before
+	// it is dropped or imported into, see haive db undo
+	BackupBeforeDestructive bool   `json:"backup_before_destructive,omitempty" toml:"backup_before_destructive,omitempty"`
+	Trash                   *Trash `json:"trash,omitempty" toml:"trash,omitempty"`
 }
 
 type Worktrees struct {
@@ -176,11 +189,26 @@ func validateConfig(cfg *Config, projectRoot string) (*Config, error) {
 		}
 	}
 
+	if cfg.Docker != nil && !cfg.Docker.Runtime.IsValid() {
+		return nil, &types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: fmt.Sprintf("docker.runtime must be one of auto, docker, docker-compose, podman, nerdctl (got %q)", cfg.Docker.Runtime),
+		}
+	}
+
 	if cfg.Database != nil {
-		if cfg.Database.Service == "" {
+		if !cfg.Database.Executor.IsValid() {
+			return nil, &types.CommandError{
+				Code:    types.ErrConfigInvalid,
+				Message: fmt.Sprintf("database.executor must be one of auto, docker, host (got %q)", cfg.Database.Executor),
+			}
+		}
+		// SQLite databases are files and host clients connect to the DSN,
+		// there is no service to exec into
+		if cfg.Database.Service == "" && cfg.Database.Executor != ExecutorHost && !dsn.IsSQLite(cfg.Database.DSN) {
 			return nil, &types.CommandError{
 				Code:    types.ErrConfigInvalid,
-				Message: "database.service is required when database section is present",
+				Message: "database.service is required when database section is present, unless database.executor is \"host\"",
 			}
 		}
 		if cfg.Database.DSN == "" {
@@ -195,7 +223,62 @@ func validateConfig(cfg *Config, projectRoot string) (*Config, error) {
 				Message: "database.allowed is required when database section is present (e.g., [\"myapp\", \"myapp_*\"])",
 			}
 		}
+		if !cfg.Database.DumpCompression.IsValid() {
+			return nil, &types.CommandError{
+				Code:    types.ErrConfigInvalid,
+				Message: fmt.Sprintf("database.dump_compression must be one of none, gzip, zstd (got %q)", cfg.Database.DumpCompression),
+			}
+		}
+		if !cfg.Database.CloneStrategy.IsValid() {
+			return nil, &types.CommandError{
+				Code:    types.ErrConfigInvalid,
+				Message: fmt.Sprintf("database.clone_strategy must be one of auto, dump, template, copy (got %q)", cfg.Database.CloneStrategy),
+			}
+		}
+		if cfg.Database.Retention != nil {
+			if err := cfg.Database.Retention.validate(); err != nil {
+				return nil, &types.CommandError{
+					Code:    types.ErrConfigInvalid,
+					Message: err.Error(),
+				}
+			}
+		}
+		if cfg.Database.Timeouts != nil {
+			if err := cfg.Database.Timeouts.validate(); err != nil {
+				return nil, &types.CommandError{
+					Code:    types.ErrConfigInvalid,
+					Message: err.Error(),
+				}
+			}
+		}
+		if cfg.Database.Migrations != nil {
+			if err := cfg.Database.Migrations.validate(); err != nil {
+				return nil, &types.CommandError{
+					Code:    types.ErrConfigInvalid,
+					Message: err.Error(),
+				}
+			}
+		}
+		if cfg.Database.Trash != nil {
+			if err := cfg.Database.Trash.validate(); err != nil {
+				return nil, &types.CommandError{
+					Code:    types.ErrConfigInvalid,
+					Message: err.Error(),
+				}
+			}
+		}
 		cfg.Database.DSN = ResolveEnvVars(cfg.Database.DSN, projectRoot)
+		registerSecrets(cfg.Database.DSN)
+		if cfg.Database.Anonymize != nil {
+			cfg.Database.Anonymize.Seed = ResolveEnvVars(cfg.Database.Anonymize.Seed, projectRoot)
+			redact.AddSecret(cfg.Database.Anonymize.Seed)
+			if err := cfg.Database.Anonymize.validate(); err != nil {
+				return nil, &types.CommandError{
+					Code:    types.ErrConfigInvalid,
+					Message: err.Error(),
+				}
+			}
+		}
 		if cfg.Database.DumpsPath == "" {
 			cfg.Database.DumpsPath = "var/dumps"
 		}
@@ -211,6 +294,14 @@ func validateConfig(cfg *Config, projectRoot string) (*Config, error) {
 	return cfg, nil
 }
 
+// registerSecrets keeps the password of the resolved DSN out of every error
+// message and log line
+func registerSecrets(databaseDSN string) {
+	if parsedDSN, err := dsn.ParseDSN(databaseDSN); err == nil {
+		redact.AddSecret(parsedDSN.Password)
+	}
+}
+
 // loadTOML loads configuration from a TOML file
 func loadTOML(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -19,12 +19,12 @@ func ResolveEnvVars(value, projectRoot string) string {
 		}
 
 		// Then check .env.local
-		if val := getEnvFromFile(filepath.Join(projectRoot, ".env.local"), varName); val != "" {
+		if val := EnvFileValue(filepath.Join(projectRoot, ".env.local"), varName); val != "" {
 			return val
 		}
 
 		// Then check .env
-		if val := getEnvFromFile(filepath.Join(projectRoot, ".env"), varName); val != "" {
+		if val := EnvFileValue(filepath.Join(projectRoot, ".env"), varName); val != "" {
 			return val
 		}
 
@@ -32,7 +32,9 @@ func ResolveEnvVars(value, projectRoot string) string {
 	})
 }
 
-func getEnvFromFile(filePath, varName string) string {
+// EnvFileValue returns the value of varName in a dotenv file, "" when the file
+// or the variable is missing
+func EnvFileValue(filePath, varName string) string {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return ""
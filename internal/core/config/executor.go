@@ -0,0 +1,23 @@
+package config
+
+// ExecutorMode selects where the database client tools run
+type ExecutorMode string
+
+const (
+	// ExecutorAuto execs into the compose service when the compose files define
+	// it and runs the clients on the host otherwise. It is the default.
+	ExecutorAuto ExecutorMode = "auto"
+	// ExecutorDocker always execs into the compose service
+	ExecutorDocker ExecutorMode = "docker"
+	// ExecutorHost runs the clients on the host against the DSN host and port
+	ExecutorHost ExecutorMode = "host"
+)
+
+// IsValid reports whether m is a known mode; empty means auto
+func (m ExecutorMode) IsValid() bool {
+	switch m {
+	case "", ExecutorAuto, ExecutorDocker, ExecutorHost:
+		return true
+	}
+	return false
+}
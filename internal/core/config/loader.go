@@ -93,6 +93,7 @@ func LoadHaive(projectRoot string) (*HaiveConfig, error) {
 	// Resolve environment variables in DSN
 	if cfg.Database != nil {
 		cfg.Database.DSN = ResolveEnvVars(cfg.Database.DSN, cfg.ProjectRoot)
+		registerSecrets(cfg.Database.DSN)
 	}
 
 	// Set default dumps path
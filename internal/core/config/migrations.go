@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	// DefaultMigrationsPath is where Symfony Flex puts Doctrine migrations
+	DefaultMigrationsPath = "migrations"
+	// DefaultMigrationsTable is the table Doctrine Migrations 3 records executed versions in
+	DefaultMigrationsTable = "doctrine_migration_versions"
+)
+
+var migrationsTableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Migrations locates the Doctrine migrations of the project, matching the
+// migrations_paths and storage.table_storage.table_name of
+// doctrine_migrations.yaml
+type Migrations struct {
+	// Path is the directory of the migration classes, relative to the project
+	// root or the root of a worktree
+	Path string `json:"path,omitempty" toml:"path,omitempty"`
+	// Table holds the executed versions, optionally qualified by a schema
+	Table string `json:"table,omitempty" toml:"table,omitempty"`
+}
+
+// MigrationsPath returns Path, DefaultMigrationsPath when unset or when m is nil
+func (m *Migrations) MigrationsPath() string {
+	if m == nil || m.Path == "" {
+		return DefaultMigrationsPath
+	}
+	return m.Path
+}
+
+// MigrationsTable returns Table, DefaultMigrationsTable when unset or when m is nil
+func (m *Migrations) MigrationsTable() string {
+	if m == nil || m.Table == "" {
+		return DefaultMigrationsTable
+	}
+	return m.Table
+}
+
+// validate rejects table names that would need quoting, as the table is
+// queried by name
+func (m *Migrations) validate() error {
+	if m.Table != "" && !migrationsTableRegex.MatchString(m.Table) {
+		return fmt.Errorf("database.migrations.table must be a plain table name, optionally prefixed by a schema (got %q)", m.Table)
+	}
+	return nil
+}
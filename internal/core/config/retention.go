@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retention limits how many dumps are kept in the dumps directory. Each limit
+// is optional; a dump is pruned as soon as it violates any of them.
+type Retention struct {
+	// KeepLast keeps at most this many dumps per database
+	KeepLast int `json:"keep_last,omitempty" toml:"keep_last,omitempty"`
+	// MaxAge prunes dumps older than this, e.g. "30d", "2w" or "12h"
+	MaxAge string `json:"max_age,omitempty" toml:"max_age,omitempty"`
+	// MaxTotalSize prunes the oldest dumps once all dumps together exceed this, e.g. "5GB"
+	MaxTotalSize string `json:"max_total_size,omitempty" toml:"max_total_size,omitempty"`
+	// AutoPrune applies the policy after every dump
+	AutoPrune bool `json:"auto_prune,omitempty" toml:"auto_prune,omitempty"`
+}
+
+// IsEmpty reports whether no limit is configured
+func (r *Retention) IsEmpty() bool {
+	return r == nil || (r.KeepLast == 0 && r.MaxAge == "" && r.MaxTotalSize == "")
+}
+
+// MaxAgeDuration returns MaxAge as a duration, 0 when unset
+func (r *Retention) MaxAgeDuration() (time.Duration, error) {
+	if r.MaxAge == "" {
+		return 0, nil
+	}
+	return ParseAge(r.MaxAge)
+}
+
+// MaxTotalSizeBytes returns MaxTotalSize in bytes, 0 when unset
+func (r *Retention) MaxTotalSizeBytes() (int64, error) {
+	if r.MaxTotalSize == "" {
+		return 0, nil
+	}
+	return ParseSize(r.MaxTotalSize)
+}
+
+func (r *Retention) validate() error {
+	if r.KeepLast < 0 {
+		return fmt.Errorf("database.retention.keep_last must not be negative (got %d)", r.KeepLast)
+	}
+	if _, err := r.MaxAgeDuration(); err != nil {
+		return fmt.Errorf("database.retention.max_age: %w", err)
+	}
+	if _, err := r.MaxTotalSizeBytes(); err != nil {
+		return fmt.Errorf("database.retention.max_total_size: %w", err)
+	}
+	return nil
+}
+
+// ParseAge parses a Go duration extended with "d" (days) and "w" (weeks) units
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	if unit != 0 {
+		n, err := strconv.ParseFloat(s[:len(s)-1], 64)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n * float64(unit)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// ParseSize parses a byte size such as "500MB", "1.5G" or "1024" using 1024-based units
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	number := strings.TrimRight(s, "KMGTB")
+	unit := strings.TrimSuffix(s[len(number):], "B")
+
+	multipliers := map[string]float64{"": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40}
+	multiplier, ok := multipliers[unit]
+	n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if !ok || err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 500MB or 5GB)", s)
+	}
+
+	return int64(n * multiplier), nil
+}
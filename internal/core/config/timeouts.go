@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Operation names a database operation that can be bounded by a timeout
+type Operation string
+
+const (
+	OpDump   Operation = "dump"
+	OpImport Operation = "import"
+	OpClone  Operation = "clone"
+	OpQuery  Operation = "query"
+)
+
+// Timeouts bounds how long database operations may run. Each value takes the
+// same units as retention.max_age, e.g. "90s", "30m" or "2h"; an unset value
+// means no limit.
+type Timeouts struct {
+	// Dump covers dumps and snapshots
+	Dump string `json:"dump,omitempty" toml:"dump,omitempty"`
+	// Import covers imports and snapshot restores
+	Import string `json:"import,omitempty" toml:"import,omitempty"`
+	// Clone covers a whole clone, including the fallback through a dump file
+	Clone string `json:"clone,omitempty" toml:"clone,omitempty"`
+	// Query covers short statements such as create, drop and listing databases
+	Query string `json:"query,omitempty" toml:"query,omitempty"`
+}
+
+// Duration returns the timeout of op, 0 when unset or when t is nil
+func (t *Timeouts) Duration(op Operation) time.Duration {
+	if t == nil {
+		return 0
+	}
+	d, _ := ParseAge(t.value(op))
+	return d
+}
+
+func (t *Timeouts) value(op Operation) string {
+	switch op {
+	case OpDump:
+		return t.Dump
+	case OpImport:
+		return t.Import
+	case OpClone:
+		return t.Clone
+	case OpQuery:
+		return t.Query
+	}
+	return ""
+}
+
+func (t *Timeouts) validate() error {
+	for _, op := range []Operation{OpDump, OpImport, OpClone, OpQuery} {
+		if value := t.value(op); value != "" {
+			if _, err := ParseAge(value); err != nil {
+				return fmt.Errorf("database.timeouts.%s: %w", op, err)
+			}
+		}
+	}
+	return nil
+}
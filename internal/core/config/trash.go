@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTrashMaxAge is how long a backup taken before a destructive operation is kept
+const DefaultTrashMaxAge = "7d"
+
+// Trash limits the backups database.backup_before_destructive leaves in
+// <dumps_path>/.trash. Expired backups are removed whenever a new one is taken.
+type Trash struct {
+	// MaxAge removes backups older than this, e.g. "7d", "2w" or "12h"
+	MaxAge string `json:"max_age,omitempty" toml:"max_age,omitempty"`
+	// KeepLast keeps at most this many backups per database, 0 for no limit
+	KeepLast int `json:"keep_last,omitempty" toml:"keep_last,omitempty"`
+}
+
+// MaxAgeDuration returns MaxAge as a duration, DefaultTrashMaxAge when unset or when t is nil
+func (t *Trash) MaxAgeDuration() (time.Duration, error) {
+	if t == nil || t.MaxAge == "" {
+		return ParseAge(DefaultTrashMaxAge)
+	}
+	return ParseAge(t.MaxAge)
+}
+
+// KeepLastCount returns KeepLast, 0 when t is nil
+func (t *Trash) KeepLastCount() int {
+	if t == nil {
+		return 0
+	}
+	return t.KeepLast
+}
+
+func (t *Trash) validate() error {
+	if t.KeepLast < 0 {
+		return fmt.Errorf("database.trash.keep_last must not be negative (got %d)", t.KeepLast)
+	}
+	if _, err := t.MaxAgeDuration(); err != nil {
+		return fmt.Errorf("database.trash.max_age: %w", err)
+	}
+	return nil
+}
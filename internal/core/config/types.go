@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/dsn"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
 // Module is the interface all modules implement
@@ -50,17 +53,25 @@ type EnvConfig struct {
 
 // DatabaseConfig holds database module configuration
 type DatabaseConfig struct {
-	Service   string         `toml:"service" yaml:"service" json:"service"`
-	DSN       string         `toml:"dsn" yaml:"dsn" json:"dsn"`
-	Allowed   []string       `toml:"allowed" yaml:"allowed" json:"allowed"`
-	DumpsPath string         `toml:"dumps_path,omitempty" yaml:"dumps_path,omitempty" json:"dumps_path,omitempty"`
-	Hooks     *DatabaseHooks `toml:"hooks,omitempty" yaml:"hooks,omitempty" json:"hooks,omitempty"`
+	Service       string         `toml:"service" yaml:"service" json:"service"`
+	Executor      ExecutorMode   `toml:"executor,omitempty" yaml:"executor,omitempty" json:"executor,omitempty"`
+	DSN           string         `toml:"dsn" yaml:"dsn" json:"dsn"`
+	Allowed       []string       `toml:"allowed" yaml:"allowed" json:"allowed"`
+	DumpsPath     string         `toml:"dumps_path,omitempty" yaml:"dumps_path,omitempty" json:"dumps_path,omitempty"`
+	CloneStrategy CloneMode      `toml:"clone_strategy,omitempty" yaml:"clone_strategy,omitempty" json:"clone_strategy,omitempty"`
+	Hooks         *DatabaseHooks `toml:"hooks,omitempty" yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
 func (d *DatabaseConfig) Name() string { return "database" }
 
 func (d *DatabaseConfig) Validate() error {
-	if d.Service == "" {
+	if !d.Executor.IsValid() {
+		return fmt.Errorf("database.executor must be one of auto, docker, host")
+	}
+	if !d.CloneStrategy.IsValid() {
+		return fmt.Errorf("database.clone_strategy must be one of auto, dump, template, copy")
+	}
+	if d.Service == "" && d.Executor != ExecutorHost && !dsn.IsSQLite(d.DSN) {
 		return fmt.Errorf("database.service is required")
 	}
 	if d.DSN == "" {
@@ -80,8 +91,9 @@ type DatabaseHooks struct {
 
 // DockerConfig holds Docker settings
 type DockerConfig struct {
-	ComposeFiles []string `toml:"compose_files,omitempty" yaml:"compose_files,omitempty" json:"compose_files,omitempty"`
-	ProjectName  string   `toml:"project_name,omitempty" yaml:"project_name,omitempty" json:"project_name,omitempty"`
+	ComposeFiles []string             `toml:"compose_files,omitempty" yaml:"compose_files,omitempty" json:"compose_files,omitempty"`
+	ProjectName  string               `toml:"project_name,omitempty" yaml:"project_name,omitempty" json:"project_name,omitempty"`
+	Runtime      types.ComposeRuntime `toml:"runtime,omitempty" yaml:"runtime,omitempty" json:"runtime,omitempty"`
 }
 
 // HaiveConfig is the top-level configuration structure
@@ -94,6 +106,10 @@ type HaiveConfig struct {
 
 // Validate validates the entire configuration
 func (c *HaiveConfig) Validate() error {
+	if !c.Docker.Runtime.IsValid() {
+		return fmt.Errorf("docker.runtime must be one of auto, docker, docker-compose, podman, nerdctl")
+	}
+
 	if c.Worktree != nil {
 		if err := c.Worktree.Validate(); err != nil {
 			return err
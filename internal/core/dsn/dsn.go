@@ -3,6 +3,7 @@ package dsn
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
@@ -13,6 +14,10 @@ func ParseDSN(dsnString string) (*types.DSN, error) {
 		return nil, fmt.Errorf("empty DSN")
 	}
 
+	if IsSQLite(dsnString) {
+		return parseSQLiteDSN(dsnString)
+	}
+
 	u, err := url.Parse(dsnString)
 	if err != nil {
 		return nil, fmt.Errorf("invalid DSN format: %w", err)
@@ -54,6 +59,34 @@ func ParseDSN(dsnString string) (*types.DSN, error) {
 	return dsn, nil
 }
 
+// IsSQLite reports whether dsnString points at a SQLite database file
+func IsSQLite(dsnString string) bool {
+	return strings.HasPrefix(dsnString, "sqlite://") || strings.HasPrefix(dsnString, "sqlite3://")
+}
+
+// parseSQLiteDSN handles Doctrine's sqlite:///<path> form, which url.Parse
+// rejects as soon as the path contains %kernel.project_dir%. As in Doctrine,
+// sqlite:///var/data.db is relative and sqlite:////var/data.db absolute.
+func parseSQLiteDSN(dsnString string) (*types.DSN, error) {
+	_, rest, _ := strings.Cut(dsnString, "://")
+	rest, _, _ = strings.Cut(rest, "?")
+	if !strings.HasPrefix(rest, "/") {
+		return nil, fmt.Errorf("invalid SQLite DSN: expected sqlite:///<path>")
+	}
+
+	filePath := strings.TrimPrefix(rest, "/")
+	if filePath == "" || filePath == ":memory:" {
+		return nil, fmt.Errorf("SQLite DSN must point at a database file")
+	}
+
+	base := path.Base(filePath)
+	return &types.DSN{
+		Engine:   "sqlite",
+		Database: strings.TrimSuffix(base, path.Ext(base)),
+		Path:     filePath,
+	}, nil
+}
+
 func determineEngine(scheme, serverVersion string) string {
 	if strings.Contains(serverVersion, "mariadb") {
 		return "mariadb"
@@ -134,6 +134,37 @@ func TestParseDSN(t *testing.T) {
 	}
 }
 
+func TestParseSQLiteDSN(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedPath string
+		expectedDB   string
+	}{
+		{"sqlite:///%kernel.project_dir%/var/data.db", "%kernel.project_dir%/var/data.db", "data"},
+		{"sqlite:////srv/app/var/app.sqlite", "/srv/app/var/app.sqlite", "app"},
+		{"sqlite3:///var/data.db?mode=rwc", "var/data.db", "data"},
+		{"sqlite:///var/data", "var/data", "data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseDSN(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Engine != "sqlite" || result.Path != tt.expectedPath || result.Database != tt.expectedDB {
+				t.Errorf("unexpected DSN %+v", result)
+			}
+		})
+	}
+
+	for _, input := range []string{"sqlite:///:memory:", "sqlite:///", "sqlite://var/data.db"} {
+		if _, err := ParseDSN(input); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
 func TestDetermineEngine(t *testing.T) {
 	tests := []struct {
 		scheme        string
@@ -22,6 +22,20 @@ func ValidateBranchName(name string) error {
 	return nil
 }
 
+// ValidateSnapshotName accepts names usable as a single file name component
+func ValidateSnapshotName(name string) error {
+	if name == "" {
+		return &types.CommandError{Code: types.ErrInvalidName, Message: "snapshot name cannot be empty"}
+	}
+
+	matched, _ := regexp.MatchString(`^[a-zA-Z0-9][a-zA-Z0-9_.\-]*$`, name)
+	if !matched {
+		return &types.CommandError{Code: types.ErrInvalidName, Message: "snapshot name may only contain letters, digits, '.', '_' and '-'"}
+	}
+
+	return nil
+}
+
 func CheckPathTraversal(resolvedPath, basePath string) error {
 	absResolved, err := filepath.Abs(resolvedPath)
 	if err != nil {
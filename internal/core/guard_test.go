@@ -67,6 +67,34 @@ func TestValidateBranchName(t *testing.T) {
 	}
 }
 
+func TestValidateSnapshotName(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshot  string
+		wantError bool
+	}{
+		{"empty name", "", true},
+		{"simple name", "before-migration", false},
+		{"name with dots and underscores", "v1.2_seed", false},
+		{"path separator", "../etc", true},
+		{"leading dot", ".hidden", true},
+		{"space", "my snapshot", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSnapshotName(tt.snapshot)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateSnapshotName(%q) error = %v, wantError %v", tt.snapshot, err, tt.wantError)
+				return
+			}
+			if tt.wantError && err.(*types.CommandError).Code != types.ErrInvalidName {
+				t.Errorf("ValidateSnapshotName() error code = %v, want %v", err.(*types.CommandError).Code, types.ErrInvalidName)
+			}
+		})
+	}
+}
+
 func TestCheckPathTraversal(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1,13 +1,20 @@
 package hooks
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 )
 
+// outputWaitDelay bounds how long a finished hook's output is still copied
+const outputWaitDelay = 5 * time.Second
+
 // HookContext provides environment variables and working directory for hooks
 type HookContext struct {
 	// Common
@@ -75,16 +82,20 @@ func NewExecutor(projectRoot string) *Executor {
 // ExecuteHooks runs a list of hooks and returns error if any fail
 // For pre-hooks, non-zero exit stops execution and returns error
 // For post-hooks, non-zero exit is logged but doesn't stop
-func (e *Executor) ExecuteHooks(hooks []string, ctx *HookContext, workingDir string, isPre bool) error {
+// Cancelling ctx kills the running hook and skips the remaining ones
+func (e *Executor) ExecuteHooks(ctx context.Context, hooks []string, hookCtx *HookContext, workingDir string, isPre bool) error {
 	for _, hook := range hooks {
-		if err := e.executeHook(hook, ctx, workingDir, isPre); err != nil {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("hooks interrupted: %w", err)
+		}
+		if err := e.executeHook(ctx, hook, hookCtx, workingDir, isPre); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (e *Executor) executeHook(hook string, ctx *HookContext, workingDir string, isPre bool) error {
+func (e *Executor) executeHook(ctx context.Context, hook string, hookCtx *HookContext, workingDir string, isPre bool) error {
 	// Check if hook is a script file or command
 	var cmd *exec.Cmd
 
@@ -99,26 +110,39 @@ func (e *Executor) executeHook(hook string, ctx *HookContext, workingDir string,
 			if isPre {
 				return fmt.Errorf("pre-hook script not found: %s", scriptPath)
 			}
-			fmt.Fprintf(os.Stderr, "Warning: post-hook script not found: %s\n", scriptPath)
+			redact.Fprintf(os.Stderr, "Warning: post-hook script not found: %s\n", scriptPath)
 			return nil
 		}
 
-		cmd = exec.Command(scriptPath)
+		cmd = exec.CommandContext(ctx, scriptPath)
 	} else {
 		// Run as shell command
-		cmd = exec.Command("sh", "-c", hook)
+		cmd = exec.CommandContext(ctx, "sh", "-c", hook)
 	}
 
+	// Hooks see DATABASE_URL, so a hook that prints its environment must not
+	// reveal the password
+	stdout := redact.NewWriter(os.Stdout)
+	stderr := redact.NewWriter(os.Stderr)
+	defer stdout.Close()
+	defer stderr.Close()
+
 	cmd.Dir = workingDir
-	cmd.Env = ctx.ToEnv()
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Env = hookCtx.ToEnv()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Output is copied through a pipe now; don't wait on background children
+	// of a finished hook that keep it open
+	cmd.WaitDelay = outputWaitDelay
 
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("hook %q interrupted: %w", hook, ctx.Err())
+		}
 		if isPre {
 			return fmt.Errorf("pre-hook failed: %w", err)
 		}
-		fmt.Fprintf(os.Stderr, "Warning: post-hook failed: %v\n", err)
+		redact.Fprintf(os.Stderr, "Warning: post-hook failed: %v\n", err)
 	}
 
 	return nil
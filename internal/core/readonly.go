@@ -0,0 +1,233 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// readOnlyStatements are the statements a read-only query may start with
+var readOnlyStatements = map[string]bool{
+	"SELECT": true, "WITH": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true,
+}
+
+// writeKeywords may not appear in a read-only query outside strings, quoted
+// identifiers and comments, unless they call a function such as REPLACE()
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "MERGE": true, "UPSERT": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+	"GRANT": true, "REVOKE": true, "INTO": true, "OUTFILE": true, "DUMPFILE": true,
+	"CALL": true, "DO": true, "SET": true, "LOCK": true, "UNLOCK": true, "HANDLER": true,
+	"LOAD": true, "COPY": true, "IMPORT": true, "VACUUM": true, "REINDEX": true, "CLUSTER": true,
+	"REFRESH": true, "ATTACH": true, "DETACH": true, "PRAGMA": true, "NOTIFY": true, "LISTEN": true,
+	"PREPARE": true, "EXECUTE": true, "DEALLOCATE": true, "BEGIN": true, "START": true,
+	"COMMIT": true, "ROLLBACK": true, "SAVEPOINT": true, "RELEASE": true, "KILL": true,
+	"SHUTDOWN": true, "FLUSH": true, "RESET": true, "PURGE": true, "INSTALL": true,
+	"UNINSTALL": true, "CHECKPOINT": true, "DISCARD": true,
+}
+
+// sideEffectFunctions act on the server or its files, which a read-only
+// transaction doesn't prevent. dblink runs its query on a connection of its
+// own, outside the read-only transaction. WRITEFILE, READFILE, EDIT and FSDIR
+// are functions of the sqlite3 shell that reach the host's files.
+var sideEffectFunctions = map[string]bool{
+	"LOAD_FILE": true, "LOAD_EXTENSION": true, "GET_LOCK": true, "SET_CONFIG": true,
+	"PG_TERMINATE_BACKEND": true, "PG_CANCEL_BACKEND": true, "PG_RELOAD_CONF": true,
+	"PG_READ_FILE": true, "PG_READ_BINARY_FILE": true, "PG_LS_DIR": true,
+	"PG_FILE_WRITE": true, "PG_FILE_UNLINK": true, "PG_FILE_RENAME": true,
+	"PG_ADVISORY_LOCK": true, "PG_ADVISORY_XACT_LOCK": true,
+	"LO_IMPORT": true, "LO_EXPORT": true,
+	"DBLINK": true, "DBLINK_EXEC": true, "DBLINK_SEND_QUERY": true, "DBLINK_OPEN": true,
+	"WRITEFILE": true, "READFILE": true, "EDIT": true, "FSDIR": true,
+}
+
+// CheckReadOnlyQuery accepts a single SELECT, WITH, SHOW, EXPLAIN or DESCRIBE
+// statement that uses no writing keyword, and returns it without the comments
+// before it and the semicolon after it. The check is lexical: the read-only
+// transaction the statement runs in is what guarantees nothing is written.
+func CheckReadOnlyQuery(query string, d sqldump.Dialect) (string, error) {
+	reject := func(format string, args ...any) error {
+		return &types.CommandError{Code: types.ErrQueryRejected, Message: fmt.Sprintf(format, args...)}
+	}
+
+	start, end := -1, 0
+	first := ""
+	terminated := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case isLineComment(query[i:], d):
+			if n := strings.IndexByte(query[i:], '\n'); n >= 0 {
+				i += n + 1
+			} else {
+				i = len(query)
+			}
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			// MySQL runs the content of /*! ... */ as SQL
+			if d == sqldump.DialectMySQL && (strings.HasPrefix(query[i:], "/*!") || strings.HasPrefix(query[i:], "/*M!")) {
+				return "", reject("executable comments are not allowed")
+			}
+			n, ok := skipBlockComment(query[i:], d == sqldump.DialectPostgres)
+			if !ok {
+				return "", reject("unterminated comment")
+			}
+			i += n
+			continue
+		case c == ';':
+			terminated = true
+			i++
+			continue
+		}
+
+		if terminated {
+			return "", reject("only a single statement is allowed")
+		}
+		if start < 0 {
+			start = i
+		}
+		if first == "" && (!isWordByte(c) || c == '$') {
+			return "", reject("only SELECT, SHOW, EXPLAIN and DESCRIBE statements are allowed")
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			n, ok := skipQuoted(query[i:], d == sqldump.DialectMySQL && c != '`')
+			if !ok {
+				return "", reject("unterminated quote")
+			}
+			i += n
+		case c == '$' && d == sqldump.DialectPostgres:
+			tag := dollarTag(query[i:])
+			if tag == "" {
+				i++
+				break
+			}
+			n := strings.Index(query[i+len(tag):], tag)
+			if n < 0 {
+				return "", reject("unterminated dollar quote")
+			}
+			i += len(tag) + n + len(tag)
+		case isWordByte(c):
+			j := i
+			for j < len(query) && isWordByte(query[j]) {
+				j++
+			}
+			word := strings.ToUpper(query[i:j])
+			if first == "" {
+				first = word
+				if !readOnlyStatements[word] {
+					return "", reject("only SELECT, SHOW, EXPLAIN and DESCRIBE statements are allowed, not %s", word)
+				}
+			}
+			// E'...' strings of PostgreSQL take backslash escapes
+			if word == "E" && j < len(query) && query[j] == '\'' {
+				n, ok := skipQuoted(query[j:], true)
+				if !ok {
+					return "", reject("unterminated quote")
+				}
+				i = j + n
+				break
+			}
+			i = j
+
+			call := strings.HasPrefix(strings.TrimLeft(query[j:], " \t\r\n"), "(")
+			switch {
+			case call && sideEffectFunctions[word]:
+				return "", reject("%s() is not allowed in a read-only query", strings.ToLower(word))
+			// Every SHOW statement reads, e.g. SHOW CREATE TABLE
+			case !call && first != "SHOW" && writeKeywords[word]:
+				return "", reject("%s is not allowed in a read-only query", word)
+			}
+		case c == '\\':
+			// The mysql client runs backslash commands such as \! anywhere
+			return "", reject("backslashes outside strings are not allowed")
+		default:
+			i++
+		}
+		end = i
+	}
+
+	if start < 0 {
+		return "", reject("the query is empty")
+	}
+	return query[start:end], nil
+}
+
+// isLineComment reports whether s starts with a comment running to the end of
+// the line. In MySQL "--" must be followed by a space: 1--1 is arithmetic.
+func isLineComment(s string, d sqldump.Dialect) bool {
+	if d != sqldump.DialectMySQL {
+		return strings.HasPrefix(s, "--")
+	}
+	if s[0] == '#' {
+		return true
+	}
+	return strings.HasPrefix(s, "--") && (len(s) == 2 || s[2] == ' ' || s[2] == '\t' || s[2] == '\n' || s[2] == '\r')
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// skipQuoted returns the length of the quoted string or identifier at the
+// start of s, whose doubled quotes, and with escapes backslashes, escape
+func skipQuoted(s string, escapes bool) (int, bool) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case escapes && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// skipBlockComment returns the length of the /* */ comment at the start of s.
+// PostgreSQL comments nest.
+func skipBlockComment(s string, nested bool) (int, bool) {
+	depth := 0
+	for i := 0; i+1 < len(s); i++ {
+		switch {
+		case s[i] == '/' && s[i+1] == '*' && (depth == 0 || nested):
+			depth++
+			i++
+		case s[i] == '*' && s[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// dollarTag returns the opening $tag$ of a PostgreSQL dollar quoted string at
+// the start of s, or "" for a parameter like $1
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+		case '0' <= c && c <= '9' && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
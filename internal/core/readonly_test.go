@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func TestCheckReadOnlyQuery(t *testing.T) {
+	mysql, postgres := sqldump.DialectMySQL, sqldump.DialectPostgres
+
+	tests := []struct {
+		name    string
+		query   string
+		dialect sqldump.Dialect
+		want    string
+		reject  bool
+	}{
+		{name: "select", query: "SELECT * FROM users", dialect: mysql, want: "SELECT * FROM users"},
+		{name: "comments and semicolon trimmed", query: "-- users\n/* all */ select id from users; -- done\n", dialect: postgres, want: "select id from users"},
+		{name: "with", query: "WITH recent AS (SELECT * FROM orders) SELECT count(*) FROM recent", dialect: postgres, want: "WITH recent AS (SELECT * FROM orders) SELECT count(*) FROM recent"},
+		{name: "show create table", query: "SHOW CREATE TABLE users", dialect: mysql, want: "SHOW CREATE TABLE users"},
+		{name: "explain", query: "EXPLAIN ANALYZE SELECT 1", dialect: postgres, want: "EXPLAIN ANALYZE SELECT 1"},
+		{name: "describe", query: "DESCRIBE users", dialect: mysql, want: "DESCRIBE users"},
+		{name: "keywords in strings", query: "SELECT 'drop table; delete' FROM t WHERE note = \"update\"", dialect: mysql, want: "SELECT 'drop table; delete' FROM t WHERE note = \"update\""},
+		{name: "keywords as quoted identifiers", query: "SELECT `delete`, `set` FROM t", dialect: mysql, want: "SELECT `delete`, `set` FROM t"},
+		{name: "escaped quote", query: `SELECT 'it\'s; drop' FROM t`, dialect: mysql, want: `SELECT 'it\'s; drop' FROM t`},
+		{name: "doubled quote", query: "SELECT 'it''s; drop' FROM t", dialect: postgres, want: "SELECT 'it''s; drop' FROM t"},
+		{name: "escape string", query: `SELECT E'a\'; drop' FROM t`, dialect: postgres, want: `SELECT E'a\'; drop' FROM t`},
+		{name: "dollar quote", query: "SELECT $fn$; delete from t$fn$, $1", dialect: postgres, want: "SELECT $fn$; delete from t$fn$, $1"},
+		{name: "nested comment", query: "SELECT /* a /* b */ delete */ 1", dialect: postgres, want: "SELECT /* a /* b */ delete */ 1"},
+		{name: "replace function", query: "SELECT REPLACE(email, '@', ' at ') FROM users", dialect: mysql, want: "SELECT REPLACE(email, '@', ' at ') FROM users"},
+		{name: "identifiers containing keywords", query: "SELECT updated_at, created_by FROM users ORDER BY id DESC", dialect: mysql, want: "SELECT updated_at, created_by FROM users ORDER BY id DESC"},
+		{name: "mysql double dash without space", query: "SELECT 1--1", dialect: mysql, want: "SELECT 1--1"},
+
+		{name: "empty", query: " ; -- nothing", dialect: mysql, reject: true},
+		{name: "insert", query: "INSERT INTO users VALUES (1)", dialect: mysql, reject: true},
+		{name: "second statement", query: "SELECT 1; DROP TABLE users", dialect: mysql, reject: true},
+		{name: "statement after comment", query: "SELECT 1; /* x */ SELECT 2", dialect: postgres, reject: true},
+		{name: "mysql double dash hides nothing", query: "SELECT 1--1; DROP TABLE users", dialect: mysql, reject: true},
+		{name: "select into", query: "SELECT * INTO backup FROM users", dialect: postgres, reject: true},
+		{name: "into outfile", query: "SELECT * FROM users INTO OUTFILE '/tmp/x'", dialect: mysql, reject: true},
+		{name: "for update", query: "SELECT * FROM users FOR UPDATE", dialect: postgres, reject: true},
+		{name: "data modifying cte", query: "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", dialect: postgres, reject: true},
+		{name: "explain of a write", query: "EXPLAIN ANALYZE DELETE FROM users", dialect: postgres, reject: true},
+		{name: "executable comment", query: "SELECT 1 /*!50000 , (SELECT 1) */", dialect: mysql, reject: true},
+		{name: "side effect function", query: "SELECT pg_terminate_backend (42)", dialect: postgres, reject: true},
+		{name: "load file", query: "SELECT LOAD_FILE('/etc/passwd')", dialect: mysql, reject: true},
+		{name: "dblink", query: "SELECT * FROM dblink('dbname=app', 'DELETE FROM users') AS t(x int)", dialect: postgres, reject: true},
+		{name: "dblink exec", query: "SELECT dblink_exec('dbname=app', 'DELETE FROM users')", dialect: postgres, reject: true},
+		{name: "dblink send query", query: "SELECT dblink_send_query('conn', 'DELETE FROM users')", dialect: postgres, reject: true},
+		{name: "dblink open", query: "SELECT dblink_open('cur', 'SELECT 1')", dialect: postgres, reject: true},
+		{name: "pg file write", query: "SELECT pg_file_write('x', 'y', false)", dialect: postgres, reject: true},
+		{name: "pg file unlink", query: "SELECT pg_file_unlink('postgresql.conf')", dialect: postgres, reject: true},
+		{name: "pg file rename", query: "SELECT pg_file_rename('a', 'b')", dialect: postgres, reject: true},
+		{name: "advisory lock", query: "SELECT pg_advisory_lock(1)", dialect: postgres, reject: true},
+		{name: "advisory xact lock", query: "SELECT pg_advisory_xact_lock(1)", dialect: postgres, reject: true},
+		{name: "sqlite writefile", query: "SELECT writefile('/tmp/pwned', 'x')", dialect: postgres, reject: true},
+		{name: "sqlite readfile", query: "SELECT readfile('/etc/passwd')", dialect: postgres, reject: true},
+		{name: "sqlite edit", query: "SELECT edit('x', 'vi')", dialect: postgres, reject: true},
+		{name: "sqlite fsdir", query: "SELECT name FROM fsdir('/etc')", dialect: postgres, reject: true},
+		{name: "client command", query: `SELECT 1 \! id`, dialect: mysql, reject: true},
+		{name: "leading parenthesis", query: "(SELECT 1)", dialect: postgres, reject: true},
+		{name: "unterminated string", query: "SELECT 'abc", dialect: postgres, reject: true},
+		{name: "unterminated comment", query: "SELECT 1 /* abc", dialect: mysql, reject: true},
+		{name: "mysql hash comment", query: "SELECT 1 # ; drop\n", dialect: mysql, want: "SELECT 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckReadOnlyQuery(tt.query, tt.dialect)
+			if tt.reject {
+				cmdErr, ok := err.(*types.CommandError)
+				if !ok || cmdErr.Code != types.ErrQueryRejected {
+					t.Errorf("CheckReadOnlyQuery(%q) = %q, %v, want ErrQueryRejected", tt.query, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckReadOnlyQuery(%q) error = %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckReadOnlyQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
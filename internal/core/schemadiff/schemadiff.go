@@ -0,0 +1,196 @@
+// Package schemadiff compares the schemas of two databases of one engine as
+// their catalogs describe them.
+package schemadiff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// Diff returns the changes that turn from into to, by table and then by
+// column, index and foreign key name. Column order is not compared. A new
+// table is a single change carrying all of its columns, indexes and foreign
+// keys.
+func Diff(from, to *types.DatabaseSchema) []types.SchemaChange {
+	oldTables := tablesByName(from)
+	newTables := tablesByName(to)
+
+	var names []string
+	for name := range oldTables {
+		names = append(names, name)
+	}
+	for name := range newTables {
+		if _, ok := oldTables[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []types.SchemaChange
+	for _, name := range names {
+		oldTable, newTable := oldTables[name], newTables[name]
+		switch {
+		case oldTable == nil:
+			changes = append(changes, types.SchemaChange{
+				Kind:     types.SchemaAddTable,
+				Table:    name,
+				To:       fmt.Sprintf("%d columns", len(newTable.Columns)),
+				NewTable: newTable,
+			})
+		case newTable == nil:
+			changes = append(changes, types.SchemaChange{
+				Kind:  types.SchemaDropTable,
+				Table: name,
+				From:  fmt.Sprintf("%d columns", len(oldTable.Columns)),
+			})
+		default:
+			changes = append(changes, diffColumns(oldTable, newTable)...)
+			changes = append(changes, diffIndexes(oldTable, newTable)...)
+			changes = append(changes, diffForeignKeys(oldTable, newTable)...)
+		}
+	}
+	return changes
+}
+
+func tablesByName(schema *types.DatabaseSchema) map[string]*types.TableSchema {
+	tables := make(map[string]*types.TableSchema)
+	if schema == nil {
+		return tables
+	}
+	for i := range schema.Tables {
+		tables[schema.Tables[i].Name] = &schema.Tables[i]
+	}
+	return tables
+}
+
+// diffNamed pairs the objects of two lists by name, the old ones in their
+// order followed by the new ones, and calls fn for each pair with nil for the
+// missing side
+func diffNamed[T any](oldItems, newItems []T, name func(*T) string, fn func(oldItem, newItem *T)) {
+	newByName := make(map[string]*T, len(newItems))
+	for i := range newItems {
+		newByName[name(&newItems[i])] = &newItems[i]
+	}
+	seen := make(map[string]bool, len(oldItems))
+	for i := range oldItems {
+		key := name(&oldItems[i])
+		seen[key] = true
+		fn(&oldItems[i], newByName[key])
+	}
+	for i := range newItems {
+		if !seen[name(&newItems[i])] {
+			fn(nil, &newItems[i])
+		}
+	}
+}
+
+func diffColumns(oldTable, newTable *types.TableSchema) []types.SchemaChange {
+	var changes []types.SchemaChange
+	diffNamed(oldTable.Columns, newTable.Columns, func(c *types.ColumnSchema) string { return c.Name },
+		func(oldColumn, newColumn *types.ColumnSchema) {
+			change := types.SchemaChange{Table: newTable.Name, OldColumn: oldColumn, NewColumn: newColumn}
+			switch {
+			case oldColumn == nil:
+				change.Kind, change.Name, change.To = types.SchemaAddColumn, newColumn.Name, ColumnDefinition(newColumn)
+			case newColumn == nil:
+				change.Kind, change.Name, change.From = types.SchemaDropColumn, oldColumn.Name, ColumnDefinition(oldColumn)
+			case ColumnDefinition(oldColumn) != ColumnDefinition(newColumn):
+				change.Kind, change.Name = types.SchemaModifyColumn, newColumn.Name
+				change.From, change.To = ColumnDefinition(oldColumn), ColumnDefinition(newColumn)
+			default:
+				return
+			}
+			changes = append(changes, change)
+		})
+	return changes
+}
+
+func diffIndexes(oldTable, newTable *types.TableSchema) []types.SchemaChange {
+	var changes []types.SchemaChange
+	diffNamed(oldTable.Indexes, newTable.Indexes, func(i *types.IndexSchema) string { return i.Name },
+		func(oldIndex, newIndex *types.IndexSchema) {
+			change := types.SchemaChange{Table: newTable.Name, OldIndex: oldIndex, NewIndex: newIndex}
+			switch {
+			case oldIndex == nil:
+				change.Kind, change.Name, change.To = types.SchemaAddIndex, newIndex.Name, IndexDefinition(newIndex)
+			case newIndex == nil:
+				change.Kind, change.Name, change.From = types.SchemaDropIndex, oldIndex.Name, IndexDefinition(oldIndex)
+			case IndexDefinition(oldIndex) != IndexDefinition(newIndex):
+				change.Kind, change.Name = types.SchemaModifyIndex, newIndex.Name
+				change.From, change.To = IndexDefinition(oldIndex), IndexDefinition(newIndex)
+			default:
+				return
+			}
+			changes = append(changes, change)
+		})
+	return changes
+}
+
+func diffForeignKeys(oldTable, newTable *types.TableSchema) []types.SchemaChange {
+	var changes []types.SchemaChange
+	diffNamed(oldTable.ForeignKeys, newTable.ForeignKeys, func(k *types.ForeignKeySchema) string { return k.Name },
+		func(oldKey, newKey *types.ForeignKeySchema) {
+			change := types.SchemaChange{Table: newTable.Name, NewForeignKey: newKey}
+			switch {
+			case oldKey == nil:
+				change.Kind, change.Name, change.To = types.SchemaAddForeignKey, newKey.Name, ForeignKeyDefinition(newKey)
+			case newKey == nil:
+				change.Kind, change.Name, change.From = types.SchemaDropForeignKey, oldKey.Name, ForeignKeyDefinition(oldKey)
+			case ForeignKeyDefinition(oldKey) != ForeignKeyDefinition(newKey):
+				change.Kind, change.Name = types.SchemaModifyForeignKey, newKey.Name
+				change.From, change.To = ForeignKeyDefinition(oldKey), ForeignKeyDefinition(newKey)
+			default:
+				return
+			}
+			changes = append(changes, change)
+		})
+	return changes
+}
+
+// ColumnDefinition describes a column the way a CREATE TABLE statement would,
+// without its name
+func ColumnDefinition(c *types.ColumnSchema) string {
+	parts := []string{c.Type}
+	if !c.Nullable {
+		parts = append(parts, "NOT NULL")
+	}
+	if c.Default != nil {
+		parts = append(parts, "DEFAULT "+*c.Default)
+	}
+	if c.Extra != "" {
+		parts = append(parts, c.Extra)
+	}
+	return strings.Join(parts, " ")
+}
+
+// IndexDefinition describes an index without its name, e.g. "UNIQUE (email)"
+func IndexDefinition(i *types.IndexSchema) string {
+	kind := "INDEX"
+	switch {
+	case i.Primary:
+		kind = "PRIMARY KEY"
+	case i.Unique:
+		kind = "UNIQUE"
+	}
+	definition := kind + " (" + strings.Join(i.Columns, ", ") + ")"
+	if i.Method != "" && !strings.EqualFold(i.Method, "btree") {
+		definition += " USING " + i.Method
+	}
+	return definition
+}
+
+// ForeignKeyDefinition describes a foreign key without its name
+func ForeignKeyDefinition(k *types.ForeignKeySchema) string {
+	definition := fmt.Sprintf("(%s) REFERENCES %s (%s)",
+		strings.Join(k.Columns, ", "), k.ReferencedTable, strings.Join(k.ReferencedColumns, ", "))
+	if k.OnDelete != "" {
+		definition += " ON DELETE " + k.OnDelete
+	}
+	if k.OnUpdate != "" {
+		definition += " ON UPDATE " + k.OnUpdate
+	}
+	return definition
+}
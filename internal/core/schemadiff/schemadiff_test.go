@@ -0,0 +1,154 @@
+package schemadiff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func strPtr(s string) *string { return &s }
+
+func usersTable() types.TableSchema {
+	return types.TableSchema{
+		Name: "users",
+		Columns: []types.ColumnSchema{
+			{Name: "id", Type: "int", Extra: "auto_increment"},
+			{Name: "email", Type: "varchar(180)"},
+			{Name: "status", Type: "varchar(20)", Nullable: true, Default: strPtr("'new'")},
+		},
+		Indexes: []types.IndexSchema{
+			{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id"}, Method: "BTREE"},
+			{Name: "uniq_email", Unique: true, Columns: []string{"email"}, Method: "BTREE"},
+		},
+	}
+}
+
+func postsTable() types.TableSchema {
+	return types.TableSchema{
+		Name:    "posts",
+		Columns: []types.ColumnSchema{{Name: "id", Type: "int"}, {Name: "user_id", Type: "int"}},
+		ForeignKeys: []types.ForeignKeySchema{
+			{Name: "fk_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE"},
+		},
+	}
+}
+
+type summary struct {
+	Kind  types.SchemaChangeKind
+	Table string
+	Name  string
+	From  string
+	To    string
+}
+
+func summarize(changes []types.SchemaChange) []summary {
+	var result []summary
+	for _, c := range changes {
+		result = append(result, summary{c.Kind, c.Table, c.Name, c.From, c.To})
+	}
+	return result
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     []types.TableSchema
+		to       func() []types.TableSchema
+		expected []summary
+	}{
+		{
+			name: "identical schemas",
+			from: []types.TableSchema{usersTable(), postsTable()},
+			to:   func() []types.TableSchema { return []types.TableSchema{postsTable(), usersTable()} },
+		},
+		{
+			name: "added and dropped tables",
+			from: []types.TableSchema{usersTable()},
+			to:   func() []types.TableSchema { return []types.TableSchema{postsTable()} },
+			expected: []summary{
+				{types.SchemaAddTable, "posts", "", "", "2 columns"},
+				{types.SchemaDropTable, "users", "", "3 columns", ""},
+			},
+		},
+		{
+			name: "column changes",
+			from: []types.TableSchema{usersTable()},
+			to: func() []types.TableSchema {
+				users := usersTable()
+				users.Columns[1].Type = "varchar(255)"
+				users.Columns[2].Default = nil
+				users.Columns = append(users.Columns[:2], users.Columns[2], types.ColumnSchema{Name: "nickname", Type: "varchar(64)", Nullable: true})
+				users.Columns[0], users.Columns[1] = users.Columns[1], users.Columns[0]
+				return []types.TableSchema{users}
+			},
+			expected: []summary{
+				{types.SchemaModifyColumn, "users", "email", "varchar(180) NOT NULL", "varchar(255) NOT NULL"},
+				{types.SchemaModifyColumn, "users", "status", "varchar(20) DEFAULT 'new'", "varchar(20)"},
+				{types.SchemaAddColumn, "users", "nickname", "", "varchar(64)"},
+			},
+		},
+		{
+			name: "index changes",
+			from: []types.TableSchema{usersTable()},
+			to: func() []types.TableSchema {
+				users := usersTable()
+				users.Indexes[1] = types.IndexSchema{Name: "uniq_email", Unique: true, Columns: []string{"email", "status"}, Method: "BTREE"}
+				users.Indexes = append(users.Indexes, types.IndexSchema{Name: "ft_email", Columns: []string{"email"}, Method: "FULLTEXT"})
+				users.Indexes = users.Indexes[1:]
+				return []types.TableSchema{users}
+			},
+			expected: []summary{
+				{types.SchemaDropIndex, "users", "PRIMARY", "PRIMARY KEY (id)", ""},
+				{types.SchemaModifyIndex, "users", "uniq_email", "UNIQUE (email)", "UNIQUE (email, status)"},
+				{types.SchemaAddIndex, "users", "ft_email", "", "INDEX (email) USING FULLTEXT"},
+			},
+		},
+		{
+			name: "foreign key changes",
+			from: []types.TableSchema{postsTable()},
+			to: func() []types.TableSchema {
+				posts := postsTable()
+				posts.ForeignKeys[0].OnDelete = "SET NULL"
+				return []types.TableSchema{posts}
+			},
+			expected: []summary{
+				{types.SchemaModifyForeignKey, "posts", "fk_user",
+					"(user_id) REFERENCES users (id) ON DELETE CASCADE",
+					"(user_id) REFERENCES users (id) ON DELETE SET NULL"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Diff(&types.DatabaseSchema{Tables: tt.from}, &types.DatabaseSchema{Tables: tt.to()})
+			if got := summarize(changes); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Diff() =\n%+v\nwant\n%+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiff_CarriesDefinitions(t *testing.T) {
+	users := usersTable()
+	posts := postsTable()
+	changedUsers := usersTable()
+	changedUsers.Columns[1].Nullable = true
+
+	changes := Diff(
+		&types.DatabaseSchema{Tables: []types.TableSchema{users}},
+		&types.DatabaseSchema{Tables: []types.TableSchema{changedUsers, posts}},
+	)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", summarize(changes))
+	}
+
+	if changes[0].Kind != types.SchemaAddTable || changes[0].NewTable == nil || changes[0].NewTable.Name != "posts" {
+		t.Errorf("expected the new table to be carried, got %+v", changes[0])
+	}
+	modify := changes[1]
+	if modify.OldColumn == nil || modify.OldColumn.Nullable || modify.NewColumn == nil || !modify.NewColumn.Nullable {
+		t.Errorf("expected both column definitions, got %+v", modify)
+	}
+}
@@ -0,0 +1,57 @@
+package sqldump
+
+import "strings"
+
+// CopyNull is how COPY text format writes NULL
+const CopyNull = `\N`
+
+// SplitCopyRow splits one data line of a COPY block (without its newline)
+// into its still escaped fields
+func SplitCopyRow(line string) []string {
+	return strings.Split(line, "\t")
+}
+
+// JoinCopyRow is the inverse of SplitCopyRow
+func JoinCopyRow(fields []string) string {
+	return strings.Join(fields, "\t")
+}
+
+// UnescapeCopy decodes a COPY text format field
+func UnescapeCopy(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c != '\\' || i+1 == len(field) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch field[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return b.String()
+}
+
+// EscapeCopy encodes s as a COPY text format field
+func EscapeCopy(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+	return r.Replace(s)
+}
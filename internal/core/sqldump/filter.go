@@ -0,0 +1,317 @@
+package sqldump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// statementKind tells what a statement of a dump belongs to
+type statementKind int
+
+const (
+	statementOther statementKind = iota
+	statementTable
+	statementCopy
+	statementSequence
+	statementSequenceOwner
+	statementSetval
+)
+
+// statement is the start of a statement as classified by classifyStatement
+type statement struct {
+	kind statementKind
+	// name is the table, or the sequence for sequence statements
+	name string
+	// owner is the table of ALTER SEQUENCE ... OWNED BY
+	owner string
+}
+
+var (
+	triggerPattern      = regexp.MustCompile(`(?i)^CREATE\b.*\bTRIGGER\s+\S+\s+(?:BEFORE|AFTER|INSTEAD\s+OF)\s.*?\sON\s+`)
+	indexPattern        = regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?INDEX\s.*?\sON\s+(?:ONLY\s+)?`)
+	sequenceNamePattern = regexp.MustCompile(`(?i)\bSEQUENCE\s+NAME\s+(\S+)`)
+	setvalPattern       = regexp.MustCompile(`(?i)^SELECT\s+(?:pg_catalog\.)?setval\('([^']+)'`)
+)
+
+// TableFilter passes the statements of the selected tables of a SQL dump
+// written to it through to its output: CREATE, DROP, ALTER and LOCK TABLE,
+// INSERT, COPY, indexes and triggers. PostgreSQL sequences go with the table
+// owning them. Statements not tied to a table, like the SET statements of
+// the dump header, are always kept.
+type TableFilter struct {
+	patterns []string
+	dialect  Dialect
+	out      io.Writer
+
+	buf       []byte
+	delimiter string
+	current   statement
+	keep      bool
+	stmt      strings.Builder
+
+	sequences map[string]string // CREATE SEQUENCE statements waiting for their owner
+	owned     map[string]bool   // whether the owner of a sequence is kept
+	matched   []string
+	seen      map[string]bool
+	err       error
+}
+
+// NewTableFilter returns a TableFilter writing the statements of the tables
+// matching patterns to w. Patterns are globs matched against the table name
+// with and without its schema. Close must be called to flush the final line.
+func NewTableFilter(w io.Writer, d Dialect, patterns []string) *TableFilter {
+	return &TableFilter{
+		patterns:  patterns,
+		dialect:   d,
+		out:       w,
+		delimiter: ";",
+		sequences: make(map[string]string),
+		owned:     make(map[string]bool),
+		seen:      make(map[string]bool),
+	}
+}
+
+func (f *TableFilter) Write(p []byte) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.buf = append(f.buf, p...)
+	for {
+		idx := bytes.IndexByte(f.buf, '\n')
+		if idx == -1 {
+			break
+		}
+		line := string(f.buf[:idx+1])
+		f.buf = f.buf[idx+1:]
+		if f.err = f.line(line); f.err != nil {
+			return 0, f.err
+		}
+	}
+	return len(p), nil
+}
+
+// Err returns the error that stopped the filter, if any
+func (f *TableFilter) Err() error {
+	return f.err
+}
+
+// Matched returns the tables whose statements were kept, in dump order
+func (f *TableFilter) Matched() []string {
+	return f.matched
+}
+
+func (f *TableFilter) Close() error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if len(f.buf) > 0 {
+		line := string(f.buf)
+		f.buf = nil
+		if f.err = f.line(line); f.err != nil {
+			return f.err
+		}
+	}
+
+	switch f.current.kind {
+	case statementOther:
+	case statementCopy:
+		f.err = fmt.Errorf("dump ended inside the COPY data of %s", f.current.name)
+	default:
+		f.err = fmt.Errorf("dump ended inside a statement of %s", f.current.name)
+	}
+	return f.err
+}
+
+func (f *TableFilter) line(line string) error {
+	switch f.current.kind {
+	case statementCopy:
+		if strings.TrimRight(line, "\r\n") == CopyTerminator {
+			f.current = statement{}
+		}
+		return f.writeIf(f.keep, line)
+
+	case statementOther:
+		if d, ok := cutPrefixFold(strings.TrimSpace(line), "DELIMITER "); ok {
+			f.delimiter = strings.TrimSpace(d)
+			return f.write(line)
+		}
+
+		f.current = classifyStatement(line)
+		switch f.current.kind {
+		case statementOther:
+			return f.write(line)
+		case statementCopy:
+			f.keep = f.match(f.current.name)
+			return f.writeIf(f.keep, line)
+		}
+		f.stmt.Reset()
+	}
+
+	f.stmt.WriteString(line)
+	if !f.complete(f.stmt.String()) {
+		return nil
+	}
+	current := f.current
+	f.current = statement{}
+	return f.statement(current, f.stmt.String())
+}
+
+// statement writes the complete statement stmt if it belongs to a selected
+// table
+func (f *TableFilter) statement(current statement, stmt string) error {
+	switch current.kind {
+	case statementSequence:
+		f.sequences[current.name] = stmt
+		return nil
+
+	case statementSequenceOwner:
+		keep := f.owned[current.name]
+		if current.owner != "" {
+			keep = f.match(current.owner)
+			f.owned[current.name] = keep
+		}
+		if create, ok := f.sequences[current.name]; ok && keep {
+			delete(f.sequences, current.name)
+			if err := f.write(create); err != nil {
+				return err
+			}
+		}
+		return f.writeIf(keep, stmt)
+
+	case statementSetval:
+		return f.writeIf(f.owned[current.name], stmt)
+	}
+
+	keep := f.match(current.name)
+	// Identity columns name their sequence inside ALTER TABLE
+	for _, m := range sequenceNamePattern.FindAllStringSubmatch(stmt, -1) {
+		f.owned[UnquoteIdent(m[1])] = keep
+	}
+	return f.writeIf(keep, stmt)
+}
+
+// complete reports whether stmt ends with the current delimiter
+func (f *TableFilter) complete(stmt string) bool {
+	if f.delimiter != ";" {
+		return strings.HasSuffix(strings.TrimSpace(stmt), f.delimiter)
+	}
+	return StatementComplete(stmt, f.dialect)
+}
+
+// match reports whether table is selected and records it the first time
+func (f *TableFilter) match(table string) bool {
+	for _, pattern := range f.patterns {
+		if ok, _ := path.Match(pattern, table); ok {
+			return f.record(table)
+		}
+		if ok, _ := path.Match(pattern, BaseName(table)); ok {
+			return f.record(table)
+		}
+	}
+	return false
+}
+
+func (f *TableFilter) record(table string) bool {
+	if !f.seen[table] {
+		f.seen[table] = true
+		f.matched = append(f.matched, table)
+	}
+	return true
+}
+
+func (f *TableFilter) writeIf(keep bool, s string) error {
+	if !keep {
+		return nil
+	}
+	return f.write(s)
+}
+
+func (f *TableFilter) write(s string) error {
+	_, err := io.WriteString(f.out, s)
+	return err
+}
+
+// classifyStatement returns what the statement starting with line belongs
+// to. Indented lines are taken to continue a statement, like function bodies.
+func classifyStatement(line string) statement {
+	if line == "" || line[0] == ' ' || line[0] == '\t' {
+		return statement{}
+	}
+	stmt := strings.TrimSpace(stripVersionComment(line))
+
+	if table, ok := ParseCreateTable(stmt); ok {
+		return statement{kind: statementTable, name: table}
+	}
+	if table, _, ok := ParseCopy(stmt); ok {
+		return statement{kind: statementCopy, name: table}
+	}
+	if rest, ok := cutPrefixFold(stmt, "INSERT INTO "); ok {
+		return identStatement(statementTable, rest)
+	}
+	if rest, ok := cutPrefixFold(stmt, "LOCK TABLES "); ok {
+		return identStatement(statementTable, rest)
+	}
+	if rest, ok := cutPrefixFold(stmt, "DROP TABLE "); ok {
+		return identStatement(statementTable, skipKeywords(rest, "IF EXISTS "))
+	}
+	if rest, ok := cutPrefixFold(stmt, "ALTER TABLE "); ok {
+		return identStatement(statementTable, skipKeywords(rest, "IF EXISTS ", "ONLY "))
+	}
+	if loc := indexPattern.FindStringIndex(stmt); loc != nil {
+		return identStatement(statementTable, stmt[loc[1]:])
+	}
+	if loc := triggerPattern.FindStringIndex(stmt); loc != nil {
+		return identStatement(statementTable, stmt[loc[1]:])
+	}
+
+	if rest, ok := cutPrefixFold(stmt, "CREATE SEQUENCE "); ok {
+		return identStatement(statementSequence, skipKeywords(rest, "IF NOT EXISTS "))
+	}
+	if rest, ok := cutPrefixFold(stmt, "ALTER SEQUENCE "); ok {
+		ident, rest := readIdent(skipKeywords(rest, "IF EXISTS "))
+		current := statement{kind: statementSequenceOwner, name: UnquoteIdent(ident)}
+		if owner, ok := cutPrefixFold(strings.TrimSpace(rest), "OWNED BY "); ok {
+			column, _ := readIdent(owner)
+			if parts := splitQualified(column); len(parts) > 1 {
+				current.owner = UnquoteIdent(strings.Join(parts[:len(parts)-1], "."))
+			}
+		}
+		return current
+	}
+	if m := setvalPattern.FindStringSubmatch(stmt); m != nil {
+		return statement{kind: statementSetval, name: UnquoteIdent(m[1])}
+	}
+
+	return statement{}
+}
+
+func identStatement(kind statementKind, rest string) statement {
+	ident, _ := readIdent(strings.TrimSpace(rest))
+	if ident == "" {
+		return statement{}
+	}
+	return statement{kind: kind, name: UnquoteIdent(ident)}
+}
+
+// skipKeywords drops the optional keywords from the start of s in order
+func skipKeywords(s string, keywords ...string) string {
+	for _, keyword := range keywords {
+		s, _ = cutPrefixFold(strings.TrimSpace(s), keyword)
+	}
+	return s
+}
+
+// stripVersionComment removes the opening of a MySQL version comment like
+// "/*!40000 ", which mysqldump wraps around statements
+func stripVersionComment(line string) string {
+	rest, ok := strings.CutPrefix(line, "/*!")
+	if !ok {
+		return line
+	}
+	return strings.TrimLeft(rest, "0123456789")
+}
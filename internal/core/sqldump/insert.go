@@ -0,0 +1,275 @@
+package sqldump
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Value is one literal of an INSERT row exactly as written in the dump,
+// e.g. 42, NULL, 'O\'Brien' or _binary 'x'
+type Value string
+
+// IsNull reports whether the value is the NULL literal
+func (v Value) IsNull() bool {
+	return strings.EqualFold(strings.TrimSpace(string(v)), "NULL")
+}
+
+// Text returns the content of a string literal, or the literal itself for
+// numbers and other unquoted values
+func (v Value) Text(d Dialect) string {
+	raw := strings.TrimSpace(string(v))
+	start := strings.IndexByte(raw, '\'')
+	if start == -1 || !strings.HasSuffix(raw, "'") || start == len(raw)-1 {
+		return raw
+	}
+
+	body := raw[start+1 : len(raw)-1]
+	backslashes := d == DialectMySQL || strings.EqualFold(raw[:start], "E")
+
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && backslashes && i+1 < len(body):
+			i++
+			b.WriteByte(unescapeByte(body[i]))
+		case c == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			i++
+			b.WriteByte('\'')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func unescapeByte(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case '0':
+		return 0
+	case 'Z':
+		return 26
+	default:
+		return c
+	}
+}
+
+// QuoteString returns s as a string literal for dialect d
+func QuoteString(s string, d Dialect) Value {
+	if d != DialectMySQL {
+		return Value("'" + strings.ReplaceAll(s, "'", "''") + "'")
+	}
+
+	var b strings.Builder
+	b.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case 0:
+			b.WriteString(`\0`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return Value(b.String())
+}
+
+// Insert is a parsed INSERT ... VALUES statement
+type Insert struct {
+	Table   string
+	Columns []string // nil when the statement has no column list
+	Rows    [][]Value
+
+	prefix string // everything up to and including VALUES
+	suffix string // everything after the last row, e.g. ";\n"
+}
+
+// ParseInsert parses a complete INSERT INTO ... VALUES statement
+func ParseInsert(stmt string, d Dialect) (*Insert, error) {
+	ins, afterValues, err := parseInsertHead(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := 0
+	s := afterValues
+	for {
+		for pos < len(s) && (s[pos] == ' ' || s[pos] == '\t' || s[pos] == '\n' || s[pos] == '\r') {
+			pos++
+		}
+		if pos >= len(s) || s[pos] != '(' {
+			break
+		}
+
+		row, next, err := scanRow(s, pos+1, d)
+		if err != nil {
+			return nil, fmt.Errorf("INSERT into %s: %w", ins.Table, err)
+		}
+		ins.Rows = append(ins.Rows, row)
+		pos = next
+
+		if pos < len(s) && s[pos] == ',' {
+			pos++
+			continue
+		}
+		break
+	}
+
+	if len(ins.Rows) == 0 {
+		return nil, fmt.Errorf("INSERT into %s has no rows", ins.Table)
+	}
+	ins.suffix = s[pos:]
+
+	return ins, nil
+}
+
+// parseInsertHead parses stmt up to and including its VALUES keyword and
+// returns the rest of it
+func parseInsertHead(stmt string) (*Insert, string, error) {
+	rest, found := cutPrefixFold(stmt, "INSERT INTO ")
+	if !found {
+		return nil, "", fmt.Errorf("not an INSERT statement")
+	}
+
+	ident, rest := readIdent(rest)
+	ins := &Insert{Table: UnquoteIdent(ident)}
+
+	rest = strings.TrimLeft(rest, " \t")
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end == -1 {
+			return nil, "", fmt.Errorf("unterminated column list in INSERT into %s", ins.Table)
+		}
+		ins.Columns = splitColumnList(rest[1:end])
+		rest = strings.TrimLeft(rest[end+1:], " \t")
+	}
+
+	afterValues, found := cutPrefixFold(rest, "VALUES")
+	if !found {
+		return nil, "", fmt.Errorf("INSERT into %s has no VALUES clause", ins.Table)
+	}
+	ins.prefix = stmt[:len(stmt)-len(afterValues)]
+
+	return ins, afterValues, nil
+}
+
+// CountInsertRows returns the table of a complete INSERT INTO ... VALUES
+// statement and its number of rows, without splitting the rows into values
+func CountInsertRows(stmt string, d Dialect) (table string, rows int, err error) {
+	ins, s, err := parseInsertHead(stmt)
+	if err != nil {
+		return "", 0, err
+	}
+
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			end, err := skipString(s, i, d)
+			if err != nil {
+				return ins.Table, rows, fmt.Errorf("INSERT into %s: %w", ins.Table, err)
+			}
+			i = end
+		case '(':
+			if depth == 0 {
+				rows++
+			}
+			depth++
+		case ')':
+			depth--
+		}
+	}
+
+	return ins.Table, rows, nil
+}
+
+// scanRow reads comma separated values from s[pos:] up to the closing
+// parenthesis of the row and returns the position after it
+func scanRow(s string, pos int, d Dialect) ([]Value, int, error) {
+	var row []Value
+	start := pos
+	depth := 0
+	for i := pos; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'':
+			end, err := skipString(s, i, d)
+			if err != nil {
+				return nil, 0, err
+			}
+			i = end
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			row = append(row, Value(strings.TrimSpace(s[start:i])))
+			return row, i + 1, nil
+		case ',':
+			if depth == 0 {
+				row = append(row, Value(strings.TrimSpace(s[start:i])))
+				start = i + 1
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("unterminated row")
+}
+
+// skipString returns the index of the quote closing the literal opened at s[open]
+func skipString(s string, open int, d Dialect) (int, error) {
+	backslashes := d == DialectMySQL || (open > 0 && (s[open-1] == 'E' || s[open-1] == 'e'))
+	for i := open + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslashes {
+				i++
+			}
+		case '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string literal")
+}
+
+// String renders the statement back to SQL, reflecting any changed values
+func (ins *Insert) String() string {
+	var b strings.Builder
+	b.WriteString(ins.prefix)
+	if !strings.HasSuffix(ins.prefix, " ") {
+		b.WriteByte(' ')
+	}
+	for i, row := range ins.Rows {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		for j, v := range row {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(string(v))
+		}
+		b.WriteByte(')')
+	}
+	b.WriteString(ins.suffix)
+	return b.String()
+}
@@ -0,0 +1,194 @@
+package sqldump
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Summary describes a dump as read by Inspect
+type Summary struct {
+	// Engine is MySQL, MariaDB or PostgreSQL when the header names it
+	Engine string
+	// Tool is the dump tool and its version from the header
+	Tool string
+	// ServerVersion is the version of the dumped server from the header
+	ServerVersion string
+	// Charset is the client character set the dump is written in
+	Charset string
+	// Size is the number of bytes of SQL read
+	Size   int64
+	Tables []TableSummary
+}
+
+// TableSummary describes what a dump holds for one table
+type TableSummary struct {
+	Name string
+	// Create is the CREATE TABLE statement, empty for data-only dumps
+	Create string
+	// Rows counts the rows of its INSERT statements and COPY blocks
+	Rows int64
+	// Bytes is the size of its CREATE TABLE statement and row data in the dump
+	Bytes int64
+}
+
+var (
+	setNamesPattern       = regexp.MustCompile(`(?i)SET NAMES '?(\w+)`)
+	clientEncodingPattern = regexp.MustCompile(`(?i)^SET client_encoding = '([^']+)'`)
+)
+
+// Inspect reads a plain SQL dump from r and summarises it per table. The
+// dialect is taken from the header of mysqldump or pg_dump when there is one,
+// d applies otherwise. Statements it doesn't recognise are skipped.
+func Inspect(r io.Reader, d Dialect) (*Summary, error) {
+	summary := &Summary{}
+	// Tables are indexed by position, appending moves the elements
+	tables := make(map[string]int)
+	table := func(name string) *TableSummary {
+		i, ok := tables[name]
+		if !ok {
+			i = len(summary.Tables)
+			summary.Tables = append(summary.Tables, TableSummary{Name: name})
+			tables[name] = i
+		}
+		return &summary.Tables[i]
+	}
+
+	const (
+		stateStatements = iota
+		stateCreateTable
+		stateInsert
+		stateCopy
+	)
+	state := stateStatements
+	var stmt strings.Builder
+	var current string
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line == "" && err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		summary.Size += int64(len(line))
+
+		switch state {
+		case stateCreateTable:
+			stmt.WriteString(line)
+			if StatementComplete(stmt.String(), d) {
+				t := table(current)
+				t.Create = strings.TrimSpace(stmt.String())
+				t.Bytes += int64(stmt.Len())
+				state = stateStatements
+			}
+			continue
+
+		case stateInsert:
+			stmt.WriteString(line)
+			if StatementComplete(stmt.String(), d) {
+				countInsert(table, stmt.String(), d)
+				state = stateStatements
+			}
+			continue
+
+		case stateCopy:
+			t := table(current)
+			t.Bytes += int64(len(line))
+			if strings.TrimRight(line, "\r\n") == CopyTerminator {
+				state = stateStatements
+			} else {
+				t.Rows++
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "--") {
+			if engine := inspectHeader(summary, line); engine != "" {
+				summary.Engine = engine
+				d = DialectMySQL
+				if engine == "PostgreSQL" {
+					d = DialectPostgres
+				}
+			}
+			continue
+		}
+
+		if summary.Charset == "" {
+			if m := setNamesPattern.FindStringSubmatch(line); m != nil {
+				summary.Charset = m[1]
+			} else if m := clientEncodingPattern.FindStringSubmatch(line); m != nil {
+				summary.Charset = m[1]
+			}
+		}
+
+		if name, ok := ParseCreateTable(line); ok {
+			current = name
+			stmt.Reset()
+			stmt.WriteString(line)
+			state = stateCreateTable
+			if StatementComplete(line, d) {
+				t := table(current)
+				t.Create = strings.TrimSpace(line)
+				t.Bytes += int64(len(line))
+				state = stateStatements
+			}
+		} else if IsInsert(line) {
+			stmt.Reset()
+			stmt.WriteString(line)
+			state = stateInsert
+			if StatementComplete(line, d) {
+				countInsert(table, line, d)
+				state = stateStatements
+			}
+		} else if name, _, ok := ParseCopy(line); ok {
+			current = name
+			table(current).Bytes += int64(len(line))
+			state = stateCopy
+		}
+	}
+
+	return summary, nil
+}
+
+// countInsert adds the rows and bytes of an INSERT statement to its table.
+// A statement whose rows can't be scanned still counts towards the bytes.
+func countInsert(table func(string) *TableSummary, stmt string, d Dialect) {
+	name, rows, err := CountInsertRows(stmt, d)
+	if name == "" && err != nil {
+		return
+	}
+	t := table(name)
+	t.Rows += int64(rows)
+	t.Bytes += int64(len(stmt))
+}
+
+// inspectHeader records what a comment line of the dump header tells and
+// returns the engine if the line names it
+func inspectHeader(summary *Summary, line string) string {
+	text := strings.TrimSpace(strings.TrimPrefix(line, "--"))
+
+	switch {
+	case strings.HasPrefix(text, "MySQL dump "):
+		summary.Tool = text
+		return "MySQL"
+	case strings.HasPrefix(text, "MariaDB dump "):
+		summary.Tool = text
+		return "MariaDB"
+	case strings.HasPrefix(text, "Server version"):
+		summary.ServerVersion = strings.TrimSpace(strings.TrimPrefix(text, "Server version"))
+		if strings.Contains(summary.ServerVersion, "MariaDB") {
+			return "MariaDB"
+		}
+	case strings.HasPrefix(text, "Dumped from database version "):
+		summary.ServerVersion = strings.TrimPrefix(text, "Dumped from database version ")
+		return "PostgreSQL"
+	case strings.HasPrefix(text, "Dumped by pg_dump version "):
+		summary.Tool = "pg_dump " + strings.TrimPrefix(text, "Dumped by pg_dump version ")
+		return "PostgreSQL"
+	}
+	return ""
+}
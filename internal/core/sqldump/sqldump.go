@@ -0,0 +1,203 @@
+// Package sqldump parses the line oriented output of mysqldump and pg_dump
+// well enough to rewrite row data without loading a whole dump into memory.
+package sqldump
+
+import (
+	"strings"
+)
+
+// Dialect selects the quoting rules of a dump
+type Dialect string
+
+const (
+	// DialectMySQL covers mysqldump and mariadb-dump: backslash escapes in strings
+	DialectMySQL Dialect = "mysql"
+	// DialectPostgres covers pg_dump: standard conforming strings and COPY blocks
+	DialectPostgres Dialect = "postgres"
+)
+
+// CopyTerminator ends the data of a COPY ... FROM stdin block
+const CopyTerminator = `\.`
+
+// UnquoteIdent strips identifier quotes from every part of a possibly
+// schema-qualified name, e.g. `app`.`users` or public."Users"
+func UnquoteIdent(ident string) string {
+	parts := splitQualified(strings.TrimSpace(ident))
+	for i, part := range parts {
+		if len(part) >= 2 && (part[0] == '`' || part[0] == '"') && part[len(part)-1] == part[0] {
+			q := string(part[0])
+			part = strings.ReplaceAll(part[1:len(part)-1], q+q, q)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, ".")
+}
+
+// BaseName returns the table name without its schema
+func BaseName(table string) string {
+	if idx := strings.LastIndex(table, "."); idx != -1 {
+		return table[idx+1:]
+	}
+	return table
+}
+
+// splitQualified splits a name on dots that are outside identifier quotes
+func splitQualified(ident string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(ident); i++ {
+		c := ident[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '`' || c == '"':
+			quote = c
+		case c == '.':
+			parts = append(parts, ident[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, ident[start:])
+}
+
+// readIdent reads a possibly quoted, possibly schema-qualified identifier at
+// the start of s and returns it together with the remainder of s
+func readIdent(s string) (ident, rest string) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '`' || c == '"':
+			quote = c
+		case c == ' ' || c == '\t' || c == '(' || c == ',' || c == ';' || c == '\n' || c == '\r':
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+// ParseCreateTable recognises the first line of a CREATE TABLE statement
+func ParseCreateTable(line string) (table string, ok bool) {
+	rest, found := cutPrefixFold(strings.TrimSpace(line), "CREATE TABLE ")
+	if !found {
+		return "", false
+	}
+	if r, found := cutPrefixFold(rest, "IF NOT EXISTS "); found {
+		rest = r
+	}
+
+	ident, _ := readIdent(strings.TrimSpace(rest))
+	if ident == "" {
+		return "", false
+	}
+	return UnquoteIdent(ident), true
+}
+
+// ParseColumnDefinition returns the column declared by a line inside a CREATE
+// TABLE body. Constraint and index lines are not columns.
+func ParseColumnDefinition(line string) (column string, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, ")") {
+		return "", false
+	}
+
+	for _, keyword := range []string{"PRIMARY ", "KEY ", "UNIQUE ", "INDEX ", "CONSTRAINT ", "FOREIGN ", "FULLTEXT ", "SPATIAL ", "CHECK ", "CHECK(", "EXCLUDE "} {
+		if _, found := cutPrefixFold(trimmed, keyword); found {
+			return "", false
+		}
+	}
+
+	ident, _ := readIdent(trimmed)
+	if ident == "" {
+		return "", false
+	}
+	return UnquoteIdent(ident), true
+}
+
+// IsCreateTableEnd reports whether line closes a CREATE TABLE body
+func IsCreateTableEnd(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), ")")
+}
+
+// ParseCopy recognises a "COPY table (columns) FROM stdin;" line
+func ParseCopy(line string) (table string, columns []string, ok bool) {
+	rest, found := cutPrefixFold(strings.TrimSpace(line), "COPY ")
+	if !found || !strings.Contains(strings.ToUpper(rest), "FROM STDIN") {
+		return "", nil, false
+	}
+
+	ident, rest := readIdent(rest)
+	table = UnquoteIdent(ident)
+
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end == -1 {
+			return "", nil, false
+		}
+		columns = splitColumnList(rest[1:end])
+	}
+
+	return table, columns, true
+}
+
+func splitColumnList(list string) []string {
+	var columns []string
+	for _, part := range strings.Split(list, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			columns = append(columns, UnquoteIdent(part))
+		}
+	}
+	return columns
+}
+
+// IsInsert reports whether line starts an INSERT statement
+func IsInsert(line string) bool {
+	_, found := cutPrefixFold(line, "INSERT INTO ")
+	return found
+}
+
+// StatementComplete reports whether stmt ends with a semicolon outside of any
+// string literal, i.e. no further lines belong to it
+func StatementComplete(stmt string, d Dialect) bool {
+	inString := false
+	last := byte(0)
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		if inString {
+			switch {
+			case c == '\\' && d == DialectMySQL:
+				i++
+			case c == '\'':
+				if i+1 < len(stmt) && stmt[i+1] == '\'' {
+					i++
+				} else {
+					inString = false
+				}
+			}
+			continue
+		}
+		switch c {
+		case '\'':
+			inString = true
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		last = c
+	}
+	return !inString && last == ';'
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,465 @@
+package sqldump
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnquoteIdent(t *testing.T) {
+	tests := map[string]string{
+		"users":                "users",
+		"`users`":              "users",
+		"`app`.`users`":        "app.users",
+		`public."Users"`:       "public.Users",
+		`"we""ird"`:            `we"ird`,
+		"`with.dot`":           "with.dot",
+		"public.user_accounts": "public.user_accounts",
+	}
+	for input, want := range tests {
+		if got := UnquoteIdent(input); got != want {
+			t.Errorf("UnquoteIdent(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestParseCreateTable(t *testing.T) {
+	table, ok := ParseCreateTable("CREATE TABLE `users` (\n")
+	if !ok || table != "users" {
+		t.Fatalf("got %q, %v", table, ok)
+	}
+
+	table, ok = ParseCreateTable("CREATE TABLE public.users (\n")
+	if !ok || table != "public.users" {
+		t.Fatalf("got %q, %v", table, ok)
+	}
+
+	if _, ok := ParseCreateTable("CREATE DATABASE app;\n"); ok {
+		t.Error("expected CREATE DATABASE not to match")
+	}
+}
+
+func TestParseColumnDefinition(t *testing.T) {
+	columns := []string{
+		"  `id` int NOT NULL AUTO_INCREMENT,",
+		"  `email` varchar(255) NOT NULL,",
+		"    name character varying(255),",
+		`    "Phone" text`,
+		"  PRIMARY KEY (`id`),",
+		"  UNIQUE KEY `uniq_email` (`email`),",
+		"  CONSTRAINT `fk` FOREIGN KEY (`x`) REFERENCES `y` (`id`)",
+		") ENGINE=InnoDB;",
+	}
+
+	var got []string
+	for _, line := range columns {
+		if col, ok := ParseColumnDefinition(line); ok {
+			got = append(got, col)
+		}
+	}
+
+	want := []string{"id", "email", "name", "Phone"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %v, want %v", got, want)
+	}
+}
+
+func TestParseCopy(t *testing.T) {
+	table, columns, ok := ParseCopy("COPY public.users (id, email, \"Name\") FROM stdin;\n")
+	if !ok {
+		t.Fatal("expected COPY line to match")
+	}
+	if table != "public.users" {
+		t.Errorf("table = %q", table)
+	}
+	if want := []string{"id", "email", "Name"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %v, want %v", columns, want)
+	}
+
+	if _, _, ok := ParseCopy("COPY users TO '/tmp/x';"); ok {
+		t.Error("expected COPY TO not to match")
+	}
+}
+
+func TestStatementComplete(t *testing.T) {
+	tests := []struct {
+		stmt    string
+		dialect Dialect
+		want    bool
+	}{
+		{"INSERT INTO t VALUES (1);\n", DialectMySQL, true},
+		{"INSERT INTO t VALUES ('a;\n", DialectMySQL, false},
+		{"INSERT INTO t VALUES ('a\\';');", DialectMySQL, true},
+		{"INSERT INTO t VALUES ('a\\';');", DialectPostgres, false},
+		{"INSERT INTO t VALUES ('it''s');", DialectPostgres, true},
+		{"INSERT INTO t VALUES ('multi\nline');\n", DialectPostgres, true},
+	}
+	for _, tt := range tests {
+		if got := StatementComplete(tt.stmt, tt.dialect); got != tt.want {
+			t.Errorf("StatementComplete(%q, %s) = %v, want %v", tt.stmt, tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestParseInsert_MySQL(t *testing.T) {
+	stmt := "INSERT INTO `users` VALUES (1,'a@b.c','O\\'Brien',NULL),(2,'x@y.z','f(o,o)',ST_GeomFromText('POINT(1 2)'));\n"
+
+	ins, err := ParseInsert(stmt, DialectMySQL)
+	if err != nil {
+		t.Fatalf("ParseInsert() error = %v", err)
+	}
+	if ins.Table != "users" || ins.Columns != nil {
+		t.Errorf("table = %q, columns = %v", ins.Table, ins.Columns)
+	}
+	if len(ins.Rows) != 2 || len(ins.Rows[0]) != 4 || len(ins.Rows[1]) != 4 {
+		t.Fatalf("rows = %v", ins.Rows)
+	}
+	if got := ins.Rows[0][2].Text(DialectMySQL); got != "O'Brien" {
+		t.Errorf("text = %q", got)
+	}
+	if !ins.Rows[0][3].IsNull() {
+		t.Errorf("expected NULL, got %q", ins.Rows[0][3])
+	}
+	if got := ins.Rows[1][3]; got != "ST_GeomFromText('POINT(1 2)')" {
+		t.Errorf("function value = %q", got)
+	}
+
+	if got := ins.String(); got != stmt {
+		t.Errorf("round trip:\n got %q\nwant %q", got, stmt)
+	}
+}
+
+func TestParseInsert_PostgresColumns(t *testing.T) {
+	stmt := "INSERT INTO public.users (id, email) VALUES (1, 'it''s@x.y');\n"
+
+	ins, err := ParseInsert(stmt, DialectPostgres)
+	if err != nil {
+		t.Fatalf("ParseInsert() error = %v", err)
+	}
+	if want := []string{"id", "email"}; !reflect.DeepEqual(ins.Columns, want) {
+		t.Errorf("columns = %v", ins.Columns)
+	}
+	if got := ins.Rows[0][1].Text(DialectPostgres); got != "it's@x.y" {
+		t.Errorf("text = %q", got)
+	}
+
+	ins.Rows[0][1] = QuoteString("new", DialectPostgres)
+	if got, want := ins.String(), "INSERT INTO public.users (id, email) VALUES (1,'new');\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseInsert_Errors(t *testing.T) {
+	for _, stmt := range []string{
+		"INSERT INTO t (a, b VALUES (1);",
+		"INSERT INTO t SELECT 1;",
+		"INSERT INTO t VALUES (1, 'open);",
+	} {
+		if _, err := ParseInsert(stmt, DialectMySQL); err == nil {
+			t.Errorf("ParseInsert(%q) expected error", stmt)
+		}
+	}
+}
+
+func TestCountInsertRows(t *testing.T) {
+	stmt := "INSERT INTO `users` VALUES (1,'a)(b',NULL),(2,'O\\'Brien',ST_GeomFromText('POINT(1 2)')),(3,'',NULL);"
+
+	table, rows, err := CountInsertRows(stmt, DialectMySQL)
+	if err != nil {
+		t.Fatalf("CountInsertRows() error = %v", err)
+	}
+	if table != "users" || rows != 3 {
+		t.Errorf("got %q, %d rows", table, rows)
+	}
+
+	if _, _, err := CountInsertRows("INSERT INTO t VALUES (1, 'open);", DialectMySQL); err == nil {
+		t.Error("expected error for unterminated string")
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	if got := QuoteString("a'b\\c\n", DialectMySQL); got != `'a\'b\\c\n'` {
+		t.Errorf("mysql = %s", got)
+	}
+	if got := QuoteString("a'b\\c", DialectPostgres); got != `'a''b\c'` {
+		t.Errorf("postgres = %s", got)
+	}
+
+	for _, d := range []Dialect{DialectMySQL, DialectPostgres} {
+		input := "tricky 'value' with \\ and\nnewline"
+		if got := QuoteString(input, d).Text(d); got != input {
+			t.Errorf("%s round trip = %q", d, got)
+		}
+	}
+}
+
+func TestCopyEscaping(t *testing.T) {
+	fields := SplitCopyRow("1\ta\\tb\t\\N")
+	if len(fields) != 3 {
+		t.Fatalf("fields = %v", fields)
+	}
+	if got := UnescapeCopy(fields[1]); got != "a\tb" {
+		t.Errorf("unescape = %q", got)
+	}
+	if fields[2] != CopyNull {
+		t.Errorf("null = %q", fields[2])
+	}
+
+	input := "back\\slash\ttab\nnewline"
+	if got := UnescapeCopy(EscapeCopy(input)); got != input {
+		t.Errorf("round trip = %q", got)
+	}
+}
+
+func TestInspect_MySQL(t *testing.T) {
+	dump := strings.Join([]string{
+		"-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)",
+		"--",
+		"-- Host: localhost    Database: app",
+		"-- ------------------------------------------------------",
+		"-- Server version\t8.0.36",
+		"",
+		"/*!40101 SET NAMES utf8mb4 */;",
+		"DROP TABLE IF EXISTS `users`;",
+		"CREATE TABLE `users` (",
+		"  `id` int NOT NULL,",
+		"  `bio` text,",
+		"  PRIMARY KEY (`id`)",
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+		"LOCK TABLES `users` WRITE;",
+		"INSERT INTO `users` VALUES (1,'semi;colon\\'s'),(2,NULL);",
+		"INSERT INTO `users` VALUES (3,'multi",
+		"line');",
+		"UNLOCK TABLES;",
+		"CREATE TABLE `empty` (`id` int);",
+		"-- Dump completed on 2026-01-01 10:00:00",
+		"",
+	}, "\n")
+
+	summary, err := Inspect(strings.NewReader(dump), DialectPostgres)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+
+	if summary.Engine != "MySQL" || summary.ServerVersion != "8.0.36" || summary.Charset != "utf8mb4" {
+		t.Errorf("header = %q, %q, %q", summary.Engine, summary.ServerVersion, summary.Charset)
+	}
+	if !strings.HasPrefix(summary.Tool, "MySQL dump 10.13") {
+		t.Errorf("tool = %q", summary.Tool)
+	}
+	if summary.Size != int64(len(dump)) {
+		t.Errorf("size = %d, want %d", summary.Size, len(dump))
+	}
+
+	if len(summary.Tables) != 2 {
+		t.Fatalf("tables = %+v", summary.Tables)
+	}
+	users := summary.Tables[0]
+	if users.Name != "users" || users.Rows != 3 {
+		t.Errorf("users = %q, %d rows", users.Name, users.Rows)
+	}
+	if !strings.HasPrefix(users.Create, "CREATE TABLE `users` (") || !strings.HasSuffix(users.Create, "CHARSET=utf8mb4;") {
+		t.Errorf("create = %q", users.Create)
+	}
+	if users.Bytes <= int64(len(users.Create)) {
+		t.Errorf("bytes = %d, expected the rows to count", users.Bytes)
+	}
+	if empty := summary.Tables[1]; empty.Name != "empty" || empty.Rows != 0 || empty.Create != "CREATE TABLE `empty` (`id` int);" {
+		t.Errorf("empty = %+v", empty)
+	}
+}
+
+func TestInspect_Postgres(t *testing.T) {
+	dump := strings.Join([]string{
+		"--",
+		"-- PostgreSQL database dump",
+		"--",
+		"",
+		"-- Dumped from database version 16.2 (Debian 16.2-1.pgdg120+2)",
+		"-- Dumped by pg_dump version 16.2",
+		"",
+		"SET client_encoding = 'UTF8';",
+		"CREATE TABLE public.users (",
+		"    id integer NOT NULL,",
+		"    email text",
+		");",
+		"COPY public.users (id, email) FROM stdin;",
+		"1\ta@b.c",
+		"2\t\\N",
+		"\\.",
+		"INSERT INTO public.users VALUES (3, 'it''s');",
+		"",
+		"-- PostgreSQL database dump complete",
+		"",
+	}, "\n")
+
+	summary, err := Inspect(strings.NewReader(dump), DialectMySQL)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+
+	if summary.Engine != "PostgreSQL" || summary.Tool != "pg_dump 16.2" || summary.Charset != "UTF8" {
+		t.Errorf("header = %q, %q, %q", summary.Engine, summary.Tool, summary.Charset)
+	}
+	if summary.ServerVersion != "16.2 (Debian 16.2-1.pgdg120+2)" {
+		t.Errorf("server version = %q", summary.ServerVersion)
+	}
+	if len(summary.Tables) != 1 || summary.Tables[0].Name != "public.users" || summary.Tables[0].Rows != 3 {
+		t.Errorf("tables = %+v", summary.Tables)
+	}
+}
+
+func TestInspect_WithoutHeader(t *testing.T) {
+	dump := "INSERT INTO t VALUES ('a\\'),('b');\n"
+
+	summary, err := Inspect(strings.NewReader(dump), DialectPostgres)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if summary.Engine != "" || len(summary.Tables) != 1 || summary.Tables[0].Rows != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+// filterDump runs dump through a TableFilter for patterns
+func filterDump(t *testing.T, dump string, d Dialect, patterns ...string) (string, *TableFilter) {
+	t.Helper()
+	var out strings.Builder
+	f := NewTableFilter(&out, d, patterns)
+	if _, err := f.Write([]byte(dump)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return out.String(), f
+}
+
+func TestTableFilter_MySQL(t *testing.T) {
+	dump := strings.Join([]string{
+		"-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)",
+		"/*!40101 SET NAMES utf8mb4 */;",
+		"/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;",
+		"DROP TABLE IF EXISTS `category`;",
+		"CREATE TABLE `category` (",
+		"  `id` int NOT NULL",
+		") ENGINE=InnoDB;",
+		"LOCK TABLES `category` WRITE;",
+		"/*!40000 ALTER TABLE `category` DISABLE KEYS */;",
+		"INSERT INTO `category` VALUES (1,'a;b');",
+		"/*!40000 ALTER TABLE `category` ENABLE KEYS */;",
+		"UNLOCK TABLES;",
+		"DROP TABLE IF EXISTS `product`;",
+		"CREATE TABLE `product` (",
+		"  `id` int NOT NULL",
+		") ENGINE=InnoDB;",
+		"LOCK TABLES `product` WRITE;",
+		"INSERT INTO `product` VALUES (1,'multi",
+		"line');",
+		"UNLOCK TABLES;",
+		"DELIMITER ;;",
+		"/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER `product_bi` BEFORE INSERT ON `product` FOR EACH ROW BEGIN",
+		"  SET NEW.id = NEW.id;",
+		"END */;;",
+		"/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER `category_bi` BEFORE INSERT ON `category` FOR EACH ROW BEGIN",
+		"  SET NEW.id = NEW.id;",
+		"END */;;",
+		"DELIMITER ;",
+		"-- Dump completed on 2026-01-01 10:00:00",
+		"",
+	}, "\n")
+
+	got, f := filterDump(t, dump, DialectMySQL, "product")
+
+	for _, kept := range []string{
+		"SET NAMES utf8mb4", "FOREIGN_KEY_CHECKS=0", "DROP TABLE IF EXISTS `product`;", "CREATE TABLE `product` (",
+		"LOCK TABLES `product` WRITE;", "line');", "TRIGGER `product_bi`", "DELIMITER ;;", "-- Dump completed",
+	} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %q to be kept:\n%s", kept, got)
+		}
+	}
+	if strings.Contains(got, "category") {
+		t.Errorf("expected the statements of category to be dropped:\n%s", got)
+	}
+	if want := []string{"product"}; !reflect.DeepEqual(f.Matched(), want) {
+		t.Errorf("Matched() = %v, want %v", f.Matched(), want)
+	}
+}
+
+func TestTableFilter_Postgres(t *testing.T) {
+	dump := strings.Join([]string{
+		"SET client_encoding = 'UTF8';",
+		"CREATE TABLE public.category (",
+		"    id integer NOT NULL",
+		");",
+		"CREATE SEQUENCE public.category_id_seq",
+		"    AS integer",
+		"    START WITH 1;",
+		"ALTER SEQUENCE public.category_id_seq OWNED BY public.category.id;",
+		"CREATE TABLE public.product (",
+		"    id integer NOT NULL,",
+		"    category_id integer",
+		");",
+		"CREATE SEQUENCE public.product_id_seq",
+		"    AS integer",
+		"    START WITH 1;",
+		"ALTER SEQUENCE public.product_id_seq OWNED BY public.product.id;",
+		"CREATE TABLE public.tag (",
+		"    id integer NOT NULL",
+		");",
+		"ALTER TABLE public.tag ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (",
+		"    SEQUENCE NAME public.tag_id_seq",
+		"    START WITH 1",
+		");",
+		"ALTER TABLE ONLY public.product ALTER COLUMN id SET DEFAULT nextval('public.product_id_seq'::regclass);",
+		"COPY public.category (id) FROM stdin;",
+		"1",
+		"\\.",
+		"COPY public.product (id, category_id) FROM stdin;",
+		"1\t1",
+		"\\.",
+		"COPY public.tag (id) FROM stdin;",
+		"1",
+		"\\.",
+		"SELECT pg_catalog.setval('public.category_id_seq', 1, true);",
+		"SELECT pg_catalog.setval('public.product_id_seq', 1, true);",
+		"SELECT pg_catalog.setval('public.tag_id_seq', 1, true);",
+		"CREATE INDEX idx_product_category ON public.product USING btree (category_id);",
+		"CREATE INDEX idx_category ON public.category USING btree (id);",
+		"ALTER TABLE ONLY public.product",
+		"    ADD CONSTRAINT fk_category FOREIGN KEY (category_id) REFERENCES public.category(id);",
+		"",
+	}, "\n")
+
+	got, f := filterDump(t, dump, DialectPostgres, "product", "t*")
+
+	for _, kept := range []string{
+		"SET client_encoding", "CREATE TABLE public.product (", "CREATE SEQUENCE public.product_id_seq",
+		"OWNED BY public.product.id", "SET DEFAULT nextval", "1\t1\n\\.", "setval('public.product_id_seq'",
+		"setval('public.tag_id_seq'", "idx_product_category", "ADD CONSTRAINT fk_category",
+	} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %q to be kept:\n%s", kept, got)
+		}
+	}
+	if strings.Contains(got, "category_id_seq") || strings.Contains(got, "CREATE TABLE public.category") ||
+		strings.Contains(got, "idx_category") || strings.Contains(got, "COPY public.category") {
+		t.Errorf("expected the statements of category to be dropped:\n%s", got)
+	}
+	if strings.Index(got, "CREATE SEQUENCE public.product_id_seq") > strings.Index(got, "OWNED BY public.product.id") {
+		t.Errorf("expected the sequence to be created before its owner is set:\n%s", got)
+	}
+	if want := []string{"public.product", "public.tag"}; !reflect.DeepEqual(f.Matched(), want) {
+		t.Errorf("Matched() = %v, want %v", f.Matched(), want)
+	}
+}
+
+func TestTableFilter_TruncatedDump(t *testing.T) {
+	var out strings.Builder
+	f := NewTableFilter(&out, DialectMySQL, []string{"product"})
+	f.Write([]byte("INSERT INTO `product` VALUES (1,'open\n"))
+	if err := f.Close(); err == nil {
+		t.Error("expected Close() to fail inside an INSERT")
+	}
+}
@@ -0,0 +1,24 @@
+package types
+
+// ComposeRuntime identifies the tool that runs compose projects
+type ComposeRuntime string
+
+const (
+	// RuntimeAuto picks the first runtime found on PATH
+	RuntimeAuto ComposeRuntime = "auto"
+	// RuntimeDocker is the compose v2 plugin, docker compose
+	RuntimeDocker ComposeRuntime = "docker"
+	// RuntimeDockerCompose is the legacy standalone docker-compose
+	RuntimeDockerCompose ComposeRuntime = "docker-compose"
+	RuntimePodman        ComposeRuntime = "podman"
+	RuntimeNerdctl       ComposeRuntime = "nerdctl"
+)
+
+// IsValid reports whether r is a supported runtime ("" means auto)
+func (r ComposeRuntime) IsValid() bool {
+	switch r {
+	case "", RuntimeAuto, RuntimeDocker, RuntimeDockerCompose, RuntimePodman, RuntimeNerdctl:
+		return true
+	}
+	return false
+}
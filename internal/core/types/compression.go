@@ -0,0 +1,53 @@
+package types
+
+import "strings"
+
+// Compression identifies how a dump file is compressed
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// dumpExtensions maps every recognised dump file extension to its compression.
+// Longer extensions come first so ".sql.gz" is not mistaken for something else.
+var dumpExtensions = []struct {
+	ext         string
+	compression Compression
+}{
+	{".sql.gz", CompressionGzip},
+	{".sql.zst", CompressionZstd},
+	{".sql", CompressionNone},
+}
+
+// IsValid reports whether c is a supported compression ("" means none)
+func (c Compression) IsValid() bool {
+	switch c {
+	case "", CompressionNone, CompressionGzip, CompressionZstd:
+		return true
+	}
+	return false
+}
+
+// Extension returns the full dump file extension for this compression
+func (c Compression) Extension() string {
+	for _, e := range dumpExtensions {
+		if e.compression == c {
+			return e.ext
+		}
+	}
+	return ".sql"
+}
+
+// ParseDumpFilename splits a dump filename into its base name and compression.
+// ok is false when the filename does not carry a recognised dump extension.
+func ParseDumpFilename(filename string) (base string, compression Compression, ok bool) {
+	for _, e := range dumpExtensions {
+		if strings.HasSuffix(filename, e.ext) {
+			return strings.TrimSuffix(filename, e.ext), e.compression, true
+		}
+	}
+	return "", "", false
+}
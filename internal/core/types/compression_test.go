@@ -0,0 +1,43 @@
+package types
+
+import "testing"
+
+func TestParseDumpFilename(t *testing.T) {
+	tests := []struct {
+		filename    string
+		base        string
+		compression Compression
+		ok          bool
+	}{
+		{"app_2025-02-11T10-30.sql", "app_2025-02-11T10-30", CompressionNone, true},
+		{"app_2025-02-11T10-30.sql.gz", "app_2025-02-11T10-30", CompressionGzip, true},
+		{"app_2025-02-11T10-30.sql.zst", "app_2025-02-11T10-30", CompressionZstd, true},
+		{"app.sql.tmp", "", "", false},
+		{"notes.txt", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			base, compression, ok := ParseDumpFilename(tt.filename)
+			if base != tt.base || compression != tt.compression || ok != tt.ok {
+				t.Errorf("ParseDumpFilename(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.filename, base, compression, ok, tt.base, tt.compression, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCompressionExtension(t *testing.T) {
+	tests := map[Compression]string{
+		"":              ".sql",
+		CompressionNone: ".sql",
+		CompressionGzip: ".sql.gz",
+		CompressionZstd: ".sql.zst",
+	}
+
+	for compression, expected := range tests {
+		if got := compression.Extension(); got != expected {
+			t.Errorf("Compression(%q).Extension() = %q, want %q", compression, got, expected)
+		}
+	}
+}
@@ -152,3 +152,18 @@ func TestDSNStringSpecialCharactersInPassword(t *testing.T) {
 		t.Errorf("DSN.String() should URL-encode special chars in password, got %q", result)
 	}
 }
+
+func TestDSNStringSQLite(t *testing.T) {
+	dsn := &DSN{
+		Engine:   "sqlite",
+		Database: "data_feature",
+		Path:     "%kernel.project_dir%/var/data.db",
+	}
+
+	result := dsn.String()
+
+	expected := "sqlite:///%kernel.project_dir%/var/data_feature.db"
+	if result != expected {
+		t.Errorf("DSN.String() = %q, want %q", result, expected)
+	}
+}
@@ -3,7 +3,10 @@ package types
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 )
 
 type ErrCode string
@@ -18,6 +21,12 @@ const (
 	ErrFileNotFound        ErrCode = "FILE_NOT_FOUND"
 	ErrInvalidWorktree     ErrCode = "INVALID_WORKTREE"
 	ErrDependenciesMissing ErrCode = "DEPENDENCIES_MISSING"
+	ErrAlreadyExists       ErrCode = "ALREADY_EXISTS"
+	ErrCanceled            ErrCode = "CANCELED"
+	ErrTimeout             ErrCode = "TIMEOUT"
+	ErrServiceShared       ErrCode = "SERVICE_SHARED"
+	ErrDumpInvalid         ErrCode = "DUMP_INVALID"
+	ErrQueryRejected       ErrCode = "QUERY_REJECTED"
 )
 
 type CommandError struct {
@@ -25,8 +34,9 @@ type CommandError struct {
 	Message string  `json:"message"`
 }
 
+// Error returns the message with DSN passwords and other registered secrets masked
 func (e *CommandError) Error() string {
-	return e.Message
+	return redact.String(e.Message)
 }
 
 type ProgressStage string
@@ -93,6 +103,8 @@ type WorktreeInfo struct {
 	Path   string `json:"path"`
 	Branch string `json:"branch"`
 	IsMain bool   `json:"is_main"`
+	// Migrations compares the worktree's migrations with its database, only when asked for
+	Migrations *MigrationStatus `json:"migrations,omitempty"`
 }
 
 type WorktreeCreateResult struct {
@@ -114,13 +126,41 @@ type WorkflowCreateResult struct {
 type WorkflowRemoveResult struct {
 	WorktreePath string `json:"worktree_path"`
 	DatabaseName string `json:"database_name,omitempty"`
+	// DatabaseBackup is the dump taken before the database was dropped
+	DatabaseBackup string `json:"database_backup,omitempty"`
+}
+
+// TableFilter selects the tables of a dump and which parts of them are
+// included. Table lists accept glob patterns such as "messenger_*".
+type TableFilter struct {
+	Tables        []string `json:"tables,omitempty"`         // only dump these tables; all when empty
+	ExcludeTables []string `json:"exclude_tables,omitempty"` // leave these tables out entirely
+	StructureOnly []string `json:"structure_only,omitempty"` // dump the schema of these tables without rows
+	SchemaOnly    bool     `json:"schema_only,omitempty"`    // no rows at all
+	DataOnly      bool     `json:"data_only,omitempty"`      // no CREATE statements
+}
+
+// IsEmpty reports whether the filter selects the whole database
+func (f TableFilter) IsEmpty() bool {
+	return len(f.Tables) == 0 && len(f.ExcludeTables) == 0 && len(f.StructureOnly) == 0 && !f.SchemaOnly && !f.DataOnly
+}
+
+// DumpOptions selects what goes into a dump
+type DumpOptions struct {
+	TableFilter
+	Anonymize bool         `json:"anonymize,omitempty"` // apply the database.anonymize rules
+	Progress  ProgressFunc `json:"-"`                   // receives the bytes written so far
 }
 
 type DumpResult struct {
-	Path     string        `json:"path"`
-	Size     int64         `json:"size"`
-	Database string        `json:"database"`
-	Duration time.Duration `json:"duration"`
+	Path         string        `json:"path"`
+	Size         int64         `json:"size"`
+	Database     string        `json:"database"`
+	Checksum     string        `json:"checksum"`
+	Duration     time.Duration `json:"duration"`
+	MetadataPath string        `json:"metadata_path,omitempty"`
+	Anonymized   bool          `json:"anonymized,omitempty"`
+	Pruned       []PrunedDump  `json:"pruned,omitempty"` // removed by auto_prune after this dump
 }
 
 type CreateResult struct {
@@ -130,11 +170,15 @@ type CreateResult struct {
 type ImportResult struct {
 	Path     string        `json:"path"`
 	Database string        `json:"database"`
+	Tables   []string      `json:"tables,omitempty"` // imported tables of a partial import
+	Backup   string        `json:"backup,omitempty"` // dump taken before the import, see backup_before_destructive
 	Duration time.Duration `json:"duration"`
 }
 
 type DropResult struct {
 	Database string `json:"database"`
+	// Backup is the dump of the database taken before the drop, see backup_before_destructive
+	Backup string `json:"backup,omitempty"`
 }
 
 type DSN struct {
@@ -145,10 +189,18 @@ type DSN struct {
 	Port          string
 	Database      string
 	ServerVersion string
+	// Path is the database file of a SQLite DSN as written, which may start
+	// with %kernel.project_dir%. Database is its name without the extension.
+	Path string
 }
 
 // String returns the DSN as a URL string
 func (d *DSN) String() string {
+	if d.Engine == "sqlite" {
+		// Other databases are the files next to the DSN's file
+		return "sqlite:///" + path.Join(path.Dir(d.Path), d.Database+path.Ext(d.Path))
+	}
+
 	scheme := d.Engine
 	if scheme == "postgresql" {
 		scheme = "postgres"
@@ -159,12 +211,10 @@ func (d *DSN) String() string {
 		hostPort = fmt.Sprintf("%s:%s", d.Host, d.Port)
 	}
 
-	path := "/" + d.Database
-
 	u := &url.URL{
 		Scheme: scheme,
 		Host:   hostPort,
-		Path:   path,
+		Path:   "/" + d.Database,
 	}
 
 	if d.User != "" || d.Password != "" {
@@ -181,28 +231,359 @@ func (d *DSN) String() string {
 }
 
 type DatabaseInfo struct {
+	Name      string         `json:"name"`
+	IsDefault bool           `json:"is_default"`
+	Stats     *DatabaseStats `json:"stats,omitempty"` // only when asked for
+	// Migrations compares the database with the project's migrations, only when asked for
+	Migrations *MigrationStatus `json:"migrations,omitempty"`
+}
+
+// DatabaseStats sums up the tables of a database as the server's catalog
+// reports them. Row counts are estimates except for SQLite.
+type DatabaseStats struct {
+	Size       int64  `json:"size"` // bytes on disk
+	TableCount int    `json:"table_count"`
+	Rows       int64  `json:"rows"`
+	Modified   string `json:"modified,omitempty"` // last change to any table, when the engine tracks it
+}
+
+// TableStats describes one table of a database
+type TableStats struct {
 	Name      string `json:"name"`
-	IsDefault bool   `json:"is_default"`
+	Rows      int64  `json:"rows"`
+	DataSize  int64  `json:"data_size"`
+	IndexSize int64  `json:"index_size"`
+	Modified  string `json:"modified,omitempty"`
+}
+
+// DatabaseStatsResult lists the tables of a database, largest first
+type DatabaseStatsResult struct {
+	Database string        `json:"database"`
+	Stats    DatabaseStats `json:"stats"`
+	Tables   []TableStats  `json:"tables"`
+}
+
+// DatabaseSchema is the structure of a database as its catalog describes it
+type DatabaseSchema struct {
+	Tables []TableSchema `json:"tables"`
+}
+
+type TableSchema struct {
+	Name        string             `json:"name"` // schema-qualified outside of public for PostgreSQL
+	Columns     []ColumnSchema     `json:"columns"`
+	Indexes     []IndexSchema      `json:"indexes,omitempty"`
+	ForeignKeys []ForeignKeySchema `json:"foreign_keys,omitempty"`
+}
+
+type ColumnSchema struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Nullable bool    `json:"nullable"`
+	Default  *string `json:"default,omitempty"` // SQL expression, nil without a default
+	Extra    string  `json:"extra,omitempty"`   // e.g. auto_increment or identity
+}
+
+type IndexSchema struct {
+	Name    string   `json:"name"`
+	Primary bool     `json:"primary,omitempty"`
+	Unique  bool     `json:"unique,omitempty"`
+	Columns []string `json:"columns"`
+	Method  string   `json:"method,omitempty"` // e.g. BTREE, FULLTEXT or gin
+	// Constraint is set for PostgreSQL indexes backing a UNIQUE constraint
+	Constraint bool `json:"constraint,omitempty"`
+}
+
+type ForeignKeySchema struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+	OnDelete          string   `json:"on_delete,omitempty"`
+	OnUpdate          string   `json:"on_update,omitempty"`
+}
+
+// SchemaChangeKind names what differs between two schemas
+type SchemaChangeKind string
+
+const (
+	SchemaAddTable         SchemaChangeKind = "add_table"
+	SchemaDropTable        SchemaChangeKind = "drop_table"
+	SchemaAddColumn        SchemaChangeKind = "add_column"
+	SchemaDropColumn       SchemaChangeKind = "drop_column"
+	SchemaModifyColumn     SchemaChangeKind = "modify_column"
+	SchemaAddIndex         SchemaChangeKind = "add_index"
+	SchemaDropIndex        SchemaChangeKind = "drop_index"
+	SchemaModifyIndex      SchemaChangeKind = "modify_index"
+	SchemaAddForeignKey    SchemaChangeKind = "add_foreign_key"
+	SchemaDropForeignKey   SchemaChangeKind = "drop_foreign_key"
+	SchemaModifyForeignKey SchemaChangeKind = "modify_foreign_key"
+)
+
+// SchemaChange is one difference that has to be applied to the first schema
+// to get the second. From and To are readable definitions of the object in
+// each; the pointers hold the objects the ALTER statements are built from.
+type SchemaChange struct {
+	Kind  SchemaChangeKind `json:"kind"`
+	Table string           `json:"table"`
+	Name  string           `json:"name,omitempty"` // column, index or foreign key
+	From  string           `json:"from,omitempty"`
+	To    string           `json:"to,omitempty"`
+
+	NewTable      *TableSchema      `json:"-"`
+	OldColumn     *ColumnSchema     `json:"-"`
+	NewColumn     *ColumnSchema     `json:"-"`
+	OldIndex      *IndexSchema      `json:"-"`
+	NewIndex      *IndexSchema      `json:"-"`
+	NewForeignKey *ForeignKeySchema `json:"-"`
+}
+
+// SchemaDiffResult lists what differs between the schemas of two databases
+type SchemaDiffResult struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Changes []SchemaChange `json:"changes"`
+	// Statements turn the schema of From into that of To; only when asked for
+	Statements []string `json:"statements,omitempty"`
+}
+
+// QueryResult holds the rows of a read-only query. Values are text as the
+// database client prints them, nil for NULL.
+type QueryResult struct {
+	Database string      `json:"database"`
+	Columns  []string    `json:"columns"`
+	Rows     [][]*string `json:"rows"`
+	MaxRows  int         `json:"max_rows"`
+	// Truncated is set when the query returned more than MaxRows rows
+	Truncated bool          `json:"truncated"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// MigrationStatus compares the versions in the Doctrine migrations table of a
+// database with the migration classes in a migrations directory
+type MigrationStatus struct {
+	Database string `json:"database"`
+	Path     string `json:"path"` // migrations directory that was scanned
+	// Available counts the migration classes, Executed the recorded versions
+	Available int `json:"available"`
+	Executed  int `json:"executed"`
+	// Current is the latest executed version
+	Current string `json:"current,omitempty"`
+	// Pending lists the classes that were not executed yet
+	Pending []string `json:"pending"`
+	// Unknown lists executed versions without a class, e.g. those of a branch
+	// the database was cloned from
+	Unknown []string `json:"unknown"`
+	// NoTable is set when the database has no migrations table at all
+	NoTable bool `json:"no_table,omitempty"`
+}
+
+// OrphanKind tells which naming scheme an orphaned database follows
+type OrphanKind string
+
+const (
+	// OrphanWorktree is a <worktrees.db_prefix><branch> database without a worktree
+	// of that branch
+	OrphanWorktree OrphanKind = "worktree"
+	// OrphanBranch is a <db>_<branch> database of haive checkout, for a branch
+	// in the reflog, without a local branch or worktree of that name
+	OrphanBranch OrphanKind = "branch"
+)
+
+// OrphanDatabase is a database named after a branch or worktree that no
+// longer exists
+type OrphanDatabase struct {
+	Name    string     `json:"name"`
+	Kind    OrphanKind `json:"kind"`
+	Dropped bool       `json:"dropped,omitempty"`
+	// Error tells why the database was not dropped, e.g. a preDrop hook
+	Error string `json:"error,omitempty"`
+}
+
+type OrphanResult struct {
+	Orphans []OrphanDatabase `json:"orphans"`
+	Dropped int              `json:"dropped"`
 }
 
 type DatabaseListResult struct {
 	Databases []DatabaseInfo `json:"databases"`
 }
 
+// CloneStrategy describes how data was moved from the source to the target database
+type CloneStrategy string
+
+const (
+	// CloneStrategyPipe streams the dump process straight into the import process
+	CloneStrategyPipe CloneStrategy = "pipe"
+	// CloneStrategyFile dumps into a temporary SQL file and imports it afterwards
+	CloneStrategyFile CloneStrategy = "file"
+	// CloneStrategyCopy copies the database file of a SQLite database, or the
+	// tables of a MySQL database on the server
+	CloneStrategyCopy CloneStrategy = "copy"
+	// CloneStrategyTemplate creates the target from the source as a PostgreSQL template
+	CloneStrategyTemplate CloneStrategy = "template"
+)
+
+// CloneOptions tunes how a database is cloned
+type CloneOptions struct {
+	TableFilter
+	Anonymize bool `json:"anonymize,omitempty"` // apply the database.anonymize rules to the copied data
+}
+
 type CloneResult struct {
-	Source   string        `json:"source"`
-	Target   string        `json:"target"`
-	Size     int64         `json:"size"`
-	Duration time.Duration `json:"duration"`
+	Source     string        `json:"source"`
+	Target     string        `json:"target"`
+	Strategy   CloneStrategy `json:"strategy"`
+	Size       int64         `json:"size"` // bytes moved between source and target
+	Anonymized bool          `json:"anonymized,omitempty"`
+	Duration   time.Duration `json:"duration"`
 }
 
 type DumpFileInfo struct {
+	Name     string        `json:"name"`
+	Database string        `json:"database"`
+	Size     int64         `json:"size"`
+	Modified string        `json:"modified"`
+	Metadata *DumpMetadata `json:"metadata,omitempty"`
+}
+
+// DumpMetadata describes a dump file and is stored next to it as <dump>.meta.json
+type DumpMetadata struct {
+	Database      string        `json:"database"`
+	Engine        string        `json:"engine"`
+	ServerVersion string        `json:"server_version,omitempty"`
+	GitBranch     string        `json:"git_branch,omitempty"`
+	GitCommit     string        `json:"git_commit,omitempty"`
+	Tables        []string      `json:"tables,omitempty"`
+	ExcludeTables []string      `json:"exclude_tables,omitempty"`
+	StructureOnly []string      `json:"structure_only,omitempty"`
+	SchemaOnly    bool          `json:"schema_only,omitempty"`
+	DataOnly      bool          `json:"data_only,omitempty"`
+	Anonymized    bool          `json:"anonymized,omitempty"`
+	Compression   Compression   `json:"compression"`
+	Size          int64         `json:"size"`
+	Checksum      string        `json:"checksum"` // "sha256:<hex>" of the dump file as stored
+	ToolVersion   string        `json:"tool_version"`
+	Duration      time.Duration `json:"duration"`
+	CreatedAt     time.Time     `json:"created_at"`
+	// Trashed names the operation a backup in the trash was taken before: drop or import
+	Trashed string `json:"trashed,omitempty"`
+}
+
+// DumpVerification is the outcome of checking one file in the dumps directory
+type DumpVerification struct {
+	Name    string   `json:"name"` // relative to the dumps directory
+	Path    string   `json:"path"`
+	Size    int64    `json:"size"`
+	Valid   bool     `json:"valid"`
+	Checks  []string `json:"checks"` // what was verified: readable, checksum, complete or archive
+	Problem string   `json:"problem,omitempty"`
+}
+
+type DumpVerifyResult struct {
+	Dumps   []DumpVerification `json:"dumps"`
+	Invalid int                `json:"invalid"`
+}
+
+// DumpTableInfo is what a dump holds for one table
+type DumpTableInfo struct {
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`             // rows of its INSERT statements and COPY blocks
+	Bytes  int64  `json:"bytes"`            // SQL of its definition and rows
+	Create string `json:"create,omitempty"` // CREATE TABLE statement, absent in data-only dumps
+}
+
+type DumpInspectResult struct {
+	Name          string          `json:"name"` // relative to the dumps directory
+	Path          string          `json:"path"`
+	Size          int64           `json:"size"`     // of the file as stored
+	SQLSize       int64           `json:"sql_size"` // of the decompressed SQL
+	Engine        string          `json:"engine,omitempty"`
+	DumpTool      string          `json:"dump_tool,omitempty"`
+	ServerVersion string          `json:"server_version,omitempty"`
+	Charset       string          `json:"charset,omitempty"`
+	Tables        []DumpTableInfo `json:"tables"`
+	Metadata      *DumpMetadata   `json:"metadata,omitempty"`
+}
+
+type DumpsListResult struct {
+	Dumps []DumpFileInfo `json:"dumps"`
+}
+
+type PrunedDump struct {
 	Name     string `json:"name"`
 	Database string `json:"database"`
 	Size     int64  `json:"size"`
-	Modified string `json:"modified"`
+	Reason   string `json:"reason"` // retention rule that selected the dump
 }
 
-type DumpsListResult struct {
-	Dumps []DumpFileInfo `json:"dumps"`
+type PruneResult struct {
+	DryRun  bool         `json:"dry_run"`
+	Removed []PrunedDump `json:"removed"`
+	Kept    int          `json:"kept"`
+	Freed   int64        `json:"freed"`
+}
+
+type SnapshotResult struct {
+	Name     string        `json:"name"`
+	Database string        `json:"database"`
+	Path     string        `json:"path"`
+	Size     int64         `json:"size"`
+	Duration time.Duration `json:"duration"`
+}
+
+type RestoreResult struct {
+	Name     string        `json:"name"`
+	Database string        `json:"database"`
+	Path     string        `json:"path"`
+	Duration time.Duration `json:"duration"`
+}
+
+type VolumeSnapshotResult struct {
+	Name     string        `json:"name"`
+	Service  string        `json:"service"`
+	Volume   string        `json:"volume"`
+	Path     string        `json:"path"`
+	Size     int64         `json:"size"`
+	Duration time.Duration `json:"duration"`
+}
+
+type VolumeRestoreResult struct {
+	Name     string        `json:"name"`
+	Service  string        `json:"service"`
+	Volume   string        `json:"volume"`
+	Path     string        `json:"path"`
+	Duration time.Duration `json:"duration"`
+}
+
+type SnapshotInfo struct {
+	Name      string `json:"name"`
+	Database  string `json:"database"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Created   string `json:"created"`
+	GitBranch string `json:"git_branch,omitempty"`
+}
+
+// TrashedDatabase is a backup taken before a destructive operation
+type TrashedDatabase struct {
+	Database  string `json:"database"`
+	Operation string `json:"operation"` // drop or import
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Created   string `json:"created"`
+	GitBranch string `json:"git_branch,omitempty"`
+}
+
+type TrashListResult struct {
+	Trash []TrashedDatabase `json:"trash"` // newest first
+}
+
+// UndoResult describes a database brought back from the trash
+type UndoResult struct {
+	TrashedDatabase
+	Duration time.Duration `json:"duration"`
+}
+
+type SnapshotListResult struct {
+	Snapshots []SnapshotInfo `json:"snapshots"`
 }
@@ -0,0 +1,4 @@
+package core
+
+// Version is the haive release version reported by the MCP server and recorded in dump metadata
+const Version = "1.0.0"
@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// composeRuntimes lists the runtimes in the order auto-detection tries them
+var composeRuntimes = []types.ComposeRuntime{
+	types.RuntimeDocker,
+	types.RuntimeDockerCompose,
+	types.RuntimePodman,
+	types.RuntimeNerdctl,
+}
+
+// Compose builds the command lines of one compose project. The zero Compose
+// runs docker compose with the default compose file.
+type Compose struct {
+	Runtime types.ComposeRuntime
+	Files   []string
+}
+
+// NewCompose resolves runtime, detecting one for auto, and fails with
+// DEPENDENCIES_MISSING if its binary is not on PATH
+func NewCompose(runtime types.ComposeRuntime, files []string) (Compose, error) {
+	if runtime == "" || runtime == types.RuntimeAuto {
+		detected, err := detectComposeRuntime()
+		if err != nil {
+			return Compose{}, err
+		}
+		runtime = detected
+	}
+
+	compose := Compose{Runtime: runtime, Files: files}
+	if _, err := exec.LookPath(compose.binary()); err != nil {
+		return Compose{}, &types.CommandError{
+			Code:    types.ErrDependenciesMissing,
+			Message: fmt.Sprintf("compose runtime %s requires %s on PATH", runtime, compose.binary()),
+		}
+	}
+	return compose, nil
+}
+
+// detectComposeRuntime returns the first runtime whose binary is on PATH.
+// docker alone doesn't tell whether the compose plugin is installed, so when
+// docker-compose is there too, docker compose version decides between them.
+func detectComposeRuntime() (types.ComposeRuntime, error) {
+	var found []types.ComposeRuntime
+	for _, runtime := range composeRuntimes {
+		if _, err := exec.LookPath(Compose{Runtime: runtime}.binary()); err == nil {
+			found = append(found, runtime)
+		}
+	}
+
+	if len(found) == 0 {
+		return "", &types.CommandError{
+			Code:    types.ErrDependenciesMissing,
+			Message: "no compose runtime found: install docker, docker-compose, podman or nerdctl, or set docker.runtime",
+		}
+	}
+
+	if len(found) > 1 && found[0] == types.RuntimeDocker && found[1] == types.RuntimeDockerCompose {
+		if err := exec.Command("docker", "compose", "version").Run(); err != nil {
+			return types.RuntimeDockerCompose, nil
+		}
+	}
+	return found[0], nil
+}
+
+func (c Compose) binary() string {
+	switch c.Runtime {
+	case types.RuntimeDockerCompose:
+		return "docker-compose"
+	case types.RuntimePodman:
+		return "podman"
+	case types.RuntimeNerdctl:
+		return "nerdctl"
+	default:
+		return "docker"
+	}
+}
+
+// Command returns the full command line running the compose subcommand args,
+// binary first
+func (c Compose) Command(args ...string) []string {
+	cmd := []string{c.binary()}
+	if c.Runtime != types.RuntimeDockerCompose {
+		cmd = append(cmd, "compose")
+	}
+	for _, f := range c.Files {
+		cmd = append(cmd, "-f", f)
+	}
+	return append(cmd, args...)
+}
+
+// ContainerCommand returns the command line running the container subcommand
+// args, e.g. run or volume, with the CLI of the runtime. The legacy
+// docker-compose has none of its own and uses docker.
+func (c Compose) ContainerCommand(args ...string) []string {
+	binary := c.binary()
+	if c.Runtime == types.RuntimeDockerCompose {
+		binary = "docker"
+	}
+	return append([]string{binary}, args...)
+}
@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// onlyOnPath replaces PATH with a directory holding scripts of the given
+// names, each running body
+func onlyOnPath(t *testing.T, body string, names ...string) {
+	t.Helper()
+	binDir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", binDir)
+}
+
+func TestCompose_Command(t *testing.T) {
+	files := []string{"compose.yaml", "compose.override.yaml"}
+
+	tests := []struct {
+		runtime  types.ComposeRuntime
+		expected []string
+	}{
+		{"", []string{"docker", "compose", "-f", "compose.yaml", "-f", "compose.override.yaml", "ps"}},
+		{types.RuntimeDocker, []string{"docker", "compose", "-f", "compose.yaml", "-f", "compose.override.yaml", "ps"}},
+		{types.RuntimeDockerCompose, []string{"docker-compose", "-f", "compose.yaml", "-f", "compose.override.yaml", "ps"}},
+		{types.RuntimePodman, []string{"podman", "compose", "-f", "compose.yaml", "-f", "compose.override.yaml", "ps"}},
+		{types.RuntimeNerdctl, []string{"nerdctl", "compose", "-f", "compose.yaml", "-f", "compose.override.yaml", "ps"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.runtime), func(t *testing.T) {
+			result := Compose{Runtime: tt.runtime, Files: files}.Command("ps")
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Command() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompose_ContainerCommand(t *testing.T) {
+	tests := []struct {
+		runtime  types.ComposeRuntime
+		expected []string
+	}{
+		{"", []string{"docker", "volume", "inspect", "app_db"}},
+		{types.RuntimeDockerCompose, []string{"docker", "volume", "inspect", "app_db"}},
+		{types.RuntimePodman, []string{"podman", "volume", "inspect", "app_db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.runtime), func(t *testing.T) {
+			result := Compose{Runtime: tt.runtime, Files: []string{"compose.yaml"}}.ContainerCommand("volume", "inspect", "app_db")
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ContainerCommand() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewCompose(t *testing.T) {
+	t.Run("auto prefers docker", func(t *testing.T) {
+		onlyOnPath(t, "exit 0", "docker", "podman")
+		compose, err := NewCompose(types.RuntimeAuto, nil)
+		if err != nil {
+			t.Fatalf("NewCompose() error = %v", err)
+		}
+		if compose.Runtime != types.RuntimeDocker {
+			t.Errorf("expected docker, got %s", compose.Runtime)
+		}
+	})
+
+	t.Run("auto falls back to legacy docker-compose without the plugin", func(t *testing.T) {
+		onlyOnPath(t, `[ "$1" = compose ] && exit 1; exit 0`, "docker", "docker-compose")
+		compose, err := NewCompose("", nil)
+		if err != nil {
+			t.Fatalf("NewCompose() error = %v", err)
+		}
+		if compose.Runtime != types.RuntimeDockerCompose {
+			t.Errorf("expected docker-compose, got %s", compose.Runtime)
+		}
+	})
+
+	t.Run("auto finds podman", func(t *testing.T) {
+		onlyOnPath(t, "exit 0", "podman", "nerdctl")
+		compose, err := NewCompose("", []string{"compose.yaml"})
+		if err != nil {
+			t.Fatalf("NewCompose() error = %v", err)
+		}
+		if compose.Runtime != types.RuntimePodman || !reflect.DeepEqual(compose.Files, []string{"compose.yaml"}) {
+			t.Errorf("unexpected compose %+v", compose)
+		}
+	})
+
+	t.Run("nothing installed", func(t *testing.T) {
+		onlyOnPath(t, "exit 0")
+		_, err := NewCompose("", nil)
+		cmdErr, ok := err.(*types.CommandError)
+		if !ok || cmdErr.Code != types.ErrDependenciesMissing {
+			t.Errorf("expected ErrDependenciesMissing, got %v", err)
+		}
+	})
+
+	t.Run("configured runtime missing", func(t *testing.T) {
+		onlyOnPath(t, "exit 0", "docker")
+		_, err := NewCompose(types.RuntimeNerdctl, nil)
+		cmdErr, ok := err.(*types.CommandError)
+		if !ok || cmdErr.Code != types.ErrDependenciesMissing {
+			t.Errorf("expected ErrDependenciesMissing, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// compressWriter returns a writer that compresses everything written to it into w.
+// Close must be called to flush the compressed stream.
+func compressWriter(w io.Writer, compression types.Compression) (io.WriteCloser, error) {
+	switch compression {
+	case types.CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case types.CompressionZstd:
+		cmd, err := zstdCommand("-q", "-c")
+		if err != nil {
+			return nil, err
+		}
+		var stderr bytes.Buffer
+		cmd.Stdout = w
+		cmd.Stderr = &stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to start zstd: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start zstd: %w", err)
+		}
+		return &processWriter{WriteCloser: stdin, cmd: cmd, stderr: &stderr}, nil
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+// decompressReader returns a reader yielding the decompressed contents of r
+func decompressReader(r io.Reader, compression types.Compression) (io.ReadCloser, error) {
+	switch compression {
+	case types.CompressionGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip dump: %w", err)
+		}
+		return gz, nil
+	case types.CompressionZstd:
+		cmd, err := zstdCommand("-d", "-q", "-c")
+		if err != nil {
+			return nil, err
+		}
+		var stderr bytes.Buffer
+		cmd.Stdin = r
+		cmd.Stderr = &stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to start zstd: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start zstd: %w", err)
+		}
+		return &processReader{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+// zstdCommand builds a zstd invocation. There is no zstd implementation in the
+// standard library, so the host binary is used.
+func zstdCommand(args ...string) (*exec.Cmd, error) {
+	path, err := exec.LookPath("zstd")
+	if err != nil {
+		return nil, &types.CommandError{
+			Code:    types.ErrDependenciesMissing,
+			Message: "zstd compression requires the zstd binary on PATH",
+		}
+	}
+	return exec.Command(path, args...), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// processWriter feeds a process's stdin and waits for it on Close
+type processWriter struct {
+	io.WriteCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+func (p *processWriter) Close() error {
+	closeErr := p.WriteCloser.Close()
+	if err := p.cmd.Wait(); err != nil {
+		return fmt.Errorf("%s failed: %w\nStderr: %s", p.cmd.Path, err, p.stderr.String())
+	}
+	return closeErr
+}
+
+// processReader reads a process's stdout and waits for it on Close
+type processReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	eof    bool
+}
+
+func (p *processReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if err == io.EOF {
+		p.eof = true
+	}
+	return n, err
+}
+
+func (p *processReader) Close() error {
+	if !p.eof {
+		// The consumer gave up early; the remaining output is not needed
+		p.cmd.Process.Kill()
+		p.cmd.Wait()
+		return nil
+	}
+	if err := p.cmd.Wait(); err != nil {
+		return fmt.Errorf("%s failed: %w\nStderr: %s", p.cmd.Path, err, p.stderr.String())
+	}
+	return nil
+}
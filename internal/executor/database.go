@@ -2,162 +2,829 @@ package executor
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/anonymize"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
 )
 
+// pipeWaitDelay bounds how long Wait keeps reading output of a killed process
+// and how long an interrupted docker client gets to exit before it is killed
+const pipeWaitDelay = 5 * time.Second
+
+// ErrCloneUnsupported is returned by Template and Copy when the engine has no
+// such server-side strategy
+var ErrCloneUnsupported = errors.New("clone strategy not supported by this database engine")
+
 type DatabaseExecutor interface {
-	Dump(service string, dsn *types.DSN, destPath string, tables []string) (*types.DumpResult, error)
-	Create(service string, dsn *types.DSN, dbName string) (*types.CreateResult, error)
-	Import(service string, dsn *types.DSN, sourcePath string, dbName string) (*types.ImportResult, error)
-	Drop(service string, dsn *types.DSN, dbName string) (*types.DropResult, error)
-	List(service string, dsn *types.DSN, defaultDB string) (*types.DatabaseListResult, error)
+	Dump(ctx context.Context, service string, dsn *types.DSN, destPath string, filter types.TableFilter) (*types.DumpResult, error)
+	Create(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.CreateResult, error)
+	Import(ctx context.Context, service string, dsn *types.DSN, sourcePath string, dbName string, tables []string) (*types.ImportResult, error)
+	Drop(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DropResult, error)
+	List(ctx context.Context, service string, dsn *types.DSN, defaultDB string) (*types.DatabaseListResult, error)
+	Pipe(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string, filter types.TableFilter) (int64, error)
+	ServerVersion(ctx context.Context, service string, dsn *types.DSN) (string, error)
+	Tables(ctx context.Context, service string, dsn *types.DSN, dbName string) ([]string, error)
+	Stats(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DatabaseStatsResult, error)
+	Schema(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DatabaseSchema, error)
+	Query(ctx context.Context, service string, dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) (*types.QueryResult, error)
+	Swap(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) error
+	Template(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) error
+	Copy(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) (int64, error)
+	SetProgressFunc(fn types.ProgressFunc)
+	SetAnonymizer(a *anonymize.Anonymizer)
 }
 
 type DockerDatabaseExecutor struct {
-	engine       engines.DatabaseEngine
-	composeFiles []string
-	projectRoot  string
+	engine      engines.DatabaseEngine
+	compose     Compose
+	projectRoot string
+	progress    types.ProgressFunc
+	anonymizer  *anonymize.Anonymizer
+	// local runs the engine's commands on the host instead of in the service
+	local bool
 }
 
-func NewDockerDatabaseExecutor(engine engines.DatabaseEngine, composeFiles []string, projectRoot string) *DockerDatabaseExecutor {
+func NewDockerDatabaseExecutor(engine engines.DatabaseEngine, compose Compose, projectRoot string) *DockerDatabaseExecutor {
 	return &DockerDatabaseExecutor{
-		engine:       engine,
-		composeFiles: composeFiles,
-		projectRoot:  projectRoot,
+		engine:      engine,
+		compose:     compose,
+		projectRoot: projectRoot,
+	}
+}
+
+// SetProgressFunc registers a callback that receives the number of bytes
+// transferred while dumping and importing
+func (d *DockerDatabaseExecutor) SetProgressFunc(fn types.ProgressFunc) {
+	d.progress = fn
+}
+
+// SetAnonymizer makes Dump and Pipe rewrite the dump stream with the given
+// anonymizer before it reaches the file or the target database
+func (d *DockerDatabaseExecutor) SetAnonymizer(a *anonymize.Anonymizer) {
+	d.anonymizer = a
+}
+
+// command builds the compose exec invocation of cmd inside service, or runs
+// cmd directly for a local executor. The credentials of dsn are passed as
+// -e NAME with the value set in the compose client's environment, so they
+// appear in no argv on either side. Local clients also get the DSN port.
+// Cancelling ctx interrupts the compose client, which ends the exec session:
+// the daemon closes the streams of the process in the container, so a dump
+// fails on its next write and an import reads EOF.
+func (d *DockerDatabaseExecutor) command(ctx context.Context, service string, dsn *types.DSN, cmd []string) *exec.Cmd {
+	env := d.engine.Env(dsn)
+
+	var execCmd *exec.Cmd
+	if d.local {
+		env = append(env, d.engine.PortEnv(dsn)...)
+		execCmd = exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+	} else {
+		args := []string{"exec", "-T"}
+		for _, kv := range env {
+			name, _, _ := strings.Cut(kv, "=")
+			args = append(args, "-e", name)
+		}
+		args = append(args, service)
+		cmdline := d.compose.Command(append(args, cmd...)...)
+		execCmd = exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	}
+	execCmd.Dir = d.projectRoot
+	if len(env) > 0 {
+		execCmd.Env = append(os.Environ(), env...)
+	}
+	execCmd.Cancel = func() error {
+		return execCmd.Process.Signal(os.Interrupt)
 	}
+	// Don't wait forever for a client that ignores the interrupt, or for output
+	// pipes held open by its children
+	execCmd.WaitDelay = pipeWaitDelay
+	return execCmd
 }
 
-func (d *DockerDatabaseExecutor) buildComposeArgs(subcmd ...string) []string {
-	var args []string
-	args = append(args, "compose")
-	for _, f := range d.composeFiles {
-		args = append(args, "-f", f)
+// interrupted returns the error of ctx once it is done, wrapped for op. A
+// process killed because of the cancellation fails with a meaningless signal
+// error, so callers report this instead.
+func interrupted(ctx context.Context, op string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s interrupted: %w", op, err)
 	}
-	args = append(args, subcmd...)
-	return args
+	return nil
 }
 
-func (d *DockerDatabaseExecutor) Dump(service string, dsn *types.DSN, destPath string, tables []string) (*types.DumpResult, error) {
+// Dump streams the dump output straight into a temporary file next to
+// destPath and renames it into place once the dump has succeeded, so the
+// database is never held in memory and a failed dump leaves no partial file.
+// The output is anonymized if an anonymizer is set, then compressed according
+// to the extension of destPath, and the returned checksum covers the file
+// exactly as written. Output without the footer of the engine's dump tool is
+// rejected as incomplete.
+func (d *DockerDatabaseExecutor) Dump(ctx context.Context, service string, dsn *types.DSN, destPath string, filter types.TableFilter) (*types.DumpResult, error) {
 	start := time.Now()
 
-	cmd := d.engine.BuildDumpCommand(dsn, tables)
-	args := append(d.buildComposeArgs("exec", "-T", service), cmd...)
+	commands, err := d.dumpCommands(ctx, service, dsn, filter)
+	if err != nil {
+		return nil, err
+	}
 
-	execCmd := exec.Command("docker", args...)
-	execCmd.Dir = d.projectRoot
-	// Only capture stdout - mysqldump warnings go to stderr and would corrupt the SQL
-	output, err := execCmd.Output()
+	_, compression, _ := types.ParseDumpFilename(filepath.Base(destPath))
+
+	file, err := os.CreateTemp(filepath.Dir(destPath), filepath.Base(destPath)+".*.tmp")
 	if err != nil {
-		stderr := ""
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr = string(exitErr.Stderr)
+		return nil, fmt.Errorf("failed to create dump file: %w", err)
+	}
+	tmpPath := file.Name()
+
+	hasher := sha256.New()
+	compressor, err := compressWriter(io.MultiWriter(file, hasher), compression)
+	if err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	progress := newProgressCounter(types.StageDumping, d.progress)
+
+	// Only stdout goes to the file - mysqldump warnings go to stderr and would corrupt the SQL
+	var stderr bytes.Buffer
+	var anonymizer *anonymize.Writer
+	edges := &edgeWriter{}
+	var stdout io.Writer = progress.Writer(io.MultiWriter(compressor, edges))
+	if d.anonymizer != nil {
+		anonymizer = d.anonymizer.NewWriter(stdout, d.engine.Dialect())
+		stdout = anonymizer
+	}
+
+	// Multiple commands (e.g. a --no-data pass for structure-only tables) append to the same file
+	for _, cmd := range commands {
+		execCmd := d.command(ctx, service, dsn, cmd)
+		execCmd.Stdout = stdout
+		execCmd.Stderr = &stderr
+
+		if err := execCmd.Run(); err != nil {
+			compressor.Close()
+			file.Close()
+			os.Remove(tmpPath)
+			if err := interrupted(ctx, "dump"); err != nil {
+				return nil, err
+			}
+			// A rejected row kills the dump with a broken pipe; report the real cause
+			if anonymizer != nil && anonymizer.Err() != nil {
+				return nil, fmt.Errorf("anonymize failed: %w", anonymizer.Err())
+			}
+			return nil, fmt.Errorf("dump failed: %w\nStderr: %s", err, stderr.String())
 		}
-		return nil, fmt.Errorf("dump failed: %w\nStderr: %s", err, stderr)
+	}
+	if anonymizer != nil {
+		if err := anonymizer.Close(); err != nil {
+			compressor.Close()
+			file.Close()
+			os.Remove(tmpPath)
+			return nil, fmt.Errorf("anonymize failed: %w", err)
+		}
+	}
+	progress.finish()
+
+	// A dump tool killed from outside may still exit cleanly, e.g. behind a
+	// wrapper; only the footer proves the output is whole
+	if markers := d.engine.DumpMarkers(); markers.Footer != "" && !markers.Complete(edges.tail) {
+		compressor.Close()
+		file.Close()
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("dump incomplete: the output does not end with %q\nStderr: %s", markers.Footer, stderr.String())
 	}
 
-	if err := os.WriteFile(destPath, output, 0644); err != nil {
+	if err := compressor.Close(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to compress dump file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
 		return nil, fmt.Errorf("failed to write dump file: %w", err)
 	}
 
-	stat, _ := os.Stat(destPath)
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to write dump file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to move dump file into place: %w", err)
+	}
+
+	stat, err := os.Stat(destPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat dump file: %w", err)
+	}
 
 	return &types.DumpResult{
 		Path:     destPath,
 		Size:     stat.Size(),
 		Database: dsn.Database,
+		Checksum: "sha256:" + hex.EncodeToString(hasher.Sum(nil)),
 		Duration: time.Since(start),
 	}, nil
 }
 
-func (d *DockerDatabaseExecutor) Create(service string, dsn *types.DSN, dbName string) (*types.CreateResult, error) {
-	cmd := d.engine.BuildCreateCommand(dsn, dbName)
-	args := append(d.buildComposeArgs("exec", "-T", service), cmd...)
-
-	execCmd := exec.Command("docker", args...)
-	execCmd.Dir = d.projectRoot
+func (d *DockerDatabaseExecutor) Create(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.CreateResult, error) {
+	execCmd := d.command(ctx, service, dsn, d.engine.BuildCreateCommand(dsn, dbName))
 	output, err := execCmd.CombinedOutput()
 	if err != nil {
+		if err := interrupted(ctx, "create database"); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("create database failed: %w\nOutput: %s", err, string(output))
 	}
 
 	return &types.CreateResult{Database: dbName}, nil
 }
 
-func (d *DockerDatabaseExecutor) Import(service string, dsn *types.DSN, sourcePath string, dbName string) (*types.ImportResult, error) {
+// Import feeds the SQL file at sourcePath into dbName, decompressing it
+// according to its extension. With tables set only the statements of the
+// matching tables reach the client, see sqldump.TableFilter; an import in
+// which no table matched fails.
+func (d *DockerDatabaseExecutor) Import(ctx context.Context, service string, dsn *types.DSN, sourcePath string, dbName string, tables []string) (*types.ImportResult, error) {
 	start := time.Now()
 
-	sqlData, err := os.ReadFile(sourcePath)
+	file, err := os.Open(sourcePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read SQL file: %w", err)
 	}
+	defer file.Close()
 
-	cmd := d.engine.BuildImportCommand(dsn, dbName)
-	args := append(d.buildComposeArgs("exec", "-T", service), cmd...)
+	_, compression, _ := types.ParseDumpFilename(filepath.Base(sourcePath))
+	sqlReader, err := decompressReader(file, compression)
+	if err != nil {
+		return nil, err
+	}
 
-	execCmd := exec.Command("docker", args...)
-	execCmd.Dir = d.projectRoot
-	execCmd.Stdin = bytes.NewReader(sqlData)
+	progress := newProgressCounter(types.StageImporting, d.progress)
+	sqlStream := progress.Reader(sqlReader)
+
+	var tableFilter *sqldump.TableFilter
+	var filtered *rewrittenStream
+	if len(tables) > 0 {
+		sqlStream, filtered = rewriteStream(sqlStream, func(w io.Writer) streamWriter {
+			tableFilter = sqldump.NewTableFilter(w, d.engine.Dialect(), tables)
+			return tableFilter
+		})
+	}
+
+	execCmd := d.command(ctx, service, dsn, d.engine.BuildImportCommand(dsn, dbName))
+	execCmd.Stdin = sqlStream
 	output, err := execCmd.CombinedOutput()
-	if err != nil {
+	filterErr := filtered.Err()
+	filtered.stop()
+	if err != nil || filterErr != nil {
+		sqlReader.Close()
+		if err := interrupted(ctx, "import"); err != nil {
+			return nil, err
+		}
+		if filterErr != nil {
+			return nil, fmt.Errorf("failed to filter SQL file: %w", filterErr)
+		}
 		return nil, fmt.Errorf("import failed: %w\nOutput: %s", err, string(output))
 	}
+	if err := sqlReader.Close(); err != nil {
+		return nil, fmt.Errorf("failed to decompress SQL file: %w", err)
+	}
+	progress.finish()
 
-	return &types.ImportResult{
+	result := &types.ImportResult{
 		Path:     sourcePath,
 		Database: dbName,
 		Duration: time.Since(start),
-	}, nil
+	}
+	if tableFilter != nil {
+		result.Tables = tableFilter.Matched()
+		if len(result.Tables) == 0 {
+			return nil, fmt.Errorf("no tables in %s match %s", filepath.Base(sourcePath), strings.Join(tables, ", "))
+		}
+	}
+	return result, nil
 }
 
-func (d *DockerDatabaseExecutor) Drop(service string, dsn *types.DSN, dbName string) (*types.DropResult, error) {
-	cmd := d.engine.BuildDropCommand(dsn, dbName)
-	args := append(d.buildComposeArgs("exec", "-T", service), cmd...)
+// Pipe copies sourceDB into the existing targetDB by feeding the output of the
+// dump commands directly into the import command, without a temporary file.
+// The dump is anonymized on the way if an anonymizer is set. It returns the
+// number of bytes moved between the two processes.
+func (d *DockerDatabaseExecutor) Pipe(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string, filter types.TableFilter) (int64, error) {
+	sourceDSN := *dsn
+	sourceDSN.Database = sourceDB
 
-	execCmd := exec.Command("docker", args...)
-	execCmd.Dir = d.projectRoot
+	commands, err := d.dumpCommands(ctx, service, &sourceDSN, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	// All dump commands start together; the import reads their outputs one after another
+	dumpCmds := make([]*exec.Cmd, len(commands))
+	dumpStderr := make([]bytes.Buffer, len(commands))
+	dumpOuts := make([]io.Reader, len(commands))
+	for i, cmd := range commands {
+		dumpCmd := d.command(ctx, service, &sourceDSN, cmd)
+		dumpCmd.Stderr = &dumpStderr[i]
+		dumpOut, err := dumpCmd.StdoutPipe()
+		if err != nil {
+			return 0, fmt.Errorf("failed to connect dump output: %w", err)
+		}
+		dumpCmds[i] = dumpCmd
+		dumpOuts[i] = dumpOut
+	}
+
+	killDumps := func() {
+		for _, dumpCmd := range dumpCmds {
+			if dumpCmd.Process != nil {
+				dumpCmd.Process.Kill()
+			}
+		}
+	}
+	waitDumps := func() error {
+		var firstErr error
+		for i, dumpCmd := range dumpCmds {
+			if dumpCmd.Process == nil {
+				continue
+			}
+			if err := dumpCmd.Wait(); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("dump failed: %w\nStderr: %s", err, dumpStderr[i].String())
+			}
+		}
+		return firstErr
+	}
+
+	progress := newProgressCounter(types.StageCloning, d.progress)
+	dumpStream, anonymizer := d.anonymizeStream(io.MultiReader(dumpOuts...))
+
+	importCmd := d.command(ctx, service, dsn, d.engine.BuildImportCommand(dsn, targetDB))
+	var importOutput bytes.Buffer
+	importCmd.Stdin = progress.Reader(dumpStream)
+	importCmd.Stdout = &importOutput
+	importCmd.Stderr = &importOutput
+
+	for _, dumpCmd := range dumpCmds {
+		if err := dumpCmd.Start(); err != nil {
+			killDumps()
+			anonymizer.stop()
+			waitDumps()
+			return 0, fmt.Errorf("dump failed: %w", err)
+		}
+	}
+
+	if err := importCmd.Start(); err != nil {
+		killDumps()
+		anonymizer.stop()
+		waitDumps()
+		return 0, fmt.Errorf("import failed: %w", err)
+	}
+
+	importErr := importCmd.Wait()
+	anonymizeErr := anonymizer.Err()
+	if importErr != nil || anonymizeErr != nil {
+		// Nobody reads the dump output anymore, so stop the dump before it blocks
+		killDumps()
+	}
+	anonymizer.stop()
+	dumpErr := waitDumps()
+
+	if err := interrupted(ctx, "clone"); err != nil {
+		return progress.n, err
+	}
+	if anonymizeErr != nil {
+		return progress.n, fmt.Errorf("anonymize failed: %w", anonymizeErr)
+	}
+	if importErr != nil {
+		return progress.n, fmt.Errorf("import failed: %w\nOutput: %s", importErr, importOutput.String())
+	}
+	if dumpErr != nil {
+		return progress.n, dumpErr
+	}
+	progress.finish()
+
+	return progress.n, nil
+}
+
+// anonymizeStream returns r rewritten by the anonymizer in a background
+// goroutine, or r itself and a nil stream when no anonymizer is set
+func (d *DockerDatabaseExecutor) anonymizeStream(r io.Reader) (io.Reader, *rewrittenStream) {
+	if d.anonymizer == nil {
+		return r, nil
+	}
+	return rewriteStream(r, func(w io.Writer) streamWriter {
+		return d.anonymizer.NewWriter(w, d.engine.Dialect())
+	})
+}
+
+// streamWriter rewrites a SQL dump written to it line by line
+type streamWriter interface {
+	io.WriteCloser
+	Err() error
+}
+
+// rewriteStream returns r rewritten in a background goroutine by the writer
+// newWriter wraps around the returned reader
+func rewriteStream(r io.Reader, newWriter func(io.Writer) streamWriter) (io.Reader, *rewrittenStream) {
+	pr, pw := io.Pipe()
+	s := &rewrittenStream{reader: pr, failed: make(chan error, 1), done: make(chan struct{})}
+	w := newWriter(pw)
+	go func() {
+		defer close(s.done)
+		io.Copy(w, r)
+		if err := w.Close(); err != nil {
+			// Record the failure before the reader can observe the broken stream
+			s.failed <- err
+		}
+		pw.CloseWithError(w.Err())
+	}()
+
+	return pr, s
+}
+
+type rewrittenStream struct {
+	reader *io.PipeReader
+	failed chan error
+	done   chan struct{}
+}
+
+// Err returns the error if the rewrite has already failed on its own
+func (s *rewrittenStream) Err() error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case err := <-s.failed:
+		s.failed <- err
+		return err
+	default:
+		return nil
+	}
+}
+
+// stop interrupts the rewrite and waits for the goroutine to exit
+func (s *rewrittenStream) stop() {
+	if s == nil {
+		return
+	}
+	s.reader.Close()
+	<-s.done
+}
+
+func (d *DockerDatabaseExecutor) Drop(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DropResult, error) {
+	execCmd := d.command(ctx, service, dsn, d.engine.BuildDropCommand(dsn, dbName))
 	output, err := execCmd.CombinedOutput()
 	if err != nil {
+		if err := interrupted(ctx, "drop database"); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("drop database failed: %w\nOutput: %s", err, string(output))
 	}
 
 	return &types.DropResult{Database: dbName}, nil
 }
 
-func (d *DockerDatabaseExecutor) List(service string, dsn *types.DSN, defaultDB string) (*types.DatabaseListResult, error) {
-	cmd := d.engine.BuildListCommand(dsn)
-	args := append(d.buildComposeArgs("exec", "-T", service), cmd...)
+func (d *DockerDatabaseExecutor) List(ctx context.Context, service string, dsn *types.DSN, defaultDB string) (*types.DatabaseListResult, error) {
+	output, err := d.query(ctx, service, dsn, d.engine.BuildListCommand(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("list databases failed: %w", err)
+	}
 
-	execCmd := exec.Command("docker", args...)
-	execCmd.Dir = d.projectRoot
+	return parseDatabaseList(output, defaultDB, d.engine.SystemDatabases())
+}
+
+// ServerVersion returns the version string reported by the database server
+func (d *DockerDatabaseExecutor) ServerVersion(ctx context.Context, service string, dsn *types.DSN) (string, error) {
+	output, err := d.query(ctx, service, dsn, d.engine.BuildVersionCommand(dsn))
+	if err != nil {
+		return "", fmt.Errorf("server version query failed: %w", err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// Tables returns the names of the tables in dbName
+func (d *DockerDatabaseExecutor) Tables(ctx context.Context, service string, dsn *types.DSN, dbName string) ([]string, error) {
+	output, err := d.query(ctx, service, dsn, d.engine.BuildListTablesCommand(dsn, dbName))
+	if err != nil {
+		return nil, fmt.Errorf("list tables failed: %w", err)
+	}
+
+	return lines(output), nil
+}
+
+// Stats returns the sizes, row estimates and last changes of the tables of
+// dbName, largest first
+func (d *DockerDatabaseExecutor) Stats(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DatabaseStatsResult, error) {
+	output, err := d.query(ctx, service, dsn, d.engine.BuildTableStatsCommand(dsn, dbName, nil))
+	if err != nil {
+		return nil, fmt.Errorf("table statistics query failed: %w", err)
+	}
+
+	tables, err := parseTableStats(output)
+	if err != nil {
+		return nil, err
+	}
+	return newStatsResult(dbName, tables), nil
+}
+
+// parseTableStats reads the output of the engine's table statistics query
+func parseTableStats(output string) ([]types.TableStats, error) {
+	var tables []types.TableStats
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected table statistics line: %q", line)
+		}
+
+		var numbers [4]int64
+		for i, field := range fields[1:] {
+			// Some servers print estimates as decimals
+			whole, _, _ := strings.Cut(strings.TrimSpace(field), ".")
+			n, err := strconv.ParseInt(whole, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected table statistics line: %q", line)
+			}
+			numbers[i] = max(n, 0)
+		}
+
+		table := types.TableStats{
+			Name:      fields[0],
+			Rows:      numbers[0],
+			DataSize:  numbers[1],
+			IndexSize: numbers[2],
+		}
+		if numbers[3] > 0 {
+			table.Modified = time.Unix(numbers[3], 0).UTC().Format(time.RFC3339)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// newStatsResult sorts tables by size, largest first, and sums them up
+func newStatsResult(dbName string, tables []types.TableStats) *types.DatabaseStatsResult {
+	size := func(t types.TableStats) int64 { return t.DataSize + t.IndexSize }
+	sort.SliceStable(tables, func(i, j int) bool {
+		return size(tables[i]) > size(tables[j])
+	})
+
+	result := &types.DatabaseStatsResult{
+		Database: dbName,
+		Stats:    types.DatabaseStats{TableCount: len(tables)},
+		Tables:   tables,
+	}
+	if result.Tables == nil {
+		result.Tables = []types.TableStats{}
+	}
+	for _, table := range tables {
+		result.Stats.Size += size(table)
+		result.Stats.Rows += table.Rows
+		// RFC 3339 times in UTC sort as strings
+		if table.Modified > result.Stats.Modified {
+			result.Stats.Modified = table.Modified
+		}
+	}
+	return result
+}
+
+// Schema reads the tables, columns, indexes and foreign keys of dbName from
+// the catalog
+func (d *DockerDatabaseExecutor) Schema(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DatabaseSchema, error) {
+	output, err := d.query(ctx, service, dsn, d.engine.BuildSchemaCommand(dsn, dbName))
+	if err != nil {
+		return nil, fmt.Errorf("schema query failed: %w", err)
+	}
+
+	return parseSchema(output)
+}
+
+// parseSchema reads the output of the engine's schema query. Tables are
+// sorted by name, their columns keep the order of the output.
+func parseSchema(output string) (*types.DatabaseSchema, error) {
+	tables := make(map[string]*types.TableSchema)
+	table := func(name string) *types.TableSchema {
+		if tables[name] == nil {
+			tables[name] = &types.TableSchema{Name: name, Columns: []types.ColumnSchema{}}
+		}
+		return tables[name]
+	}
+	list := func(field string) []string {
+		if field == "" {
+			return nil
+		}
+		return strings.Split(field, ",")
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		f := strings.Split(line, "\t")
+		if len(f) != 8 {
+			return nil, fmt.Errorf("unexpected schema line: %q", line)
+		}
+
+		switch f[0] {
+		case "column":
+			column := types.ColumnSchema{Name: f[2], Type: f[3], Nullable: f[4] == "YES", Extra: f[7]}
+			if f[5] == "1" {
+				column.Default = &f[6]
+			}
+			t := table(f[1])
+			t.Columns = append(t.Columns, column)
+		case "index":
+			t := table(f[1])
+			t.Indexes = append(t.Indexes, types.IndexSchema{
+				Name:       f[2],
+				Primary:    f[3] == "primary",
+				Unique:     f[3] == "primary" || f[3] == "unique",
+				Columns:    list(f[4]),
+				Method:     f[5],
+				Constraint: f[6] == "constraint",
+			})
+		case "foreign_key":
+			t := table(f[1])
+			t.ForeignKeys = append(t.ForeignKeys, types.ForeignKeySchema{
+				Name:              f[2],
+				Columns:           list(f[3]),
+				ReferencedTable:   f[4],
+				ReferencedColumns: list(f[5]),
+				OnDelete:          f[6],
+				OnUpdate:          f[7],
+			})
+		default:
+			return nil, fmt.Errorf("unexpected schema line: %q", line)
+		}
+	}
+
+	schema := &types.DatabaseSchema{Tables: make([]types.TableSchema, 0, len(tables))}
+	for _, t := range tables {
+		schema.Tables = append(schema.Tables, *t)
+	}
+	sort.Slice(schema.Tables, func(i, j int) bool {
+		return schema.Tables[i].Name < schema.Tables[j].Name
+	})
+	return schema, nil
+}
+
+// lines returns the non-empty lines of output
+func lines(output string) []string {
+	var result []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// Swap replaces the contents of targetDB with those of sourceDB and removes sourceDB.
+// If the swap fails, targetDB is left untouched. Engines that swap by moving
+// tables refuse databases with views or triggers, which can't be moved to
+// another database.
+func (d *DockerDatabaseExecutor) Swap(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) error {
+	if d.engine.BuildCopyCheckCommand(dsn, sourceDB) != nil {
+		var blockers []string
+		for _, dbName := range []string{sourceDB, targetDB} {
+			output, err := d.query(ctx, service, dsn, d.engine.BuildCopyCheckCommand(dsn, dbName))
+			if err != nil {
+				return fmt.Errorf("swap check failed: %w", err)
+			}
+			for _, object := range lines(output) {
+				blockers = append(blockers, object+" in "+dbName)
+			}
+		}
+		if len(blockers) > 0 {
+			return fmt.Errorf("swap databases can't move %s", strings.Join(blockers, ", "))
+		}
+	}
+
+	sourceTables, err := d.Tables(ctx, service, dsn, sourceDB)
+	if err != nil {
+		return err
+	}
+	targetTables, err := d.Tables(ctx, service, dsn, targetDB)
+	if err != nil {
+		return err
+	}
+
+	execCmd := d.command(ctx, service, dsn, d.engine.BuildSwapCommand(dsn, sourceDB, targetDB, sourceTables, targetTables))
+	output, err := execCmd.CombinedOutput()
+	if err != nil {
+		if err := interrupted(ctx, "swap databases"); err != nil {
+			return err
+		}
+		return fmt.Errorf("swap databases failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// Template creates targetDB as a copy of sourceDB made by the server itself,
+// without moving any data through the client
+func (d *DockerDatabaseExecutor) Template(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) error {
+	cmd := d.engine.BuildTemplateCommand(dsn, sourceDB, targetDB)
+	if cmd == nil {
+		return ErrCloneUnsupported
+	}
+
+	execCmd := d.command(ctx, service, dsn, cmd)
+	output, err := execCmd.CombinedOutput()
+	if err != nil {
+		if err := interrupted(ctx, "template clone"); err != nil {
+			return err
+		}
+		return fmt.Errorf("template clone failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// Copy copies the tables of sourceDB into the existing, empty targetDB on the
+// server. It fails before touching targetDB if sourceDB holds objects the copy
+// can't reproduce. Nothing passes through the client, so it returns 0 bytes.
+func (d *DockerDatabaseExecutor) Copy(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) (int64, error) {
+	check := d.engine.BuildCopyCheckCommand(dsn, sourceDB)
+	if check == nil {
+		return 0, ErrCloneUnsupported
+	}
+
+	output, err := d.query(ctx, service, dsn, check)
+	if err != nil {
+		return 0, fmt.Errorf("table copy check failed: %w", err)
+	}
+	if blockers := lines(output); len(blockers) > 0 {
+		return 0, fmt.Errorf("table copy can't reproduce %s", strings.Join(blockers, ", "))
+	}
+
+	output, err = d.query(ctx, service, dsn, d.engine.BuildForeignKeysCommand(dsn, sourceDB, targetDB))
+	if err != nil {
+		return 0, fmt.Errorf("foreign key query failed: %w", err)
+	}
+	foreignKeys := lines(output)
+
+	tables, err := d.Tables(ctx, service, dsn, sourceDB)
+	if err != nil {
+		return 0, err
+	}
+
+	execCmd := d.command(ctx, service, dsn, d.engine.BuildCopyCommand(dsn, sourceDB, targetDB, tables, foreignKeys))
+	if output, err := execCmd.CombinedOutput(); err != nil {
+		if err := interrupted(ctx, "table copy"); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("table copy failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return 0, nil
+}
+
+// query runs cmd inside the service container and returns its stdout
+func (d *DockerDatabaseExecutor) query(ctx context.Context, service string, dsn *types.DSN, cmd []string) (string, error) {
+	execCmd := d.command(ctx, service, dsn, cmd)
 	// Only capture stdout - mysql warnings go to stderr
 	output, err := execCmd.Output()
 	if err != nil {
+		if err := interrupted(ctx, "query"); err != nil {
+			return "", err
+		}
 		stderr := ""
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			stderr = string(exitErr.Stderr)
 		}
-		return nil, fmt.Errorf("list databases failed: %w\nStderr: %s", err, stderr)
+		return "", fmt.Errorf("%w\nStderr: %s", err, stderr)
 	}
 
-	return parseDatabaseList(string(output), defaultDB)
+	return string(output), nil
 }
 
-func parseDatabaseList(output, defaultDB string) (*types.DatabaseListResult, error) {
+func parseDatabaseList(output, defaultDB string, systemDatabases []string) (*types.DatabaseListResult, error) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
 	var databases []types.DatabaseInfo
 
-	systemDBs := map[string]bool{
-		"information_schema": true,
-		"mysql":              true,
-		"performance_schema": true,
-		"sys":                true,
+	systemDBs := make(map[string]bool, len(systemDatabases))
+	for _, name := range systemDatabases {
+		systemDBs[name] = true
 	}
 
 	for _, line := range lines {
@@ -0,0 +1,815 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/anonymize"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+func TestParseDatabaseList(t *testing.T) {
+	t.Run("mysql filters system databases", func(t *testing.T) {
+		output := "Database\ninformation_schema\napp\napp_wt_feature\nmysql\nperformance_schema\nsys\n"
+
+		result, err := parseDatabaseList(output, "app", engines.NewMySQLEngine(false).SystemDatabases())
+		if err != nil {
+			t.Fatalf("parseDatabaseList() error = %v", err)
+		}
+
+		if len(result.Databases) != 2 {
+			t.Fatalf("expected 2 databases, got %d: %v", len(result.Databases), result.Databases)
+		}
+
+		if result.Databases[0].Name != "app" || !result.Databases[0].IsDefault {
+			t.Errorf("expected default database app, got %+v", result.Databases[0])
+		}
+
+		if result.Databases[1].Name != "app_wt_feature" || result.Databases[1].IsDefault {
+			t.Errorf("expected non-default database app_wt_feature, got %+v", result.Databases[1])
+		}
+	})
+
+	t.Run("postgres filters template and maintenance databases", func(t *testing.T) {
+		output := "app\npostgres\ntemplate0\ntemplate1\n"
+
+		result, err := parseDatabaseList(output, "app", engines.NewPostgresEngine().SystemDatabases())
+		if err != nil {
+			t.Fatalf("parseDatabaseList() error = %v", err)
+		}
+
+		if len(result.Databases) != 1 {
+			t.Fatalf("expected 1 database, got %d: %v", len(result.Databases), result.Databases)
+		}
+
+		if result.Databases[0].Name != "app" {
+			t.Errorf("expected app, got %s", result.Databases[0].Name)
+		}
+	})
+}
+
+func TestProgressCounter(t *testing.T) {
+	var reports []string
+	counter := newProgressCounter(types.StageDumping, func(stage types.ProgressStage, detail string) {
+		if stage != types.StageDumping {
+			t.Errorf("expected stage %s, got %s", types.StageDumping, stage)
+		}
+		reports = append(reports, detail)
+	})
+
+	var buf bytes.Buffer
+	w := counter.Writer(&buf)
+	chunk := make([]byte, progressInterval/2)
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	counter.finish()
+
+	if counter.n != int64(3*len(chunk)) {
+		t.Errorf("expected %d bytes counted, got %d", 3*len(chunk), counter.n)
+	}
+
+	expected := []string{
+		fmt.Sprintf("%d bytes", progressInterval),
+		fmt.Sprintf("%d bytes", 3*len(chunk)),
+	}
+	if len(reports) != len(expected) {
+		t.Fatalf("expected %d reports, got %d: %v", len(expected), len(reports), reports)
+	}
+	for i, r := range reports {
+		if r != expected[i] {
+			t.Errorf("report[%d] = %q, want %q", i, r, expected[i])
+		}
+	}
+}
+
+// fakeDocker puts a "docker" script on PATH that runs the given shell body
+func fakeDocker(t *testing.T, body string) {
+	t.Helper()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestDockerDatabaseExecutor_DumpStreamsToFile(t *testing.T) {
+	fakeDocker(t, `echo "CREATE TABLE t (id int);"; echo "-- Dump completed"; echo "warning" >&2`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	result, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{})
+	if err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "CREATE TABLE t (id int);\n-- Dump completed\n" {
+		t.Errorf("unexpected dump content %q", string(data))
+	}
+	if result.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), result.Size)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the dump file in %s, got %d entries", dir, len(entries))
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpFailureLeavesNoFile(t *testing.T) {
+	fakeDocker(t, `echo "partial"; echo "boom" >&2; exit 2`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	if _, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{}); err == nil {
+		t.Fatal("expected dump to fail")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected no files after failed dump, got %d entries", len(entries))
+	}
+}
+
+func TestDockerDatabaseExecutor_ImportStreamsFromFile(t *testing.T) {
+	dir := t.TempDir()
+	received := filepath.Join(dir, "received.sql")
+	fakeDocker(t, `cat > "`+received+`"`)
+
+	sourcePath := filepath.Join(dir, "app.sql")
+	if err := os.WriteFile(sourcePath, []byte("INSERT INTO t VALUES (1);\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	var reported string
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	dbExecutor.SetProgressFunc(func(stage types.ProgressStage, detail string) {
+		reported = detail
+	})
+
+	if _, err := dbExecutor.Import(context.Background(), "database", dsn, sourcePath, "app_copy", nil); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "INSERT INTO t VALUES (1);\n" {
+		t.Errorf("unexpected imported content %q", string(data))
+	}
+	if reported != "26 bytes" {
+		t.Errorf("expected final progress report of 26 bytes, got %q", reported)
+	}
+}
+
+func TestDockerDatabaseExecutor_ImportFiltersTables(t *testing.T) {
+	dir := t.TempDir()
+	received := filepath.Join(dir, "received.sql")
+	fakeDocker(t, `cat > "`+received+`"`)
+
+	sourcePath := filepath.Join(dir, "app.sql")
+	dump := "SET NAMES utf8mb4;\n" +
+		"DROP TABLE IF EXISTS `category`;\n" +
+		"INSERT INTO `category` VALUES (1);\n" +
+		"DROP TABLE IF EXISTS `product`;\n" +
+		"INSERT INTO `product` VALUES (1);\n"
+	if err := os.WriteFile(sourcePath, []byte(dump), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	result, err := dbExecutor.Import(context.Background(), "database", dsn, sourcePath, "app", []string{"prod*"})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if len(result.Tables) != 1 || result.Tables[0] != "product" {
+		t.Errorf("Tables = %v, want [product]", result.Tables)
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SET NAMES utf8mb4;\nDROP TABLE IF EXISTS `product`;\nINSERT INTO `product` VALUES (1);\n"
+	if string(data) != want {
+		t.Errorf("imported %q, want %q", data, want)
+	}
+
+	if _, err := dbExecutor.Import(context.Background(), "database", dsn, sourcePath, "app", []string{"missing"}); err == nil || !strings.Contains(err.Error(), "no tables") {
+		t.Errorf("expected an error when no table matches, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_Pipe(t *testing.T) {
+	dir := t.TempDir()
+	received := filepath.Join(dir, "received.sql")
+	fakeDocker(t, `case "$*" in
+  *mysqldump*) echo "CREATE TABLE t (id int);" ;;
+  *) cat > "`+received+`" ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	size, err := dbExecutor.Pipe(context.Background(), "database", dsn, "app", "app_copy", types.TableFilter{})
+	if err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "CREATE TABLE t (id int);\n" {
+		t.Errorf("unexpected piped content %q", string(data))
+	}
+	if size != int64(len(data)) {
+		t.Errorf("expected %d bytes moved, got %d", len(data), size)
+	}
+}
+
+func TestDockerDatabaseExecutor_PipeImportFailure(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *mysqldump*) exec yes "INSERT INTO t VALUES (1);" ;;
+  *) echo "ERROR 1049: Unknown database"; exit 1 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	_, err := dbExecutor.Pipe(context.Background(), "database", dsn, "app", "app_copy", types.TableFilter{})
+	if err == nil {
+		t.Fatal("expected pipe to fail when import fails")
+	}
+	if !strings.Contains(err.Error(), "Unknown database") {
+		t.Errorf("expected import output in error, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_CompressedRoundTrip(t *testing.T) {
+	magic := map[string][]byte{
+		".sql.gz":  {0x1f, 0x8b},
+		".sql.zst": {0x28, 0xb5, 0x2f, 0xfd},
+	}
+
+	for ext, header := range magic {
+		t.Run(ext, func(t *testing.T) {
+			if ext == ".sql.zst" {
+				if _, err := exec.LookPath("zstd"); err != nil {
+					t.Skip("zstd binary not available")
+				}
+			}
+
+			dir := t.TempDir()
+			received := filepath.Join(dir, "received.sql")
+			fakeDocker(t, `case "$*" in
+  *mysqldump*) echo "CREATE TABLE t (id int);"; echo "-- Dump completed" ;;
+  *) cat > "`+received+`" ;;
+esac`)
+
+			dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+			dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+			dumpPath := filepath.Join(dir, "app"+ext)
+			if _, err := dbExecutor.Dump(context.Background(), "database", dsn, dumpPath, types.TableFilter{}); err != nil {
+				t.Fatalf("Dump() error = %v", err)
+			}
+
+			raw, err := os.ReadFile(dumpPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(raw, header) {
+				t.Errorf("expected %s dump to start with %x, got %x", ext, header, raw)
+			}
+
+			if _, err := dbExecutor.Import(context.Background(), "database", dsn, dumpPath, "app_copy", nil); err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			data, err := os.ReadFile(received)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "CREATE TABLE t (id int);\n-- Dump completed\n" {
+				t.Errorf("unexpected imported content %q", string(data))
+			}
+		})
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpChecksum(t *testing.T) {
+	fakeDocker(t, `echo "CREATE TABLE t (id int);"; echo "-- Dump completed"`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	result, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{})
+	if err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("CREATE TABLE t (id int);\n-- Dump completed\n"))
+	expected := "sha256:" + hex.EncodeToString(sum[:])
+	if result.Checksum != expected {
+		t.Errorf("expected checksum %s, got %s", expected, result.Checksum)
+	}
+}
+
+func TestDockerDatabaseExecutor_ServerVersionAndTables(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *VERSION*) echo "8.0.36" ;;
+  *"SHOW TABLES"*) printf 'users\nposts\n' ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	version, err := dbExecutor.ServerVersion(context.Background(), "database", dsn)
+	if err != nil {
+		t.Fatalf("ServerVersion() error = %v", err)
+	}
+	if version != "8.0.36" {
+		t.Errorf("expected version 8.0.36, got %q", version)
+	}
+
+	tables, err := dbExecutor.Tables(context.Background(), "database", dsn, "app")
+	if err != nil {
+		t.Fatalf("Tables() error = %v", err)
+	}
+	if len(tables) != 2 || tables[0] != "users" || tables[1] != "posts" {
+		t.Errorf("unexpected tables %v", tables)
+	}
+}
+
+func TestParseTableStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []types.TableStats
+		wantErr  bool
+	}{
+		{
+			name:   "mysql",
+			output: "users\t1200\t163840\t32768\t1714557600\nposts\t0\t16384\t0\t0\n",
+			expected: []types.TableStats{
+				{Name: "users", Rows: 1200, DataSize: 163840, IndexSize: 32768, Modified: "2024-05-01T10:00:00Z"},
+				{Name: "posts", DataSize: 16384},
+			},
+		},
+		{
+			name:     "decimal and negative estimates",
+			output:   "audit.log\t-1\t8192.0\t0\t0\n",
+			expected: []types.TableStats{{Name: "audit.log", DataSize: 8192}},
+		},
+		{
+			name:   "empty database",
+			output: "\n",
+		},
+		{
+			name:    "missing columns",
+			output:  "users\t12\n",
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			output:  "users\tNULL\t0\t0\t0\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseTableStats(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTableStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseTableStats() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDockerDatabaseExecutor_Stats(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *information_schema.TABLES*) printf 'posts\t10\t16384\t16384\t1714557600\nusers\t1200\t163840\t32768\t1714561200\nempty\t0\t0\t0\t0\n' ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	result, err := dbExecutor.Stats(context.Background(), "database", dsn, "app")
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	var names []string
+	for _, table := range result.Tables {
+		names = append(names, table.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"users", "posts", "empty"}) {
+		t.Errorf("expected tables largest first, got %v", names)
+	}
+
+	expected := types.DatabaseStats{Size: 229376, TableCount: 3, Rows: 1210, Modified: "2024-05-01T11:00:00Z"}
+	if result.Database != "app" || result.Stats != expected {
+		t.Errorf("Stats() = %s %+v, want app %+v", result.Database, result.Stats, expected)
+	}
+}
+
+func TestDockerDatabaseExecutor_Schema(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *information_schema.COLUMNS*) printf '%s\n' \
+    'column	users	id	int	NO	0		auto_increment' \
+    'column	users	status	varchar(20)	YES	1	new	' \
+    'column	posts	user_id	int	NO	0		' \
+    'index	users	PRIMARY	primary	id	BTREE		' \
+    'index	users	idx_status		status(10)	BTREE		' \
+    'foreign_key	posts	fk_user	user_id	users	id	CASCADE	RESTRICT' ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	schema, err := dbExecutor.Schema(context.Background(), "database", dsn, "app")
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+
+	status := "new"
+	expected := []types.TableSchema{
+		{
+			Name:    "posts",
+			Columns: []types.ColumnSchema{{Name: "user_id", Type: "int"}},
+			ForeignKeys: []types.ForeignKeySchema{
+				{Name: "fk_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "RESTRICT"},
+			},
+		},
+		{
+			Name: "users",
+			Columns: []types.ColumnSchema{
+				{Name: "id", Type: "int", Extra: "auto_increment"},
+				{Name: "status", Type: "varchar(20)", Nullable: true, Default: &status},
+			},
+			Indexes: []types.IndexSchema{
+				{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id"}, Method: "BTREE"},
+				{Name: "idx_status", Columns: []string{"status(10)"}, Method: "BTREE"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(schema.Tables, expected) {
+		t.Errorf("Schema() = %+v, want %+v", schema.Tables, expected)
+	}
+}
+
+func TestParseSchema_UnexpectedLine(t *testing.T) {
+	for _, output := range []string{"column\tusers\tid\n", "view\tv\t\t\t\t\t\t\n"} {
+		if _, err := parseSchema(output); err == nil {
+			t.Errorf("expected an error for %q", output)
+		}
+	}
+}
+
+func TestDockerDatabaseExecutor_Copy(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "copy.sql")
+	fakeDocker(t, `case "$*" in
+  *information_schema.VIEWS*) ;;
+  *information_schema.KEY_COLUMN_USAGE*) echo 'ALTER TABLE fk' ;;
+  *"SHOW TABLES"*) printf 'users\nposts\n' ;;
+  *) for arg; do last=$arg; done; echo "$last" > "`+script+`" ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	if _, err := dbExecutor.Copy(context.Background(), "database", dsn, "app", "app_copy"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	data, err := os.ReadFile(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"CREATE TABLE `app_copy`.`users` LIKE `app`.`users`", "INSERT INTO `app_copy`.`posts` SELECT * FROM `app`.`posts`", "; ALTER TABLE fk\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in the copy script, got %q", want, data)
+		}
+	}
+}
+
+func TestDockerDatabaseExecutor_CopyRefusesViewsAndTriggers(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *information_schema.VIEWS*) printf 'view active_users\ntrigger audit\n' ;;
+  *) echo "must not run: $*" >&2; exit 1 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	_, err := dbExecutor.Copy(context.Background(), "database", dsn, "app", "app_copy")
+	if err == nil || !strings.Contains(err.Error(), "view active_users, trigger audit") {
+		t.Errorf("expected the blocking objects in the error, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_SwapRefusesViewsAndTriggers(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *information_schema.VIEWS*"'app_haive_restore'"*) printf 'view active_users\n' ;;
+  *information_schema.VIEWS*) printf 'trigger audit\n' ;;
+  *) echo "must not run: $*" >&2; exit 1 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	err := dbExecutor.Swap(context.Background(), "database", dsn, "app_haive_restore", "app")
+	if err == nil || !strings.Contains(err.Error(), "view active_users in app_haive_restore, trigger audit in app") {
+		t.Errorf("expected the blocking objects of both databases in the error, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_ServerCloneUnsupported(t *testing.T) {
+	fakeDocker(t, `echo "must not run: $*" >&2; exit 1`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	mysql := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+	postgres := NewDockerDatabaseExecutor(engines.NewPostgresEngine(), Compose{}, t.TempDir())
+
+	if err := mysql.Template(context.Background(), "database", dsn, "app", "app_copy"); !errors.Is(err, ErrCloneUnsupported) {
+		t.Errorf("expected ErrCloneUnsupported for a MySQL template, got %v", err)
+	}
+	if _, err := postgres.Copy(context.Background(), "database", dsn, "app", "app_copy"); !errors.Is(err, ErrCloneUnsupported) {
+		t.Errorf("expected ErrCloneUnsupported for a PostgreSQL table copy, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_PasswordViaEnvironment(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *"-e MYSQL_PWD database mysql "*) echo "$MYSQL_PWD" ;;
+  *) echo "unexpected args: $*" >&2; exit 1 ;;
+esac
+case "$*" in
+  *s3cr3t*) echo "password in argv" >&2; exit 1 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "s3cr3t", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	version, err := dbExecutor.ServerVersion(context.Background(), "database", dsn)
+	if err != nil {
+		t.Fatalf("ServerVersion() error = %v", err)
+	}
+	if version != "s3cr3t" {
+		t.Errorf("expected the password in the client environment, got %q", version)
+	}
+}
+
+func testAnonymizer(t *testing.T) *anonymize.Anonymizer {
+	t.Helper()
+	rules, err := anonymize.ParseRules(map[string]map[string]string{"users": {"email": "null"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return anonymize.New("seed", rules)
+}
+
+func TestDockerDatabaseExecutor_DumpAnonymized(t *testing.T) {
+	fakeDocker(t, `echo "INSERT INTO users (id, email) VALUES (1,'jane@real.com');"; echo "-- Dump completed"`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	dbExecutor.SetAnonymizer(testAnonymizer(t))
+	if _, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{}); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO users (id, email) VALUES (1,NULL);\n-- Dump completed\n"; string(data) != want {
+		t.Errorf("dump content = %q, want %q", data, want)
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpAnonymizeFailure(t *testing.T) {
+	// No column list and no CREATE TABLE: the anonymizer can't locate the email column
+	fakeDocker(t, `exec yes "INSERT INTO users VALUES (1,'jane@real.com');"`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	dbExecutor.SetAnonymizer(testAnonymizer(t))
+	_, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{})
+	if err == nil || !strings.Contains(err.Error(), "anonymize failed") {
+		t.Fatalf("expected anonymize error, got %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected no files left behind, found %d", len(entries))
+	}
+}
+
+func TestDockerDatabaseExecutor_PipeAnonymized(t *testing.T) {
+	dir := t.TempDir()
+	received := filepath.Join(dir, "received.sql")
+	fakeDocker(t, `case "$*" in
+  *mysqldump*) echo "INSERT INTO users (id, email) VALUES (1,'jane@real.com');" ;;
+  *) cat > "`+received+`" ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	dbExecutor.SetAnonymizer(testAnonymizer(t))
+
+	if _, err := dbExecutor.Pipe(context.Background(), "database", dsn, "app", "app_copy", types.TableFilter{}); err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO users (id, email) VALUES (1,NULL);\n"; string(data) != want {
+		t.Errorf("piped content = %q, want %q", data, want)
+	}
+}
+
+func TestDockerDatabaseExecutor_PipeAnonymizeFailure(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *mysqldump*) exec yes "INSERT INTO users VALUES (1,'jane@real.com');" ;;
+  *) cat > /dev/null ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+	dbExecutor.SetAnonymizer(testAnonymizer(t))
+
+	_, err := dbExecutor.Pipe(context.Background(), "database", dsn, "app", "app_copy", types.TableFilter{})
+	if err == nil || !strings.Contains(err.Error(), "anonymize failed") {
+		t.Fatalf("expected anonymize error, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpTableFilter(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls.log")
+	fakeDocker(t, `echo "$*" >> "`+calls+`"
+case "$*" in
+  *"SHOW TABLES"*) printf "users\naudit_log\nmessenger_messages\nmessenger_failed\n" ;;
+  *--no-data*) echo "-- structure"; echo "-- Dump completed" ;;
+  *) echo "-- data"; echo "-- Dump completed" ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	destPath := filepath.Join(dir, "app.sql")
+	filter := types.TableFilter{ExcludeTables: []string{"messenger_*"}, StructureOnly: []string{"audit_log"}}
+	if _, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, filter); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "-- data\n-- Dump completed\n-- structure\n-- Dump completed\n" {
+		t.Errorf("expected both dump runs in order, got %q", data)
+	}
+
+	log, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"--ignore-table=app.messenger_messages", "--ignore-table=app.messenger_failed", "--ignore-table=app.audit_log", "--no-data app audit_log"} {
+		if !strings.Contains(string(log), want) {
+			t.Errorf("expected %q in docker calls:\n%s", want, log)
+		}
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpNoMatchingTables(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *"SHOW TABLES"*) echo "users" ;;
+esac`)
+
+	dir := t.TempDir()
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	_, err := dbExecutor.Dump(context.Background(), "database", dsn, filepath.Join(dir, "app.sql"), types.TableFilter{Tables: []string{"cache_*"}})
+	if err == nil || !strings.Contains(err.Error(), "no tables") {
+		t.Fatalf("expected no matching tables error, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_PipeMultipleDumpCommands(t *testing.T) {
+	dir := t.TempDir()
+	received := filepath.Join(dir, "received.sql")
+	fakeDocker(t, `case "$*" in
+  *--no-data*) echo "-- structure" ;;
+  *mysqldump*) echo "-- data" ;;
+  *) cat > "`+received+`" ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	if _, err := dbExecutor.Pipe(context.Background(), "database", dsn, "app", "app_copy", types.TableFilter{StructureOnly: []string{"audit_log"}}); err != nil {
+		t.Fatalf("Pipe() error = %v", err)
+	}
+
+	data, err := os.ReadFile(received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "-- data\n-- structure\n" {
+		t.Errorf("expected both dump outputs in order, got %q", data)
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpCancelled(t *testing.T) {
+	fakeDocker(t, `echo "CREATE TABLE t (id int);"; exec sleep 30`)
+
+	dir := t.TempDir()
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := dbExecutor.Dump(ctx, "database", dsn, filepath.Join(dir, "app.sql"), types.TableFilter{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= pipeWaitDelay {
+		t.Errorf("expected the dump to stop on cancellation, took %v", elapsed)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected no files left after a cancelled dump, got %v", entries)
+	}
+}
+
+func TestDockerDatabaseExecutor_PipeCancelled(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *mysqldump*) exec yes "INSERT INTO t VALUES (1);" ;;
+  *) exec sleep 30 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := dbExecutor.Pipe(ctx, "database", dsn, "app", "app_copy", types.TableFilter{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= pipeWaitDelay {
+		t.Errorf("expected the clone to stop on cancellation, took %v", elapsed)
+	}
+}
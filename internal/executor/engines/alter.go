@@ -0,0 +1,65 @@
+package engines
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// alterPhase orders the statements of a schema change: foreign keys and
+// indexes go before the columns and tables they use, and come back after them
+type alterPhase int
+
+const (
+	phaseDropForeignKey alterPhase = iota
+	phaseDropIndex
+	phaseDropColumn
+	phaseDropTable
+	phaseCreateTable
+	phaseAddColumn
+	phaseModifyColumn
+	phaseAddIndex
+	phaseAddForeignKey
+	phaseCount
+)
+
+// alterScript collects statements by phase
+type alterScript struct {
+	phases [phaseCount][]string
+}
+
+func (s *alterScript) add(phase alterPhase, statements ...string) {
+	s.phases[phase] = append(s.phases[phase], statements...)
+}
+
+// statements returns the statements in the order of their phases
+func (s *alterScript) statements() []string {
+	var all []string
+	for _, statements := range s.phases {
+		all = append(all, statements...)
+	}
+	return all
+}
+
+// unsupportedChange is the comment standing in for a change the engine
+// can't make in place
+func unsupportedChange(engine string, change types.SchemaChange) string {
+	object := change.Table
+	if change.Name != "" {
+		object += "." + change.Name
+	}
+	return fmt.Sprintf("-- %s %s: %s can't alter this in place, rebuild the table", change.Kind, object, engine)
+}
+
+// prefixLength matches a MySQL index column with a prefix length like name(10)
+var prefixLength = regexp.MustCompile(`^(.*)\((\d+)\)$`)
+
+// splitQualified splits schema.table, returning no schema for a plain name
+func splitQualified(name string) (schema, table string) {
+	if i := strings.Index(name, "."); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
@@ -1,12 +1,161 @@
 package engines
 
-import "github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+import (
+	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// SwapBackupSuffix names the database that briefly holds the replaced data during a swap
+const SwapBackupSuffix = "_haive_old"
+
+// DumpSpec is a types.TableFilter with every pattern resolved to table names
+type DumpSpec struct {
+	Tables        []string
+	ExcludeTables []string
+	StructureOnly []string
+	SchemaOnly    bool
+	DataOnly      bool
+}
+
+// DumpMarkers identify the output of an engine's dump tool: one of the first
+// lines starts with one of Headers, and a finished dump has a line starting
+// with Footer near its end. The zero value marks engines without them.
+type DumpMarkers struct {
+	Headers []string
+	Footer  string
+}
+
+// IsDump reports whether head, the start of a SQL file, was written by the
+// dump tool
+func (m DumpMarkers) IsDump(head []byte) bool {
+	for _, line := range strings.Split(string(head), "\n") {
+		for _, header := range m.Headers {
+			if strings.HasPrefix(line, header) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Complete reports whether tail, the end of a SQL file, holds the footer
+func (m DumpMarkers) Complete(tail []byte) bool {
+	for _, line := range strings.Split(string(tail), "\n") {
+		if strings.HasPrefix(line, m.Footer) {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryFormat tells how the output of an engine's query command is written
+type QueryFormat int
+
+const (
+	// QueryFormatTSV is a header line followed by a line per row, fields
+	// separated by tabs with \t, \n, \0 and \\ escaped. NULL reads NULL.
+	QueryFormatTSV QueryFormat = iota
+	// QueryFormatCSV is RFC 4180 CSV with a header record. NULL reads \N.
+	QueryFormatCSV
+)
 
 type DatabaseEngine interface {
-	BuildDumpCommand(dsn *types.DSN, tables []string) []string
+	// Env returns the NAME=value pairs that pass the DSN credentials to the
+	// client tools, which keeps passwords out of their argv
+	Env(dsn *types.DSN) []string
+	// PortEnv returns the NAME=value pairs that point the client tools at the
+	// DSN port. Only clients on the host need them: inside the compose service
+	// they reach the server on its own port.
+	PortEnv(dsn *types.DSN) []string
+	// BuildDumpCommands returns the commands whose concatenated output is the
+	// dump of dsn.Database. It returns no commands if the spec leaves nothing to dump.
+	BuildDumpCommands(dsn *types.DSN, spec DumpSpec) [][]string
 	BuildCreateCommand(dsn *types.DSN, dbName string) []string
 	BuildImportCommand(dsn *types.DSN, dbName string) []string
 	BuildDropCommand(dsn *types.DSN, dbName string) []string
 	BuildListCommand(dsn *types.DSN) []string
+	BuildVersionCommand(dsn *types.DSN) []string
+	BuildListTablesCommand(dsn *types.DSN, dbName string) []string
+	// BuildTableStatsCommand returns the query that prints one tab separated
+	// line per table of dbName: name, rows, data bytes, index bytes and the
+	// Unix time of its last change, 0 if unknown. tables lists the current
+	// tables for engines that have to count rows table by table.
+	BuildTableStatsCommand(dsn *types.DSN, dbName string, tables []string) []string
+	// BuildSchemaCommand returns the catalog queries that print the tables of
+	// dbName as lines of eight tab separated fields, the first naming the kind:
+	//   column       table, name, type, YES|NO nullable, 1|0 has default, default, extra
+	//   index        table, name, primary|unique|"", comma separated columns, method, constraint|"", ""
+	//   foreign_key  table, name, columns, referenced table, referenced columns, on delete, on update
+	BuildSchemaCommand(dsn *types.DSN, dbName string) []string
+	// BuildAlterStatements returns the statements that apply changes to a
+	// database, in an order the server accepts. Changes the engine can't make
+	// in place come out as SQL comments.
+	BuildAlterStatements(changes []types.SchemaChange) []string
+	// BuildQueryCommand returns the command that runs query, a statement
+	// core.CheckReadOnlyQuery accepted, on dbName in a read-only transaction.
+	// Where the server can, it returns at most maxRows+1 rows, which shows the
+	// caller there were more, and cancels the statement after timeout.
+	BuildQueryCommand(dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) []string
+	// QueryFormat tells how the output of the query command is written
+	QueryFormat() QueryFormat
+	// BuildSwapCommand makes targetDB hold what sourceDB holds and removes
+	// sourceDB. sourceTables and targetTables list the current tables of each.
+	BuildSwapCommand(dsn *types.DSN, sourceDB, targetDB string, sourceTables, targetTables []string) []string
+	// BuildTemplateCommand returns the command that creates targetDB as a copy
+	// of sourceDB made by the server itself, or nil if the engine can't
+	BuildTemplateCommand(dsn *types.DSN, sourceDB, targetDB string) []string
+	// BuildCopyCheckCommand returns the query that lists the objects of sourceDB
+	// a table copy can't reproduce, one per line, or nil if the engine can't
+	// copy tables. Swaps that move tables check both databases with it.
+	BuildCopyCheckCommand(dsn *types.DSN, sourceDB string) []string
+	// BuildForeignKeysCommand returns the query whose output lines are the
+	// statements that add the foreign keys of sourceDB to its copy in targetDB
+	BuildForeignKeysCommand(dsn *types.DSN, sourceDB, targetDB string) []string
+	// BuildCopyCommand returns the command that copies tables of sourceDB into
+	// the existing, empty targetDB on the server and then runs statements
+	BuildCopyCommand(dsn *types.DSN, sourceDB, targetDB string, tables, statements []string) []string
+	SystemDatabases() []string
+	// DumpMarkers returns the lines that open and close the output of the
+	// dump commands
+	DumpMarkers() DumpMarkers
+	// Dialect tells how the output of the dump command is quoted
+	Dialect() sqldump.Dialect
 	Name() string
 }
+
+// without returns the names of list that are not in any of the exclude lists
+func without(list []string, exclude ...[]string) []string {
+	skip := make(map[string]bool)
+	for _, names := range exclude {
+		for _, name := range names {
+			skip[name] = true
+		}
+	}
+
+	var kept []string
+	for _, name := range list {
+		if !skip[name] {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+// firstKeyword returns the leading keyword of statement in upper case
+func firstKeyword(statement string) string {
+	end := strings.IndexFunc(statement, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(statement)
+	}
+	return strings.ToUpper(statement[:end])
+}
+
+// intersect returns the names of list that are also in other
+func intersect(list, other []string) []string {
+	return without(list, without(list, other))
+}
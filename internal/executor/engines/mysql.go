@@ -2,7 +2,10 @@ package engines
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
@@ -14,25 +17,82 @@ func NewMySQLEngine(isMariaDB bool) *MySQLEngine {
 	return &MySQLEngine{isMariaDB: isMariaDB}
 }
 
-func (e *MySQLEngine) BuildDumpCommand(dsn *types.DSN, tables []string) []string {
+// BuildDumpCommands translates the spec into mysqldump runs. mysqldump can't
+// skip the rows of single tables, so structure-only tables are left out of the
+// main run and dumped by a second run with --no-data.
+func (e *MySQLEngine) BuildDumpCommands(dsn *types.DSN, spec DumpSpec) [][]string {
 	dumpCmd := "mysqldump"
 	if e.isMariaDB {
 		dumpCmd = "mariadb-dump"
 	}
 
-	cmd := []string{
-		dumpCmd,
-		"-h", dsn.Host,
-		"-u", dsn.User,
-		fmt.Sprintf("-p%s", dsn.Password),
-		dsn.Database,
+	base := func(flags ...string) []string {
+		return append([]string{
+			dumpCmd,
+			"-h", dsn.Host,
+			"-u", dsn.User,
+		}, flags...)
+	}
+
+	var flags []string
+	if spec.SchemaOnly {
+		flags = append(flags, "--no-data")
+	}
+	if spec.DataOnly {
+		flags = append(flags, "--no-create-info")
+	}
+
+	exclude := spec.ExcludeTables
+	structureOnly := spec.StructureOnly
+	switch {
+	case spec.SchemaOnly:
+		// Every table is structure-only already
+		structureOnly = nil
+	case spec.DataOnly:
+		// A structure-only table has nothing left to dump without its schema
+		exclude = append(append([]string{}, exclude...), structureOnly...)
+		structureOnly = nil
+	}
+
+	var commands [][]string
+	if len(spec.Tables) > 0 {
+		structureOnly = intersect(structureOnly, spec.Tables)
+		if tables := without(spec.Tables, exclude, structureOnly); len(tables) > 0 {
+			cmd := append(base(flags...), dsn.Database)
+			commands = append(commands, append(cmd, tables...))
+		}
+	} else {
+		cmd := base(flags...)
+		for _, table := range append(append([]string{}, exclude...), structureOnly...) {
+			cmd = append(cmd, fmt.Sprintf("--ignore-table=%s.%s", dsn.Database, table))
+		}
+		commands = append(commands, append(cmd, dsn.Database))
 	}
 
-	if len(tables) > 0 {
-		cmd = append(cmd, tables...)
+	if tables := without(structureOnly, exclude); len(tables) > 0 {
+		cmd := append(base("--no-data"), dsn.Database)
+		commands = append(commands, append(cmd, tables...))
 	}
 
-	return cmd
+	return commands
+}
+
+// Env supplies the password through MYSQL_PWD, so it never appears in the
+// client's argv
+func (e *MySQLEngine) Env(dsn *types.DSN) []string {
+	if dsn.Password == "" {
+		return nil
+	}
+	return []string{"MYSQL_PWD=" + dsn.Password}
+}
+
+// PortEnv sets MYSQL_TCP_PORT. The client ignores it for the host localhost,
+// which it reaches through the socket instead; use 127.0.0.1 for a TCP port.
+func (e *MySQLEngine) PortEnv(dsn *types.DSN) []string {
+	if dsn.Port == "" {
+		return nil
+	}
+	return []string{"MYSQL_TCP_PORT=" + dsn.Port}
 }
 
 func (e *MySQLEngine) BuildCreateCommand(dsn *types.DSN, dbName string) []string {
@@ -40,7 +100,6 @@ func (e *MySQLEngine) BuildCreateCommand(dsn *types.DSN, dbName string) []string
 		"mysql",
 		"-h", dsn.Host,
 		"-u", dsn.User,
-		fmt.Sprintf("-p%s", dsn.Password),
 		"-e", fmt.Sprintf("CREATE DATABASE `%s`", dbName),
 	}
 }
@@ -50,7 +109,6 @@ func (e *MySQLEngine) BuildImportCommand(dsn *types.DSN, dbName string) []string
 		"mysql",
 		"-h", dsn.Host,
 		"-u", dsn.User,
-		fmt.Sprintf("-p%s", dsn.Password),
 		dbName,
 	}
 }
@@ -60,7 +118,6 @@ func (e *MySQLEngine) BuildDropCommand(dsn *types.DSN, dbName string) []string {
 		"mysql",
 		"-h", dsn.Host,
 		"-u", dsn.User,
-		fmt.Sprintf("-p%s", dsn.Password),
 		"-e", fmt.Sprintf("DROP DATABASE `%s`", dbName),
 	}
 }
@@ -70,11 +127,395 @@ func (e *MySQLEngine) BuildListCommand(dsn *types.DSN) []string {
 		"mysql",
 		"-h", dsn.Host,
 		"-u", dsn.User,
-		fmt.Sprintf("-p%s", dsn.Password),
 		"-e", "SHOW DATABASES",
 	}
 }
 
+func (e *MySQLEngine) BuildVersionCommand(dsn *types.DSN) []string {
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-N", "-B",
+		"-e", "SELECT VERSION()",
+	}
+}
+
+func (e *MySQLEngine) BuildListTablesCommand(dsn *types.DSN, dbName string) []string {
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-N", "-B",
+		"-e", "SHOW TABLES",
+		dbName,
+	}
+}
+
+// BuildTableStatsCommand reads the table statistics of information_schema.
+// InnoDB only estimates TABLE_ROWS, and UPDATE_TIME is lost on restart, so
+// the creation time stands in for it.
+func (e *MySQLEngine) BuildTableStatsCommand(dsn *types.DSN, dbName string, tables []string) []string {
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-N", "-B", "-r",
+		"-e", "SELECT TABLE_NAME, IFNULL(TABLE_ROWS, 0), IFNULL(DATA_LENGTH, 0), IFNULL(INDEX_LENGTH, 0), " +
+			"IFNULL(UNIX_TIMESTAMP(IFNULL(UPDATE_TIME, CREATE_TIME)), 0) " +
+			"FROM information_schema.TABLES WHERE TABLE_SCHEMA = " + quoteMySQLString(dbName) +
+			" AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME",
+	}
+}
+
+// BuildSchemaCommand runs one query per kind of object on
+// information_schema. Index columns with a prefix length read like name(10).
+func (e *MySQLEngine) BuildSchemaCommand(dsn *types.DSN, dbName string) []string {
+	schema := quoteMySQLString(dbName)
+
+	columns := "SELECT 'column', c.TABLE_NAME, c.COLUMN_NAME, c.COLUMN_TYPE, c.IS_NULLABLE, " +
+		"IF(c.COLUMN_DEFAULT IS NULL, 0, 1), IFNULL(c.COLUMN_DEFAULT, ''), c.EXTRA " +
+		"FROM information_schema.COLUMNS c JOIN information_schema.TABLES t " +
+		"ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME " +
+		"WHERE c.TABLE_SCHEMA = " + schema + " AND t.TABLE_TYPE = 'BASE TABLE' " +
+		"ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION"
+
+	indexes := "SELECT 'index', TABLE_NAME, INDEX_NAME, " +
+		"IF(INDEX_NAME = 'PRIMARY', 'primary', IF(NON_UNIQUE = 0, 'unique', '')), " +
+		"GROUP_CONCAT(IF(SUB_PART IS NULL, COLUMN_NAME, CONCAT(COLUMN_NAME, '(', SUB_PART, ')')) ORDER BY SEQ_IN_INDEX SEPARATOR ','), " +
+		"INDEX_TYPE, '', '' " +
+		"FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = " + schema + " " +
+		"GROUP BY TABLE_NAME, INDEX_NAME, NON_UNIQUE, INDEX_TYPE ORDER BY TABLE_NAME, INDEX_NAME"
+
+	foreignKeys := "SELECT 'foreign_key', k.TABLE_NAME, k.CONSTRAINT_NAME, " +
+		"GROUP_CONCAT(k.COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ','), " +
+		"IF(k.REFERENCED_TABLE_SCHEMA = " + schema + ", k.REFERENCED_TABLE_NAME, CONCAT(k.REFERENCED_TABLE_SCHEMA, '.', k.REFERENCED_TABLE_NAME)), " +
+		"GROUP_CONCAT(k.REFERENCED_COLUMN_NAME ORDER BY k.ORDINAL_POSITION SEPARATOR ','), r.DELETE_RULE, r.UPDATE_RULE " +
+		"FROM information_schema.KEY_COLUMN_USAGE k " +
+		"JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA " +
+		"AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME " +
+		"WHERE k.TABLE_SCHEMA = " + schema + " AND k.REFERENCED_TABLE_NAME IS NOT NULL " +
+		"GROUP BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, r.DELETE_RULE, r.UPDATE_RULE " +
+		"ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME"
+
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-N", "-B", "-r",
+		"-e", columns + "; " + indexes + "; " + foreignKeys,
+	}
+}
+
+// BuildAlterStatements writes one ALTER TABLE statement per change. New
+// tables get their indexes in CREATE TABLE and their foreign keys once all
+// tables exist. Generated columns can't be rebuilt from the catalog.
+func (e *MySQLEngine) BuildAlterStatements(changes []types.SchemaChange) []string {
+	var script alterScript
+	for _, change := range changes {
+		table := quoteMySQLIdent(change.Table)
+		switch change.Kind {
+		case types.SchemaAddTable:
+			var lines []string
+			for i := range change.NewTable.Columns {
+				lines = append(lines, "  "+e.columnDefinition(&change.NewTable.Columns[i]))
+			}
+			for i := range change.NewTable.Indexes {
+				lines = append(lines, "  "+mysqlIndexDefinition(&change.NewTable.Indexes[i]))
+			}
+			script.add(phaseCreateTable, "CREATE TABLE "+table+" (\n"+strings.Join(lines, ",\n")+"\n)")
+			for i := range change.NewTable.ForeignKeys {
+				script.add(phaseAddForeignKey, "ALTER TABLE "+table+" ADD "+mysqlForeignKeyDefinition(&change.NewTable.ForeignKeys[i]))
+			}
+		case types.SchemaDropTable:
+			script.add(phaseDropTable, "DROP TABLE "+table)
+		case types.SchemaAddColumn, types.SchemaModifyColumn:
+			if strings.Contains(change.NewColumn.Extra, "GENERATED") && !strings.Contains(change.NewColumn.Extra, "DEFAULT_GENERATED") {
+				script.add(phaseAddColumn, unsupportedChange(e.Name(), change))
+				continue
+			}
+			if change.Kind == types.SchemaAddColumn {
+				script.add(phaseAddColumn, "ALTER TABLE "+table+" ADD COLUMN "+e.columnDefinition(change.NewColumn))
+			} else {
+				script.add(phaseModifyColumn, "ALTER TABLE "+table+" MODIFY COLUMN "+e.columnDefinition(change.NewColumn))
+			}
+		case types.SchemaDropColumn:
+			script.add(phaseDropColumn, "ALTER TABLE "+table+" DROP COLUMN "+quoteMySQLIdent(change.Name))
+		case types.SchemaAddIndex:
+			script.add(phaseAddIndex, "ALTER TABLE "+table+" ADD "+mysqlIndexDefinition(change.NewIndex))
+		case types.SchemaDropIndex:
+			script.add(phaseDropIndex, "ALTER TABLE "+table+" "+mysqlDropIndex(change.OldIndex))
+		case types.SchemaModifyIndex:
+			script.add(phaseAddIndex, "ALTER TABLE "+table+" "+mysqlDropIndex(change.OldIndex)+", ADD "+mysqlIndexDefinition(change.NewIndex))
+		case types.SchemaAddForeignKey:
+			script.add(phaseAddForeignKey, "ALTER TABLE "+table+" ADD "+mysqlForeignKeyDefinition(change.NewForeignKey))
+		case types.SchemaDropForeignKey:
+			script.add(phaseDropForeignKey, "ALTER TABLE "+table+" DROP FOREIGN KEY "+quoteMySQLIdent(change.Name))
+		case types.SchemaModifyForeignKey:
+			script.add(phaseDropForeignKey, "ALTER TABLE "+table+" DROP FOREIGN KEY "+quoteMySQLIdent(change.Name))
+			script.add(phaseAddForeignKey, "ALTER TABLE "+table+" ADD "+mysqlForeignKeyDefinition(change.NewForeignKey))
+		}
+	}
+	return script.statements()
+}
+
+// columnDefinition writes a column for CREATE and ALTER TABLE. MySQL reports
+// literal defaults unquoted and marks expressions with DEFAULT_GENERATED,
+// MariaDB quotes literals itself.
+func (e *MySQLEngine) columnDefinition(c *types.ColumnSchema) string {
+	definition := quoteMySQLIdent(c.Name) + " " + c.Type
+	if !c.Nullable {
+		definition += " NOT NULL"
+	}
+
+	extra := strings.TrimSpace(strings.ReplaceAll(c.Extra, "DEFAULT_GENERATED", ""))
+	if c.Default != nil {
+		value := *c.Default
+		literal := !e.isMariaDB && !strings.Contains(c.Extra, "DEFAULT_GENERATED") &&
+			value != "NULL" && !strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP")
+		if literal {
+			value = quoteMySQLString(value)
+		}
+		definition += " DEFAULT " + value
+	}
+	if extra != "" {
+		definition += " " + extra
+	}
+	return definition
+}
+
+func mysqlIndexDefinition(i *types.IndexSchema) string {
+	var columns []string
+	for _, column := range i.Columns {
+		if m := prefixLength.FindStringSubmatch(column); m != nil {
+			columns = append(columns, quoteMySQLIdent(m[1])+"("+m[2]+")")
+		} else {
+			columns = append(columns, quoteMySQLIdent(column))
+		}
+	}
+	list := " (" + strings.Join(columns, ", ") + ")"
+
+	switch {
+	case i.Primary:
+		return "PRIMARY KEY" + list
+	case i.Unique:
+		return "UNIQUE INDEX " + quoteMySQLIdent(i.Name) + list
+	case i.Method == "FULLTEXT" || i.Method == "SPATIAL":
+		return i.Method + " INDEX " + quoteMySQLIdent(i.Name) + list
+	default:
+		return "INDEX " + quoteMySQLIdent(i.Name) + list
+	}
+}
+
+func mysqlDropIndex(i *types.IndexSchema) string {
+	if i.Primary {
+		return "DROP PRIMARY KEY"
+	}
+	return "DROP INDEX " + quoteMySQLIdent(i.Name)
+}
+
+func mysqlForeignKeyDefinition(k *types.ForeignKeySchema) string {
+	definition := "CONSTRAINT " + quoteMySQLIdent(k.Name) +
+		" FOREIGN KEY (" + quoteMySQLIdents(k.Columns) + ")" +
+		" REFERENCES " + quoteMySQLQualified(k.ReferencedTable) + " (" + quoteMySQLIdents(k.ReferencedColumns) + ")"
+	if k.OnDelete != "" {
+		definition += " ON DELETE " + k.OnDelete
+	}
+	if k.OnUpdate != "" {
+		definition += " ON UPDATE " + k.OnUpdate
+	}
+	return definition
+}
+
+// BuildQueryCommand runs query in a READ ONLY transaction. sql_select_limit
+// cuts a SELECT off after maxRows+1 rows; the timeout is max_execution_time,
+// which only covers SELECT, on MySQL and max_statement_time on MariaDB. The
+// output is escaped, not raw, so every row stays on one line.
+func (e *MySQLEngine) BuildQueryCommand(dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) []string {
+	statements := []string{fmt.Sprintf("SET SESSION sql_select_limit = %d", maxRows+1)}
+	if timeout > 0 {
+		if e.isMariaDB {
+			statements = append(statements, fmt.Sprintf("SET SESSION max_statement_time = %g", timeout.Seconds()))
+		} else {
+			statements = append(statements, fmt.Sprintf("SET SESSION max_execution_time = %d", timeout.Milliseconds()))
+		}
+	}
+	statements = append(statements, "START TRANSACTION READ ONLY", query, "ROLLBACK")
+
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-B",
+		"-e", strings.Join(statements, "; "),
+		dbName,
+	}
+}
+
+func (e *MySQLEngine) QueryFormat() QueryFormat {
+	return QueryFormatTSV
+}
+
+// BuildSwapCommand moves the tables with a single RENAME TABLE statement, which
+// MySQL applies atomically, since there is no way to rename a database. Views
+// and triggers can't be moved to another database, the executor refuses
+// databases that have them.
+func (e *MySQLEngine) BuildSwapCommand(dsn *types.DSN, sourceDB, targetDB string, sourceTables, targetTables []string) []string {
+	backupDB := targetDB + SwapBackupSuffix
+
+	var renames []string
+	for _, table := range targetTables {
+		renames = append(renames, fmt.Sprintf("%s.%s TO %s.%s",
+			quoteMySQLIdent(targetDB), quoteMySQLIdent(table), quoteMySQLIdent(backupDB), quoteMySQLIdent(table)))
+	}
+	for _, table := range sourceTables {
+		renames = append(renames, fmt.Sprintf("%s.%s TO %s.%s",
+			quoteMySQLIdent(sourceDB), quoteMySQLIdent(table), quoteMySQLIdent(targetDB), quoteMySQLIdent(table)))
+	}
+
+	statements := []string{
+		fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteMySQLIdent(backupDB)),
+		fmt.Sprintf("CREATE DATABASE %s", quoteMySQLIdent(backupDB)),
+	}
+	if len(renames) > 0 {
+		statements = append(statements, "RENAME TABLE "+strings.Join(renames, ", "))
+	}
+	statements = append(statements,
+		fmt.Sprintf("DROP DATABASE %s", quoteMySQLIdent(backupDB)),
+		fmt.Sprintf("DROP DATABASE %s", quoteMySQLIdent(sourceDB)),
+	)
+
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-e", strings.Join(statements, "; "),
+	}
+}
+
+// BuildTemplateCommand returns nil, MySQL has no template databases
+func (e *MySQLEngine) BuildTemplateCommand(dsn *types.DSN, sourceDB, targetDB string) []string {
+	return nil
+}
+
+// BuildCopyCheckCommand lists the views and triggers of sourceDB. CREATE TABLE
+// LIKE can't copy views, and triggers would be lost silently.
+func (e *MySQLEngine) BuildCopyCheckCommand(dsn *types.DSN, sourceDB string) []string {
+	schema := quoteMySQLString(sourceDB)
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-N", "-B", "-r",
+		"-e", "SELECT CONCAT('view ', TABLE_NAME) FROM information_schema.VIEWS WHERE TABLE_SCHEMA = " + schema +
+			" UNION ALL SELECT CONCAT('trigger ', TRIGGER_NAME) FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = " + schema,
+	}
+}
+
+// BuildForeignKeysCommand generates an ALTER TABLE statement per foreign key,
+// since CREATE TABLE LIKE leaves them out. References within sourceDB point
+// to targetDB, references to other databases are kept.
+func (e *MySQLEngine) BuildForeignKeysCommand(dsn *types.DSN, sourceDB, targetDB string) []string {
+	schema := quoteMySQLString(sourceDB)
+	target := quoteMySQLString(quoteMySQLIdent(targetDB))
+
+	query := "SELECT CONCAT('ALTER TABLE ', " + target + ", '.', " + quoteMySQLIdentExpr("k.TABLE_NAME") +
+		", ' ADD CONSTRAINT ', " + quoteMySQLIdentExpr("k.CONSTRAINT_NAME") +
+		", ' FOREIGN KEY (', GROUP_CONCAT(" + quoteMySQLIdentExpr("k.COLUMN_NAME") + " ORDER BY k.ORDINAL_POSITION SEPARATOR ', ')" +
+		", ') REFERENCES ', IF(k.REFERENCED_TABLE_SCHEMA = " + schema + ", " + target + ", " + quoteMySQLIdentExpr("k.REFERENCED_TABLE_SCHEMA") + ")" +
+		", '.', " + quoteMySQLIdentExpr("k.REFERENCED_TABLE_NAME") +
+		", ' (', GROUP_CONCAT(" + quoteMySQLIdentExpr("k.REFERENCED_COLUMN_NAME") + " ORDER BY k.ORDINAL_POSITION SEPARATOR ', ')" +
+		", ') ON DELETE ', r.DELETE_RULE, ' ON UPDATE ', r.UPDATE_RULE) " +
+		"FROM information_schema.KEY_COLUMN_USAGE k " +
+		"JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA " +
+		"AND r.TABLE_NAME = k.TABLE_NAME AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME " +
+		"WHERE k.TABLE_SCHEMA = " + schema + " AND k.REFERENCED_TABLE_NAME IS NOT NULL " +
+		"GROUP BY k.TABLE_NAME, k.CONSTRAINT_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, r.DELETE_RULE, r.UPDATE_RULE " +
+		"ORDER BY k.TABLE_NAME, k.CONSTRAINT_NAME"
+
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-N", "-B", "-r",
+		"-e", query,
+	}
+}
+
+// BuildCopyCommand copies every table with CREATE TABLE LIKE and INSERT ...
+// SELECT in one client session. Foreign key checks are off, so the tables can
+// be filled in any order, and zeros in AUTO_INCREMENT columns are kept as
+// mysqldump keeps them.
+func (e *MySQLEngine) BuildCopyCommand(dsn *types.DSN, sourceDB, targetDB string, tables, statements []string) []string {
+	script := []string{
+		"SET SESSION FOREIGN_KEY_CHECKS = 0",
+		"SET SESSION sql_mode = 'NO_AUTO_VALUE_ON_ZERO'",
+	}
+	for _, table := range tables {
+		source := quoteMySQLIdent(sourceDB) + "." + quoteMySQLIdent(table)
+		target := quoteMySQLIdent(targetDB) + "." + quoteMySQLIdent(table)
+		script = append(script,
+			fmt.Sprintf("CREATE TABLE %s LIKE %s", target, source),
+			fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", target, source),
+		)
+	}
+	script = append(script, statements...)
+
+	return []string{
+		"mysql",
+		"-h", dsn.Host,
+		"-u", dsn.User,
+		"-e", strings.Join(script, "; "),
+	}
+}
+
+func quoteMySQLIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func quoteMySQLIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteMySQLIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quoteMySQLQualified quotes a table name that may be prefixed with its database
+func quoteMySQLQualified(name string) string {
+	if schema, table := splitQualified(name); schema != "" {
+		return quoteMySQLIdent(schema) + "." + quoteMySQLIdent(table)
+	}
+	return quoteMySQLIdent(name)
+}
+
+// quoteMySQLIdentExpr returns the SQL expression that quotes the identifier
+// held by the column expr
+func quoteMySQLIdentExpr(expr string) string {
+	return "CONCAT('`', REPLACE(" + expr + ", '`', '``'), '`')"
+}
+
+func quoteMySQLString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(s) + "'"
+}
+
+func (e *MySQLEngine) SystemDatabases() []string {
+	return []string{"information_schema", "mysql", "performance_schema", "sys"}
+}
+
+// DumpMarkers covers mysqldump and mariadb-dump, which both end with
+// "-- Dump completed on <date>" unless comments are turned off
+func (e *MySQLEngine) DumpMarkers() DumpMarkers {
+	return DumpMarkers{
+		Headers: []string{"-- MySQL dump", "-- MariaDB dump"},
+		Footer:  "-- Dump completed",
+	}
+}
+
+func (e *MySQLEngine) Dialect() sqldump.Dialect {
+	return sqldump.DialectMySQL
+}
+
 func (e *MySQLEngine) Name() string {
 	if e.isMariaDB {
 		return "MariaDB"
@@ -1,18 +1,21 @@
 package engines
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
-func TestMySQLEngine_BuildDumpCommand(t *testing.T) {
+func TestMySQLEngine_BuildDumpCommands(t *testing.T) {
 	tests := []struct {
 		name      string
 		isMariaDB bool
 		dsn       *types.DSN
-		tables    []string
-		expected  []string
+		spec      DumpSpec
+		expected  [][]string
 	}{
 		{
 			name:      "MySQL dump without tables",
@@ -23,10 +26,9 @@ func TestMySQLEngine_BuildDumpCommand(t *testing.T) {
 				Password: "secret",
 				Database: "app",
 			},
-			tables: nil,
-			expected: []string{
-				"mysqldump", "-h", "database", "-u", "root", "-psecret", "app",
-			},
+			expected: [][]string{{
+				"mysqldump", "-h", "database", "-u", "root", "app",
+			}},
 		},
 		{
 			name:      "MariaDB dump with tables",
@@ -37,39 +39,89 @@ func TestMySQLEngine_BuildDumpCommand(t *testing.T) {
 				Password: "pass",
 				Database: "test",
 			},
-			tables: []string{"users", "posts"},
-			expected: []string{
-				"mariadb-dump", "-h", "db", "-u", "user", "-ppass", "test", "users", "posts",
+			spec: DumpSpec{Tables: []string{"users", "posts"}},
+			expected: [][]string{{
+				"mariadb-dump", "-h", "db", "-u", "user", "test", "users", "posts",
+			}},
+		},
+		{
+			name: "exclude and structure-only tables",
+			dsn:  &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"},
+			spec: DumpSpec{ExcludeTables: []string{"messenger_messages"}, StructureOnly: []string{"audit_log"}},
+			expected: [][]string{
+				{"mysqldump", "-h", "database", "-u", "root", "--ignore-table=app.messenger_messages", "--ignore-table=app.audit_log", "app"},
+				{"mysqldump", "-h", "database", "-u", "root", "--no-data", "app", "audit_log"},
+			},
+		},
+		{
+			name: "structure-only table within a table list",
+			dsn:  &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"},
+			spec: DumpSpec{Tables: []string{"users", "audit_log"}, StructureOnly: []string{"audit_log", "other"}},
+			expected: [][]string{
+				{"mysqldump", "-h", "database", "-u", "root", "app", "users"},
+				{"mysqldump", "-h", "database", "-u", "root", "--no-data", "app", "audit_log"},
+			},
+		},
+		{
+			name: "schema only",
+			dsn:  &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"},
+			spec: DumpSpec{SchemaOnly: true, StructureOnly: []string{"audit_log"}},
+			expected: [][]string{
+				{"mysqldump", "-h", "database", "-u", "root", "--no-data", "app"},
+			},
+		},
+		{
+			name: "data only skips structure-only tables",
+			dsn:  &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"},
+			spec: DumpSpec{DataOnly: true, StructureOnly: []string{"audit_log"}},
+			expected: [][]string{
+				{"mysqldump", "-h", "database", "-u", "root", "--no-create-info", "--ignore-table=app.audit_log", "app"},
 			},
 		},
+		{
+			name:     "every listed table excluded",
+			dsn:      &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"},
+			spec:     DumpSpec{Tables: []string{"users"}, ExcludeTables: []string{"users"}},
+			expected: nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			engine := NewMySQLEngine(tt.isMariaDB)
-			result := engine.BuildDumpCommand(tt.dsn, tt.tables)
-
-			if len(result) != len(tt.expected) {
-				t.Errorf("expected %d args, got %d: %v", len(tt.expected), len(result), result)
-				return
-			}
+			result := engine.BuildDumpCommands(tt.dsn, tt.spec)
 
-			for i, v := range result {
-				if v != tt.expected[i] {
-					t.Errorf("arg[%d] = %q, want %q", i, v, tt.expected[i])
-				}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("got %v, want %v", result, tt.expected)
 			}
 		})
 	}
 }
 
+func TestMySQLEngine_Env(t *testing.T) {
+	engine := NewMySQLEngine(false)
+
+	env := engine.Env(&types.DSN{User: "root", Password: "secret"})
+	if !reflect.DeepEqual(env, []string{"MYSQL_PWD=secret"}) {
+		t.Errorf("unexpected env %v", env)
+	}
+
+	if env := engine.Env(&types.DSN{User: "root"}); env != nil {
+		t.Errorf("expected no env without a password, got %v", env)
+	}
+
+	if env := engine.PortEnv(&types.DSN{Port: "33061"}); !reflect.DeepEqual(env, []string{"MYSQL_TCP_PORT=33061"}) {
+		t.Errorf("unexpected port env %v", env)
+	}
+}
+
 func TestMySQLEngine_BuildCreateCommand(t *testing.T) {
 	engine := NewMySQLEngine(false)
 	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
 
 	result := engine.BuildCreateCommand(dsn, "new_db")
 
-	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-psecret", "-e", "CREATE DATABASE `new_db`"}
+	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-e", "CREATE DATABASE `new_db`"}
 
 	if len(result) != len(expected) {
 		t.Errorf("expected %d args, got %d", len(expected), len(result))
@@ -89,7 +141,7 @@ func TestMySQLEngine_BuildImportCommand(t *testing.T) {
 
 	result := engine.BuildImportCommand(dsn, "app_staging")
 
-	expected := []string{"mysql", "-h", "db", "-u", "root", "-psecret", "app_staging"}
+	expected := []string{"mysql", "-h", "db", "-u", "root", "app_staging"}
 
 	if len(result) != len(expected) {
 		t.Errorf("expected %d args, got %d", len(expected), len(result))
@@ -109,7 +161,7 @@ func TestMySQLEngine_BuildDropCommand(t *testing.T) {
 
 	result := engine.BuildDropCommand(dsn, "old_db")
 
-	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-psecret", "-e", "DROP DATABASE `old_db`"}
+	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-e", "DROP DATABASE `old_db`"}
 
 	if len(result) != len(expected) {
 		t.Errorf("expected %d args, got %d", len(expected), len(result))
@@ -145,7 +197,27 @@ func TestMySQLEngine_BuildListCommand(t *testing.T) {
 
 	result := engine.BuildListCommand(dsn)
 
-	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-psecret", "-e", "SHOW DATABASES"}
+	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-e", "SHOW DATABASES"}
+
+	if len(result) != len(expected) {
+		t.Errorf("expected %d args, got %d", len(expected), len(result))
+		return
+	}
+
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("arg[%d] = %q, want %q", i, v, expected[i])
+		}
+	}
+}
+
+func TestMySQLEngine_BuildListTablesCommand(t *testing.T) {
+	engine := NewMySQLEngine(false)
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	result := engine.BuildListTablesCommand(dsn, "app")
+
+	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-N", "-B", "-e", "SHOW TABLES", "app"}
 
 	if len(result) != len(expected) {
 		t.Errorf("expected %d args, got %d", len(expected), len(result))
@@ -158,3 +230,205 @@ func TestMySQLEngine_BuildListCommand(t *testing.T) {
 		}
 	}
 }
+
+func TestMySQLEngine_BuildTableStatsCommand(t *testing.T) {
+	engine := NewMySQLEngine(false)
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	result := engine.BuildTableStatsCommand(dsn, "app's", nil)
+
+	expected := []string{"mysql", "-h", "localhost", "-u", "root", "-N", "-B", "-r", "-e"}
+	if len(result) != len(expected)+1 {
+		t.Fatalf("expected %d args, got %d: %v", len(expected)+1, len(result), result)
+	}
+	for i, v := range expected {
+		if result[i] != v {
+			t.Errorf("arg[%d] = %q, want %q", i, result[i], v)
+		}
+	}
+
+	query := result[len(expected)]
+	for _, part := range []string{"FROM information_schema.TABLES", "TABLE_SCHEMA = 'app''s'", "TABLE_TYPE = 'BASE TABLE'"} {
+		if !strings.Contains(query, part) {
+			t.Errorf("query %q lacks %q", query, part)
+		}
+	}
+}
+
+func TestMySQLEngine_BuildSwapCommand(t *testing.T) {
+	engine := NewMySQLEngine(false)
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	result := engine.BuildSwapCommand(dsn, "app_haive_restore", "app", []string{"users"}, []string{"users", "posts"})
+
+	expectedSQL := "DROP DATABASE IF EXISTS `app_haive_old`; CREATE DATABASE `app_haive_old`; " +
+		"RENAME TABLE `app`.`users` TO `app_haive_old`.`users`, `app`.`posts` TO `app_haive_old`.`posts`, " +
+		"`app_haive_restore`.`users` TO `app`.`users`; " +
+		"DROP DATABASE `app_haive_old`; DROP DATABASE `app_haive_restore`"
+
+	if len(result) != 7 || result[5] != "-e" {
+		t.Fatalf("unexpected command %v", result)
+	}
+	if result[6] != expectedSQL {
+		t.Errorf("unexpected swap SQL:\n got %s\nwant %s", result[6], expectedSQL)
+	}
+}
+
+func TestMySQLEngine_BuildCopyCommand(t *testing.T) {
+	engine := NewMySQLEngine(false)
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	result := engine.BuildCopyCommand(dsn, "app", "app_copy", []string{"users", "posts"}, []string{"ALTER TABLE `app_copy`.`posts` ADD CONSTRAINT `fk`"})
+
+	expectedSQL := "SET SESSION FOREIGN_KEY_CHECKS = 0; SET SESSION sql_mode = 'NO_AUTO_VALUE_ON_ZERO'; " +
+		"CREATE TABLE `app_copy`.`users` LIKE `app`.`users`; INSERT INTO `app_copy`.`users` SELECT * FROM `app`.`users`; " +
+		"CREATE TABLE `app_copy`.`posts` LIKE `app`.`posts`; INSERT INTO `app_copy`.`posts` SELECT * FROM `app`.`posts`; " +
+		"ALTER TABLE `app_copy`.`posts` ADD CONSTRAINT `fk`"
+
+	if len(result) != 7 || result[5] != "-e" {
+		t.Fatalf("unexpected command %v", result)
+	}
+	if result[6] != expectedSQL {
+		t.Errorf("unexpected copy SQL:\n got %s\nwant %s", result[6], expectedSQL)
+	}
+}
+
+func TestMySQLEngine_CopyQueries(t *testing.T) {
+	engine := NewMySQLEngine(false)
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	check := engine.BuildCopyCheckCommand(dsn, "it's")
+	if len(check) != 10 || !strings.Contains(check[9], "TABLE_SCHEMA = 'it''s'") || !strings.Contains(check[9], "TRIGGER_SCHEMA = 'it''s'") {
+		t.Errorf("unexpected check command %v", check)
+	}
+
+	foreignKeys := engine.BuildForeignKeysCommand(dsn, "app", "app`copy")
+	if len(foreignKeys) != 10 {
+		t.Fatalf("unexpected foreign keys command %v", foreignKeys)
+	}
+	for _, want := range []string{"'ALTER TABLE ', '`app``copy`'", "IF(k.REFERENCED_TABLE_SCHEMA = 'app', '`app``copy`'", "WHERE k.TABLE_SCHEMA = 'app'"} {
+		if !strings.Contains(foreignKeys[9], want) {
+			t.Errorf("expected %q in the foreign keys query, got %s", want, foreignKeys[9])
+		}
+	}
+
+	if engine.BuildTemplateCommand(dsn, "app", "app_copy") != nil {
+		t.Error("expected no template command")
+	}
+}
+
+func TestMySQLEngine_DumpMarkers(t *testing.T) {
+	markers := NewMySQLEngine(true).DumpMarkers()
+
+	for _, head := range []string{
+		"-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)\n--\n",
+		"/*M!999999\\- enable the sandbox mode */ \n-- MariaDB dump 10.19-11.4.2-MariaDB, for debian-linux-gnu (x86_64)\n",
+	} {
+		if !markers.IsDump([]byte(head)) {
+			t.Errorf("IsDump(%q) = false", head)
+		}
+	}
+
+	if !markers.Complete([]byte("UNLOCK TABLES;\n\n-- Dump completed on 2024-05-01 10:00:00\n")) {
+		t.Error("Complete() = false for a finished dump")
+	}
+	if markers.Complete([]byte("INSERT INTO `users` VALUES (1,'a'),(2,")) {
+		t.Error("Complete() = true for a truncated dump")
+	}
+}
+
+func TestMySQLEngine_BuildSchemaCommand(t *testing.T) {
+	engine := NewMySQLEngine(false)
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	result := engine.BuildSchemaCommand(dsn, "app's")
+
+	if len(result) != 10 || result[8] != "-e" {
+		t.Fatalf("unexpected command %v", result)
+	}
+	query := result[9]
+	for _, part := range []string{
+		"FROM information_schema.COLUMNS",
+		"FROM information_schema.STATISTICS",
+		"FROM information_schema.KEY_COLUMN_USAGE",
+		"TABLE_SCHEMA = 'app''s'",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("query %q lacks %q", query, part)
+		}
+	}
+}
+
+func TestMySQLEngine_BuildQueryCommand(t *testing.T) {
+	dsn := &types.DSN{Host: "localhost", User: "root", Password: "secret"}
+
+	tests := []struct {
+		name      string
+		isMariaDB bool
+		timeout   time.Duration
+		expected  string
+	}{
+		{"mysql", false, 30 * time.Second, "SET SESSION sql_select_limit = 11; SET SESSION max_execution_time = 30000; START TRANSACTION READ ONLY; SELECT 1; ROLLBACK"},
+		{"mariadb", true, 1500 * time.Millisecond, "SET SESSION sql_select_limit = 11; SET SESSION max_statement_time = 1.5; START TRANSACTION READ ONLY; SELECT 1; ROLLBACK"},
+		{"no timeout", false, 0, "SET SESSION sql_select_limit = 11; START TRANSACTION READ ONLY; SELECT 1; ROLLBACK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewMySQLEngine(tt.isMariaDB).BuildQueryCommand(dsn, "app_feature", "SELECT 1", 10, tt.timeout)
+			expected := []string{"mysql", "-h", "localhost", "-u", "root", "-B", "-e", tt.expected, "app_feature"}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("BuildQueryCommand() = %v, want %v", result, expected)
+			}
+		})
+	}
+
+	if format := NewMySQLEngine(false).QueryFormat(); format != QueryFormatTSV {
+		t.Errorf("expected TSV output, got %v", format)
+	}
+}
+
+func TestMySQLEngine_BuildAlterStatements(t *testing.T) {
+	defaultNew := "new"
+	defaultNow := "CURRENT_TIMESTAMP"
+	userID := types.ColumnSchema{Name: "user_id", Type: "int"}
+	oldEmail := types.IndexSchema{Name: "uniq_email", Unique: true, Columns: []string{"email"}}
+	newEmail := types.IndexSchema{Name: "uniq_email", Unique: true, Columns: []string{"email(100)", "status"}}
+	fk := types.ForeignKeySchema{Name: "fk_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE"}
+
+	changes := []types.SchemaChange{
+		{Kind: types.SchemaAddTable, Table: "posts", NewTable: &types.TableSchema{
+			Name:        "posts",
+			Columns:     []types.ColumnSchema{{Name: "id", Type: "int", Extra: "auto_increment"}, userID},
+			Indexes:     []types.IndexSchema{{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id"}}},
+			ForeignKeys: []types.ForeignKeySchema{fk},
+		}},
+		{Kind: types.SchemaDropTable, Table: "legacy"},
+		{Kind: types.SchemaAddColumn, Table: "users", Name: "status", NewColumn: &types.ColumnSchema{Name: "status", Type: "varchar(20)", Default: &defaultNew}},
+		{Kind: types.SchemaModifyColumn, Table: "users", Name: "created_at", NewColumn: &types.ColumnSchema{Name: "created_at", Type: "datetime", Default: &defaultNow, Extra: "DEFAULT_GENERATED"}},
+		{Kind: types.SchemaAddColumn, Table: "users", Name: "slug", NewColumn: &types.ColumnSchema{Name: "slug", Type: "varchar(20)", Nullable: true, Extra: "VIRTUAL GENERATED"}},
+		{Kind: types.SchemaModifyIndex, Table: "users", Name: "uniq_email", OldIndex: &oldEmail, NewIndex: &newEmail},
+		{Kind: types.SchemaDropForeignKey, Table: "comments", Name: "fk_post"},
+	}
+
+	expected := []string{
+		"ALTER TABLE `comments` DROP FOREIGN KEY `fk_post`",
+		"DROP TABLE `legacy`",
+		"CREATE TABLE `posts` (\n  `id` int NOT NULL auto_increment,\n  `user_id` int NOT NULL,\n  PRIMARY KEY (`id`)\n)",
+		"ALTER TABLE `users` ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'new'",
+		"-- add_column users.slug: MySQL can't alter this in place, rebuild the table",
+		"ALTER TABLE `users` MODIFY COLUMN `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP",
+		"ALTER TABLE `users` DROP INDEX `uniq_email`, ADD UNIQUE INDEX `uniq_email` (`email`(100), `status`)",
+		"ALTER TABLE `posts` ADD CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE",
+	}
+
+	result := NewMySQLEngine(false).BuildAlterStatements(changes)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("BuildAlterStatements() =\n%s\nwant\n%s", strings.Join(result, "\n"), strings.Join(expected, "\n"))
+	}
+
+	mariaDB := NewMySQLEngine(true).BuildAlterStatements([]types.SchemaChange{changes[2]})
+	if len(mariaDB) != 1 || !strings.HasSuffix(mariaDB[0], "DEFAULT new") {
+		t.Errorf("expected MariaDB defaults to be used as reported, got %v", mariaDB)
+	}
+}
@@ -0,0 +1,488 @@
+package engines
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+type PostgresEngine struct{}
+
+func NewPostgresEngine() *PostgresEngine {
+	return &PostgresEngine{}
+}
+
+// Env supplies the password through PGPASSWORD, since the libpq client tools
+// have no command line flag for it
+func (e *PostgresEngine) Env(dsn *types.DSN) []string {
+	if dsn.Password == "" {
+		return nil
+	}
+	return []string{"PGPASSWORD=" + dsn.Password}
+}
+
+func (e *PostgresEngine) PortEnv(dsn *types.DSN) []string {
+	if dsn.Port == "" {
+		return nil
+	}
+	return []string{"PGPORT=" + dsn.Port}
+}
+
+// BuildDumpCommands translates the spec into a single pg_dump run, which
+// supports skipping the rows of single tables natively
+func (e *PostgresEngine) BuildDumpCommands(dsn *types.DSN, spec DumpSpec) [][]string {
+	cmd := []string{
+		"pg_dump",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"--no-owner",
+	}
+
+	if spec.SchemaOnly {
+		cmd = append(cmd, "--schema-only")
+	}
+	if spec.DataOnly {
+		cmd = append(cmd, "--data-only")
+	}
+
+	tables := without(spec.Tables, spec.ExcludeTables)
+	if len(spec.Tables) > 0 && len(tables) == 0 {
+		return nil
+	}
+
+	for _, table := range tables {
+		cmd = append(cmd, "-t", table)
+	}
+	for _, table := range spec.ExcludeTables {
+		cmd = append(cmd, "-T", table)
+	}
+	if !spec.SchemaOnly {
+		for _, table := range spec.StructureOnly {
+			cmd = append(cmd, "--exclude-table-data="+table)
+		}
+	}
+
+	return [][]string{append(cmd, dsn.Database)}
+}
+
+func (e *PostgresEngine) BuildCreateCommand(dsn *types.DSN, dbName string) []string {
+	return []string{
+		"createdb",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		dbName,
+	}
+}
+
+func (e *PostgresEngine) BuildImportCommand(dsn *types.DSN, dbName string) []string {
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-v", "ON_ERROR_STOP=1",
+		"-q",
+		"-d", dbName,
+	}
+}
+
+func (e *PostgresEngine) BuildDropCommand(dsn *types.DSN, dbName string) []string {
+	return []string{
+		"dropdb",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		dbName,
+	}
+}
+
+func (e *PostgresEngine) BuildListCommand(dsn *types.DSN) []string {
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", "postgres",
+		"-At",
+		"-c", "SELECT datname FROM pg_database ORDER BY datname",
+	}
+}
+
+func (e *PostgresEngine) BuildVersionCommand(dsn *types.DSN) []string {
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", "postgres",
+		"-At",
+		"-c", "SHOW server_version",
+	}
+}
+
+// BuildListTablesCommand lists user tables, schema-qualified outside of public
+func (e *PostgresEngine) BuildListTablesCommand(dsn *types.DSN, dbName string) []string {
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", dbName,
+		"-At",
+		"-c", "SELECT CASE WHEN schemaname = 'public' THEN tablename ELSE schemaname || '.' || tablename END " +
+			"FROM pg_tables WHERE schemaname NOT IN ('pg_catalog', 'information_schema') ORDER BY 1",
+	}
+}
+
+// BuildTableStatsCommand reads the live row estimates of the statistics
+// collector and the relation sizes. PostgreSQL doesn't record when a table
+// last changed, so that column is always 0.
+func (e *PostgresEngine) BuildTableStatsCommand(dsn *types.DSN, dbName string, tables []string) []string {
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", dbName,
+		"-At", "-F", "\t",
+		"-c", "SELECT CASE WHEN schemaname = 'public' THEN relname ELSE schemaname || '.' || relname END, " +
+			"n_live_tup, pg_table_size(relid), pg_indexes_size(relid), 0 " +
+			"FROM pg_stat_user_tables ORDER BY 1",
+	}
+}
+
+// pgTableName is the expression naming the table of class c in namespace n,
+// schema-qualified outside of public like BuildListTablesCommand
+func pgTableName(c, n string) string {
+	return "CASE WHEN " + n + ".nspname = 'public' THEN " + c + ".relname ELSE " + n + ".nspname || '.' || " + c + ".relname END"
+}
+
+// pgUserNamespace filters out the system schemas in namespace n
+func pgUserNamespace(n string) string {
+	return n + ".nspname <> 'information_schema' AND " + n + ".nspname !~ '^pg_'"
+}
+
+// pgReferentialAction names the ON DELETE or ON UPDATE action code in column
+func pgReferentialAction(column string) string {
+	return "CASE " + column + " WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' " +
+		"WHEN 'd' THEN 'SET DEFAULT' ELSE 'NO ACTION' END"
+}
+
+// pgConstraintColumns is the comma separated list of the columns of rel
+// numbered in the array keys, in their order
+func pgConstraintColumns(keys, rel string) string {
+	return "array_to_string(ARRAY(SELECT a.attname FROM unnest(" + keys + ") WITH ORDINALITY k(attnum, ord) " +
+		"JOIN pg_attribute a ON a.attrelid = " + rel + " AND a.attnum = k.attnum ORDER BY k.ord), ',')"
+}
+
+// BuildSchemaCommand reads pg_catalog. Index columns come from
+// pg_get_indexdef, quoted where needed and possibly expressions.
+func (e *PostgresEngine) BuildSchemaCommand(dsn *types.DSN, dbName string) []string {
+	columns := "SELECT 'column', " + pgTableName("c", "n") + ", a.attname, format_type(a.atttypid, a.atttypmod), " +
+		"CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END, CASE WHEN d.adbin IS NULL THEN 0 ELSE 1 END, " +
+		"COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), " +
+		"CASE a.attidentity WHEN 'a' THEN 'GENERATED ALWAYS AS IDENTITY' WHEN 'd' THEN 'GENERATED BY DEFAULT AS IDENTITY' ELSE '' END " +
+		"FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum " +
+		"WHERE c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped AND " + pgUserNamespace("n") + " " +
+		"ORDER BY 2, a.attnum"
+
+	indexes := "SELECT 'index', " + pgTableName("c", "n") + ", i.relname, " +
+		"CASE WHEN x.indisprimary THEN 'primary' WHEN x.indisunique THEN 'unique' ELSE '' END, " +
+		"array_to_string(ARRAY(SELECT pg_get_indexdef(x.indexrelid, k, true) FROM generate_series(1, x.indnatts) k ORDER BY k), ','), " +
+		"am.amname, " +
+		"CASE WHEN EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = x.indexrelid AND con.conrelid = x.indrelid " +
+		"AND con.contype IN ('p', 'u')) THEN 'constraint' ELSE '' END, '' " +
+		"FROM pg_index x JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_am am ON am.oid = i.relam " +
+		"JOIN pg_class c ON c.oid = x.indrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"WHERE c.relkind IN ('r', 'p') AND " + pgUserNamespace("n") + " ORDER BY 2, 3"
+
+	foreignKeys := "SELECT 'foreign_key', " + pgTableName("c", "n") + ", con.conname, " +
+		pgConstraintColumns("con.conkey", "con.conrelid") + ", " +
+		pgTableName("rc", "rn") + ", " +
+		pgConstraintColumns("con.confkey", "con.confrelid") + ", " +
+		pgReferentialAction("con.confdeltype") + ", " + pgReferentialAction("con.confupdtype") + " " +
+		"FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"JOIN pg_class rc ON rc.oid = con.confrelid JOIN pg_namespace rn ON rn.oid = rc.relnamespace " +
+		"WHERE con.contype = 'f' AND " + pgUserNamespace("n") + " ORDER BY 2, 3"
+
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", dbName,
+		"-v", "ON_ERROR_STOP=1",
+		"-At", "-F", "\t",
+		"-c", columns,
+		"-c", indexes,
+		"-c", foreignKeys,
+	}
+}
+
+// BuildAlterStatements writes ALTER TABLE statements, and CREATE and DROP
+// INDEX for indexes that don't back a constraint. Sequences behind nextval()
+// defaults are not created.
+func (e *PostgresEngine) BuildAlterStatements(changes []types.SchemaChange) []string {
+	var script alterScript
+	for _, change := range changes {
+		table := quotePostgresQualified(change.Table)
+		switch change.Kind {
+		case types.SchemaAddTable:
+			var lines []string
+			for i := range change.NewTable.Columns {
+				lines = append(lines, "  "+postgresColumnDefinition(&change.NewTable.Columns[i]))
+			}
+			for i := range change.NewTable.Indexes {
+				index := &change.NewTable.Indexes[i]
+				if index.Primary || index.Constraint {
+					lines = append(lines, "  "+postgresConstraintDefinition(index))
+				} else {
+					script.add(phaseAddIndex, postgresCreateIndex(change.Table, index))
+				}
+			}
+			script.add(phaseCreateTable, "CREATE TABLE "+table+" (\n"+strings.Join(lines, ",\n")+"\n)")
+			for i := range change.NewTable.ForeignKeys {
+				script.add(phaseAddForeignKey, "ALTER TABLE "+table+" ADD "+postgresForeignKeyDefinition(&change.NewTable.ForeignKeys[i]))
+			}
+		case types.SchemaDropTable:
+			script.add(phaseDropTable, "DROP TABLE "+table)
+		case types.SchemaAddColumn:
+			script.add(phaseAddColumn, "ALTER TABLE "+table+" ADD COLUMN "+postgresColumnDefinition(change.NewColumn))
+		case types.SchemaDropColumn:
+			script.add(phaseDropColumn, "ALTER TABLE "+table+" DROP COLUMN "+quotePostgresIdent(change.Name))
+		case types.SchemaModifyColumn:
+			script.add(phaseModifyColumn, "ALTER TABLE "+table+" "+postgresAlterColumn(change.OldColumn, change.NewColumn))
+		case types.SchemaAddIndex:
+			script.add(phaseAddIndex, postgresAddIndex(change.Table, change.NewIndex))
+		case types.SchemaDropIndex:
+			script.add(phaseDropIndex, postgresDropIndex(change.Table, change.OldIndex))
+		case types.SchemaModifyIndex:
+			script.add(phaseDropIndex, postgresDropIndex(change.Table, change.OldIndex))
+			script.add(phaseAddIndex, postgresAddIndex(change.Table, change.NewIndex))
+		case types.SchemaAddForeignKey:
+			script.add(phaseAddForeignKey, "ALTER TABLE "+table+" ADD "+postgresForeignKeyDefinition(change.NewForeignKey))
+		case types.SchemaDropForeignKey:
+			script.add(phaseDropForeignKey, "ALTER TABLE "+table+" DROP CONSTRAINT "+quotePostgresIdent(change.Name))
+		case types.SchemaModifyForeignKey:
+			script.add(phaseDropForeignKey, "ALTER TABLE "+table+" DROP CONSTRAINT "+quotePostgresIdent(change.Name))
+			script.add(phaseAddForeignKey, "ALTER TABLE "+table+" ADD "+postgresForeignKeyDefinition(change.NewForeignKey))
+		}
+	}
+	return script.statements()
+}
+
+func postgresColumnDefinition(c *types.ColumnSchema) string {
+	definition := quotePostgresIdent(c.Name) + " " + c.Type
+	if !c.Nullable {
+		definition += " NOT NULL"
+	}
+	if c.Default != nil {
+		definition += " DEFAULT " + *c.Default
+	}
+	if c.Extra != "" {
+		definition += " " + c.Extra
+	}
+	return definition
+}
+
+// postgresAlterColumn sets the type, nullability and default of a column,
+// whichever differ
+func postgresAlterColumn(oldColumn, newColumn *types.ColumnSchema) string {
+	column := "ALTER COLUMN " + quotePostgresIdent(newColumn.Name)
+	var actions []string
+	if oldColumn.Type != newColumn.Type {
+		actions = append(actions, column+" TYPE "+newColumn.Type)
+	}
+	if oldColumn.Nullable != newColumn.Nullable {
+		if newColumn.Nullable {
+			actions = append(actions, column+" DROP NOT NULL")
+		} else {
+			actions = append(actions, column+" SET NOT NULL")
+		}
+	}
+	switch {
+	case newColumn.Default == nil && oldColumn.Default != nil:
+		actions = append(actions, column+" DROP DEFAULT")
+	case newColumn.Default != nil && (oldColumn.Default == nil || *oldColumn.Default != *newColumn.Default):
+		actions = append(actions, column+" SET DEFAULT "+*newColumn.Default)
+	}
+	if oldColumn.Extra != newColumn.Extra {
+		if newColumn.Extra == "" {
+			actions = append(actions, column+" DROP IDENTITY IF EXISTS")
+		} else {
+			actions = append(actions, column+" ADD "+newColumn.Extra)
+		}
+	}
+	return strings.Join(actions, ", ")
+}
+
+// postgresConstraintDefinition writes the primary key or unique constraint an
+// index backs
+func postgresConstraintDefinition(i *types.IndexSchema) string {
+	kind := "UNIQUE"
+	if i.Primary {
+		kind = "PRIMARY KEY"
+	}
+	return "CONSTRAINT " + quotePostgresIdent(i.Name) + " " + kind + " (" + strings.Join(i.Columns, ", ") + ")"
+}
+
+func postgresCreateIndex(table string, i *types.IndexSchema) string {
+	statement := "CREATE INDEX "
+	if i.Unique {
+		statement = "CREATE UNIQUE INDEX "
+	}
+	statement += quotePostgresIdent(i.Name) + " ON " + quotePostgresQualified(table)
+	if i.Method != "" && i.Method != "btree" {
+		statement += " USING " + i.Method
+	}
+	return statement + " (" + strings.Join(i.Columns, ", ") + ")"
+}
+
+func postgresAddIndex(table string, i *types.IndexSchema) string {
+	if i.Primary || i.Constraint {
+		return "ALTER TABLE " + quotePostgresQualified(table) + " ADD " + postgresConstraintDefinition(i)
+	}
+	return postgresCreateIndex(table, i)
+}
+
+// postgresDropIndex drops an index, which lives in the schema of its table,
+// or the constraint it backs
+func postgresDropIndex(table string, i *types.IndexSchema) string {
+	if i.Primary || i.Constraint {
+		return "ALTER TABLE " + quotePostgresQualified(table) + " DROP CONSTRAINT " + quotePostgresIdent(i.Name)
+	}
+	if schema, _ := splitQualified(table); schema != "" {
+		return "DROP INDEX " + quotePostgresIdent(schema) + "." + quotePostgresIdent(i.Name)
+	}
+	return "DROP INDEX " + quotePostgresIdent(i.Name)
+}
+
+func postgresForeignKeyDefinition(k *types.ForeignKeySchema) string {
+	definition := "CONSTRAINT " + quotePostgresIdent(k.Name) +
+		" FOREIGN KEY (" + quotePostgresIdents(k.Columns) + ")" +
+		" REFERENCES " + quotePostgresQualified(k.ReferencedTable) + " (" + quotePostgresIdents(k.ReferencedColumns) + ")"
+	if k.OnDelete != "" {
+		definition += " ON DELETE " + k.OnDelete
+	}
+	if k.OnUpdate != "" {
+		definition += " ON UPDATE " + k.OnUpdate
+	}
+	return definition
+}
+
+// BuildQueryCommand runs query in a READ ONLY transaction with a local
+// statement_timeout. A SELECT is read through a cursor, fetching maxRows+1
+// rows; other statements return all of theirs. Every -c runs in the same
+// session, and -q keeps the command tags out of the CSV.
+func (e *PostgresEngine) BuildQueryCommand(dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) []string {
+	cmd := []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", dbName,
+		"-v", "ON_ERROR_STOP=1",
+		"-q", "--csv", "-P", `null=\N`,
+		"-c", "BEGIN READ ONLY",
+	}
+	if timeout > 0 {
+		cmd = append(cmd, "-c", fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds()))
+	}
+
+	switch firstKeyword(query) {
+	case "SELECT", "WITH":
+		cmd = append(cmd,
+			"-c", "DECLARE haive_query NO SCROLL CURSOR FOR "+query,
+			"-c", fmt.Sprintf("FETCH FORWARD %d FROM haive_query", maxRows+1),
+		)
+	default:
+		cmd = append(cmd, "-c", query)
+	}
+	return append(cmd, "-c", "ROLLBACK")
+}
+
+func (e *PostgresEngine) QueryFormat() QueryFormat {
+	return QueryFormatCSV
+}
+
+// BuildSwapCommand renames both databases in one transaction, so targetDB is
+// never missing, and drops the replaced data afterwards. Table lists are unused.
+func (e *PostgresEngine) BuildSwapCommand(dsn *types.DSN, sourceDB, targetDB string, sourceTables, targetTables []string) []string {
+	backupDB := targetDB + SwapBackupSuffix
+
+	return []string{
+		"psql",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-d", "postgres",
+		"-v", "ON_ERROR_STOP=1",
+		"-q",
+		"-c", fmt.Sprintf("DROP DATABASE IF EXISTS %s", quotePostgresIdent(backupDB)),
+		"-c", fmt.Sprintf("ALTER DATABASE %s RENAME TO %s; ALTER DATABASE %s RENAME TO %s",
+			quotePostgresIdent(targetDB), quotePostgresIdent(backupDB),
+			quotePostgresIdent(sourceDB), quotePostgresIdent(targetDB)),
+		"-c", fmt.Sprintf("DROP DATABASE %s", quotePostgresIdent(backupDB)),
+	}
+}
+
+// BuildTemplateCommand creates targetDB from sourceDB as its template, which
+// copies the files of the database on the server. The server refuses while
+// other sessions are connected to sourceDB.
+func (e *PostgresEngine) BuildTemplateCommand(dsn *types.DSN, sourceDB, targetDB string) []string {
+	return []string{
+		"createdb",
+		"-h", dsn.Host,
+		"-U", dsn.User,
+		"-T", sourceDB,
+		targetDB,
+	}
+}
+
+// BuildCopyCheckCommand returns nil, templates are the faster way to copy a
+// whole PostgreSQL database
+func (e *PostgresEngine) BuildCopyCheckCommand(dsn *types.DSN, sourceDB string) []string {
+	return nil
+}
+
+func (e *PostgresEngine) BuildForeignKeysCommand(dsn *types.DSN, sourceDB, targetDB string) []string {
+	return nil
+}
+
+func (e *PostgresEngine) BuildCopyCommand(dsn *types.DSN, sourceDB, targetDB string, tables, statements []string) []string {
+	return nil
+}
+
+func quotePostgresIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quotePostgresIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quotePostgresIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quotePostgresQualified quotes a table name that may be prefixed with its schema
+func quotePostgresQualified(name string) string {
+	if schema, table := splitQualified(name); schema != "" {
+		return quotePostgresIdent(schema) + "." + quotePostgresIdent(table)
+	}
+	return quotePostgresIdent(name)
+}
+
+func (e *PostgresEngine) SystemDatabases() []string {
+	return []string{"template0", "template1", "postgres"}
+}
+
+func (e *PostgresEngine) DumpMarkers() DumpMarkers {
+	return DumpMarkers{
+		Headers: []string{"-- PostgreSQL database dump"},
+		Footer:  "-- PostgreSQL database dump complete",
+	}
+}
+
+func (e *PostgresEngine) Dialect() sqldump.Dialect {
+	return sqldump.DialectPostgres
+}
+
+func (e *PostgresEngine) Name() string {
+	return "PostgreSQL"
+}
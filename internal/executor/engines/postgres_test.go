@@ -0,0 +1,290 @@
+package engines
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func TestPostgresEngine_BuildDumpCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		dsn      *types.DSN
+		spec     DumpSpec
+		expected [][]string
+	}{
+		{
+			name: "dump without tables",
+			dsn: &types.DSN{
+				Host:     "database",
+				User:     "app",
+				Password: "secret",
+				Database: "app",
+			},
+			expected: [][]string{{
+				"pg_dump", "-h", "database", "-U", "app", "--no-owner", "app",
+			}},
+		},
+		{
+			name: "dump with tables",
+			dsn: &types.DSN{
+				Host:     "db",
+				User:     "user",
+				Password: "pass",
+				Database: "test",
+			},
+			spec: DumpSpec{Tables: []string{"users", "posts"}},
+			expected: [][]string{{
+				"pg_dump", "-h", "db", "-U", "user", "--no-owner", "-t", "users", "-t", "posts", "test",
+			}},
+		},
+		{
+			name: "exclude and structure-only tables",
+			dsn:  &types.DSN{Host: "db", User: "user", Password: "pass", Database: "test"},
+			spec: DumpSpec{ExcludeTables: []string{"messenger_messages"}, StructureOnly: []string{"audit_log"}},
+			expected: [][]string{{
+				"pg_dump", "-h", "db", "-U", "user", "--no-owner", "-T", "messenger_messages", "--exclude-table-data=audit_log", "test",
+			}},
+		},
+		{
+			name: "schema only",
+			dsn:  &types.DSN{Host: "db", User: "user", Password: "pass", Database: "test"},
+			spec: DumpSpec{SchemaOnly: true, StructureOnly: []string{"audit_log"}},
+			expected: [][]string{{
+				"pg_dump", "-h", "db", "-U", "user", "--no-owner", "--schema-only", "test",
+			}},
+		},
+		{
+			name: "data only",
+			dsn:  &types.DSN{Host: "db", User: "user", Password: "pass", Database: "test"},
+			spec: DumpSpec{DataOnly: true},
+			expected: [][]string{{
+				"pg_dump", "-h", "db", "-U", "user", "--no-owner", "--data-only", "test",
+			}},
+		},
+		{
+			name:     "every listed table excluded",
+			dsn:      &types.DSN{Host: "db", User: "user", Password: "pass", Database: "test"},
+			spec:     DumpSpec{Tables: []string{"users"}, ExcludeTables: []string{"users"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewPostgresEngine()
+			result := engine.BuildDumpCommands(tt.dsn, tt.spec)
+
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("got %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostgresEngine_Commands(t *testing.T) {
+	engine := NewPostgresEngine()
+	dsn := &types.DSN{Host: "database", User: "app", Password: "secret"}
+
+	tests := []struct {
+		name     string
+		result   []string
+		expected []string
+	}{
+		{
+			name:     "create",
+			result:   engine.BuildCreateCommand(dsn, "new_db"),
+			expected: []string{"createdb", "-h", "database", "-U", "app", "new_db"},
+		},
+		{
+			name:     "import",
+			result:   engine.BuildImportCommand(dsn, "app_staging"),
+			expected: []string{"psql", "-h", "database", "-U", "app", "-v", "ON_ERROR_STOP=1", "-q", "-d", "app_staging"},
+		},
+		{
+			name:     "drop",
+			result:   engine.BuildDropCommand(dsn, "old_db"),
+			expected: []string{"dropdb", "-h", "database", "-U", "app", "old_db"},
+		},
+		{
+			name:     "list",
+			result:   engine.BuildListCommand(dsn),
+			expected: []string{"psql", "-h", "database", "-U", "app", "-d", "postgres", "-At", "-c", "SELECT datname FROM pg_database ORDER BY datname"},
+		},
+		{
+			name:     "version",
+			result:   engine.BuildVersionCommand(dsn),
+			expected: []string{"psql", "-h", "database", "-U", "app", "-d", "postgres", "-At", "-c", "SHOW server_version"},
+		},
+		{
+			name:   "list tables",
+			result: engine.BuildListTablesCommand(dsn, "app"),
+			expected: []string{"psql", "-h", "database", "-U", "app", "-d", "app", "-At", "-c",
+				"SELECT CASE WHEN schemaname = 'public' THEN tablename ELSE schemaname || '.' || tablename END " +
+					"FROM pg_tables WHERE schemaname NOT IN ('pg_catalog', 'information_schema') ORDER BY 1"},
+		},
+		{
+			name:   "table stats",
+			result: engine.BuildTableStatsCommand(dsn, "app", nil),
+			expected: []string{"psql", "-h", "database", "-U", "app", "-d", "app", "-At", "-F", "\t", "-c",
+				"SELECT CASE WHEN schemaname = 'public' THEN relname ELSE schemaname || '.' || relname END, " +
+					"n_live_tup, pg_table_size(relid), pg_indexes_size(relid), 0 " +
+					"FROM pg_stat_user_tables ORDER BY 1"},
+		},
+		{
+			name:     "template",
+			result:   engine.BuildTemplateCommand(dsn, "app", "app_copy"),
+			expected: []string{"createdb", "-h", "database", "-U", "app", "-T", "app", "app_copy"},
+		},
+		{
+			name:   "swap",
+			result: engine.BuildSwapCommand(dsn, "app_haive_restore", "app", nil, nil),
+			expected: []string{"psql", "-h", "database", "-U", "app", "-d", "postgres", "-v", "ON_ERROR_STOP=1", "-q",
+				"-c", `DROP DATABASE IF EXISTS "app_haive_old"`,
+				"-c", `ALTER DATABASE "app" RENAME TO "app_haive_old"; ALTER DATABASE "app_haive_restore" RENAME TO "app"`,
+				"-c", `DROP DATABASE "app_haive_old"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.result) != len(tt.expected) {
+				t.Errorf("expected %d args, got %d: %v", len(tt.expected), len(tt.result), tt.result)
+				return
+			}
+
+			for i, v := range tt.result {
+				if v != tt.expected[i] {
+					t.Errorf("arg[%d] = %q, want %q", i, v, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresEngine_Env(t *testing.T) {
+	engine := NewPostgresEngine()
+
+	env := engine.Env(&types.DSN{User: "app", Password: "secret"})
+	if !reflect.DeepEqual(env, []string{"PGPASSWORD=secret"}) {
+		t.Errorf("unexpected env %v", env)
+	}
+
+	if env := engine.Env(&types.DSN{User: "app"}); env != nil {
+		t.Errorf("expected no env without a password, got %v", env)
+	}
+
+	if env := engine.PortEnv(&types.DSN{Port: "5433"}); !reflect.DeepEqual(env, []string{"PGPORT=5433"}) {
+		t.Errorf("unexpected port env %v", env)
+	}
+}
+
+func TestPostgresEngine_Name(t *testing.T) {
+	engine := NewPostgresEngine()
+	if engine.Name() != "PostgreSQL" {
+		t.Errorf("expected PostgreSQL, got %s", engine.Name())
+	}
+}
+
+func TestPostgresEngine_DumpMarkers(t *testing.T) {
+	markers := NewPostgresEngine().DumpMarkers()
+
+	head := []byte("--\n-- PostgreSQL database dump\n--\n\nSET statement_timeout = 0;\n")
+	if !markers.IsDump(head) {
+		t.Error("IsDump() = false for pg_dump output")
+	}
+	if markers.IsDump([]byte("CREATE TABLE t (id int);\n")) {
+		t.Error("IsDump() = true for a hand-written file")
+	}
+
+	complete := []byte("ALTER TABLE t OWNER TO app;\n\n--\n-- PostgreSQL database dump complete\n--\n\n")
+	if !markers.Complete(complete) {
+		t.Error("Complete() = false for a finished dump")
+	}
+	if markers.Complete(head) {
+		t.Error("Complete() = true for a dump without footer")
+	}
+}
+
+func TestPostgresEngine_BuildQueryCommand(t *testing.T) {
+	engine := NewPostgresEngine()
+	dsn := &types.DSN{Host: "localhost", User: "app", Password: "secret"}
+	prefix := []string{"psql", "-h", "localhost", "-U", "app", "-d", "app_feature", "-v", "ON_ERROR_STOP=1", "-q", "--csv", "-P", `null=\N`, "-c", "BEGIN READ ONLY"}
+
+	tests := []struct {
+		name     string
+		query    string
+		timeout  time.Duration
+		expected []string
+	}{
+		{
+			name:    "select reads through a cursor",
+			query:   "with t as (select 1) select * from t",
+			timeout: 5 * time.Second,
+			expected: []string{
+				"-c", "SET LOCAL statement_timeout = 5000",
+				"-c", "DECLARE haive_query NO SCROLL CURSOR FOR with t as (select 1) select * from t",
+				"-c", "FETCH FORWARD 11 FROM haive_query",
+				"-c", "ROLLBACK",
+			},
+		},
+		{
+			name:     "explain runs as is",
+			query:    "EXPLAIN SELECT 1",
+			expected: []string{"-c", "EXPLAIN SELECT 1", "-c", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.BuildQueryCommand(dsn, "app_feature", tt.query, 10, tt.timeout)
+			expected := append(append([]string{}, prefix...), tt.expected...)
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("BuildQueryCommand() = %v, want %v", result, expected)
+			}
+		})
+	}
+
+	if format := engine.QueryFormat(); format != QueryFormatCSV {
+		t.Errorf("expected CSV output, got %v", format)
+	}
+}
+
+func TestPostgresEngine_BuildAlterStatements(t *testing.T) {
+	zero := "0"
+	oldStatus := types.ColumnSchema{Name: "status", Type: "character varying(20)", Nullable: true, Default: &zero}
+	newStatus := types.ColumnSchema{Name: "status", Type: "text"}
+	oldSlug := types.IndexSchema{Name: "posts_slug_idx", Columns: []string{"slug"}, Method: "btree"}
+	newSlug := types.IndexSchema{Name: "posts_slug_idx", Unique: true, Columns: []string{"lower(slug)"}, Method: "btree"}
+	email := types.IndexSchema{Name: "users_email_key", Unique: true, Constraint: true, Columns: []string{"email"}, Method: "btree"}
+
+	changes := []types.SchemaChange{
+		{Kind: types.SchemaAddTable, Table: "audit.events", NewTable: &types.TableSchema{
+			Name:    "audit.events",
+			Columns: []types.ColumnSchema{{Name: "id", Type: "integer", Extra: "GENERATED ALWAYS AS IDENTITY"}, {Name: "payload", Type: "jsonb", Nullable: true}},
+			Indexes: []types.IndexSchema{
+				{Name: "events_pkey", Primary: true, Unique: true, Constraint: true, Columns: []string{"id"}, Method: "btree"},
+				{Name: "events_payload_idx", Columns: []string{"payload"}, Method: "gin"},
+			},
+		}},
+		{Kind: types.SchemaModifyColumn, Table: "users", Name: "status", OldColumn: &oldStatus, NewColumn: &newStatus},
+		{Kind: types.SchemaModifyIndex, Table: "audit.posts", Name: "posts_slug_idx", OldIndex: &oldSlug, NewIndex: &newSlug},
+		{Kind: types.SchemaDropIndex, Table: "users", Name: "users_email_key", OldIndex: &email},
+	}
+
+	expected := []string{
+		`DROP INDEX "audit"."posts_slug_idx"`,
+		`ALTER TABLE "users" DROP CONSTRAINT "users_email_key"`,
+		"CREATE TABLE \"audit\".\"events\" (\n  \"id\" integer NOT NULL GENERATED ALWAYS AS IDENTITY,\n  \"payload\" jsonb,\n  CONSTRAINT \"events_pkey\" PRIMARY KEY (id)\n)",
+		`ALTER TABLE "users" ALTER COLUMN "status" TYPE text, ALTER COLUMN "status" SET NOT NULL, ALTER COLUMN "status" DROP DEFAULT`,
+		`CREATE INDEX "events_payload_idx" ON "audit"."events" USING gin (payload)`,
+		`CREATE UNIQUE INDEX "posts_slug_idx" ON "audit"."posts" (lower(slug))`,
+	}
+
+	result := NewPostgresEngine().BuildAlterStatements(changes)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("BuildAlterStatements() =\n%q\nwant\n%q", result, expected)
+	}
+}
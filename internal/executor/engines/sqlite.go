@@ -0,0 +1,336 @@
+package engines
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/sqldump"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// SQLiteEngine runs the sqlite3 shell on the host. Every database is a file in
+// one directory: the database named "app" is app<ext> there.
+type SQLiteEngine struct {
+	dir string
+	ext string
+}
+
+// NewSQLiteEngine returns an engine for the database files in dir with the
+// extension ext, e.g. ".db"
+func NewSQLiteEngine(dir, ext string) *SQLiteEngine {
+	return &SQLiteEngine{dir: dir, ext: ext}
+}
+
+// File returns the path of the database file of dbName
+func (e *SQLiteEngine) File(dbName string) string {
+	return filepath.Join(e.dir, dbName+e.ext)
+}
+
+// Dir returns the directory holding the database files
+func (e *SQLiteEngine) Dir() string {
+	return e.dir
+}
+
+// Ext returns the extension of the database files
+func (e *SQLiteEngine) Ext() string {
+	return e.ext
+}
+
+// Env returns nothing, SQLite has no credentials
+func (e *SQLiteEngine) Env(dsn *types.DSN) []string {
+	return nil
+}
+
+// PortEnv returns nothing, SQLite databases are files
+func (e *SQLiteEngine) PortEnv(dsn *types.DSN) []string {
+	return nil
+}
+
+// BuildDumpCommands translates the spec into one sqlite3 run of .dump and
+// .schema commands. Their table arguments are LIKE patterns, so the spec must
+// list the tables explicitly whenever some are excluded.
+func (e *SQLiteEngine) BuildDumpCommands(dsn *types.DSN, spec DumpSpec) [][]string {
+	tables := without(spec.Tables, spec.ExcludeTables)
+	if len(spec.Tables) > 0 && len(tables) == 0 {
+		return nil
+	}
+	structureOnly := without(spec.StructureOnly, spec.ExcludeTables)
+	if len(spec.Tables) > 0 {
+		structureOnly = intersect(structureOnly, tables)
+	}
+
+	// Without a table list every table is dumped
+	dumpAll := len(tables) == 0
+	dataTables := without(tables, structureOnly)
+
+	cmd := []string{"sqlite3", "-bail", e.File(dsn.Database)}
+	switch {
+	case spec.SchemaOnly:
+		if dumpAll {
+			cmd = append(cmd, ".schema")
+		}
+		for _, table := range tables {
+			cmd = append(cmd, ".schema "+table)
+		}
+	case spec.DataOnly:
+		if !dumpAll && len(dataTables) == 0 {
+			return nil
+		}
+		cmd = append(cmd, strings.TrimSpace(".dump --data-only "+strings.Join(dataTables, " ")))
+	default:
+		if dumpAll || len(dataTables) > 0 {
+			cmd = append(cmd, strings.TrimSpace(".dump "+strings.Join(dataTables, " ")))
+		}
+		for _, table := range structureOnly {
+			cmd = append(cmd, ".schema "+table)
+		}
+	}
+
+	return [][]string{cmd}
+}
+
+// BuildCreateCommand returns nil, database files are created by the FileDatabaseExecutor
+func (e *SQLiteEngine) BuildCreateCommand(dsn *types.DSN, dbName string) []string {
+	return nil
+}
+
+func (e *SQLiteEngine) BuildImportCommand(dsn *types.DSN, dbName string) []string {
+	return []string{"sqlite3", "-bail", e.File(dbName)}
+}
+
+// BuildDropCommand returns nil, database files are removed by the FileDatabaseExecutor
+func (e *SQLiteEngine) BuildDropCommand(dsn *types.DSN, dbName string) []string {
+	return nil
+}
+
+// BuildListCommand returns nil, the FileDatabaseExecutor lists the database files
+func (e *SQLiteEngine) BuildListCommand(dsn *types.DSN) []string {
+	return nil
+}
+
+func (e *SQLiteEngine) BuildVersionCommand(dsn *types.DSN) []string {
+	return []string{"sqlite3", ":memory:", "SELECT sqlite_version()"}
+}
+
+func (e *SQLiteEngine) BuildListTablesCommand(dsn *types.DSN, dbName string) []string {
+	return []string{
+		"sqlite3", "-bail", e.File(dbName),
+		"SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\\_%' ESCAPE '\\' ORDER BY name",
+	}
+}
+
+// BuildTableStatsCommand counts the rows of every table and sums the pages
+// of the table and its indexes in the dbstat virtual table. The database file
+// has no per-table modification time.
+func (e *SQLiteEngine) BuildTableStatsCommand(dsn *types.DSN, dbName string, tables []string) []string {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	selects := make([]string, 0, len(tables))
+	for _, table := range tables {
+		name := quoteSQLiteString(table)
+		selects = append(selects, "SELECT "+name+
+			", (SELECT COUNT(*) FROM "+quoteSQLiteIdent(table)+")"+
+			", (SELECT IFNULL(SUM(pgsize), 0) FROM dbstat WHERE name = "+name+")"+
+			", (SELECT IFNULL(SUM(pgsize), 0) FROM dbstat WHERE name IN "+
+			"(SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = "+name+")), 0")
+	}
+
+	return []string{
+		"sqlite3", "-bail", "-separator", "\t", e.File(dbName),
+		strings.Join(selects, " UNION ALL "),
+	}
+}
+
+// BuildSchemaCommand reads the pragma table functions. The primary key is
+// reported as an index named PRIMARY, since INTEGER PRIMARY KEY columns have
+// none, and foreign keys, which have no names in SQLite, are named after
+// their columns.
+func (e *SQLiteEngine) BuildSchemaCommand(dsn *types.DSN, dbName string) []string {
+	tables := "FROM sqlite_master m JOIN %s WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite\\_%%' ESCAPE '\\'"
+
+	columns := "SELECT 'column', m.name, p.name, p.type, CASE WHEN p.\"notnull\" THEN 'NO' ELSE 'YES' END, " +
+		"CASE WHEN p.dflt_value IS NULL THEN 0 ELSE 1 END, IFNULL(p.dflt_value, ''), '' " +
+		fmt.Sprintf(tables, "pragma_table_info(m.name) p") + " ORDER BY m.name, p.cid"
+
+	primaryKeys := "SELECT 'index', m.name, 'PRIMARY', 'primary', " +
+		"(SELECT group_concat(p.name, ',') FROM (SELECT name FROM pragma_table_info(m.name) WHERE pk > 0 ORDER BY pk) p), '', '', '' " +
+		fmt.Sprintf(tables, "pragma_table_info(m.name) k") + " AND k.pk = 1 ORDER BY m.name"
+
+	indexes := "SELECT 'index', m.name, il.name, CASE WHEN il.\"unique\" THEN 'unique' ELSE '' END, " +
+		"(SELECT group_concat(ii.name, ',') FROM (SELECT name FROM pragma_index_info(il.name) ORDER BY seqno) ii), " +
+		"'', CASE WHEN il.origin = 'u' THEN 'constraint' ELSE '' END, '' " +
+		fmt.Sprintf(tables, "pragma_index_list(m.name) il") + " AND il.origin <> 'pk' ORDER BY m.name, il.name"
+
+	foreignKeys := "SELECT 'foreign_key', m.name, m.name || '_' || group_concat(fk.\"from\", '_') || '_fkey', " +
+		"group_concat(fk.\"from\", ','), fk.\"table\", group_concat(IFNULL(fk.\"to\", ''), ','), fk.on_delete, fk.on_update " +
+		fmt.Sprintf(tables, "pragma_foreign_key_list(m.name) fk") + " GROUP BY m.name, fk.id ORDER BY m.name, fk.id"
+
+	return []string{
+		"sqlite3", "-bail", "-separator", "\t", e.File(dbName),
+		columns + "; " + primaryKeys + "; " + indexes + "; " + foreignKeys,
+	}
+}
+
+// BuildAlterStatements covers what ALTER TABLE can do in SQLite: add and
+// drop columns. Indexes are created and dropped; changes to columns, keys
+// and constraints need the table rebuilt and come out as comments.
+func (e *SQLiteEngine) BuildAlterStatements(changes []types.SchemaChange) []string {
+	var script alterScript
+	for _, change := range changes {
+		table := quoteSQLiteIdent(change.Table)
+		switch change.Kind {
+		case types.SchemaAddTable:
+			var lines []string
+			for i := range change.NewTable.Columns {
+				lines = append(lines, "  "+sqliteColumnDefinition(&change.NewTable.Columns[i]))
+			}
+			for i := range change.NewTable.Indexes {
+				index := &change.NewTable.Indexes[i]
+				switch {
+				case index.Primary:
+					lines = append(lines, "  PRIMARY KEY ("+quoteSQLiteIdents(index.Columns)+")")
+				case index.Constraint:
+					lines = append(lines, "  UNIQUE ("+quoteSQLiteIdents(index.Columns)+")")
+				default:
+					script.add(phaseAddIndex, sqliteCreateIndex(change.Table, index))
+				}
+			}
+			for i := range change.NewTable.ForeignKeys {
+				lines = append(lines, "  "+sqliteForeignKeyDefinition(&change.NewTable.ForeignKeys[i]))
+			}
+			script.add(phaseCreateTable, "CREATE TABLE "+table+" (\n"+strings.Join(lines, ",\n")+"\n)")
+		case types.SchemaDropTable:
+			script.add(phaseDropTable, "DROP TABLE "+table)
+		case types.SchemaAddColumn:
+			script.add(phaseAddColumn, "ALTER TABLE "+table+" ADD COLUMN "+sqliteColumnDefinition(change.NewColumn))
+		case types.SchemaDropColumn:
+			script.add(phaseDropColumn, "ALTER TABLE "+table+" DROP COLUMN "+quoteSQLiteIdent(change.Name))
+		case types.SchemaAddIndex, types.SchemaDropIndex, types.SchemaModifyIndex:
+			oldIndex, newIndex := change.OldIndex, change.NewIndex
+			if (oldIndex != nil && (oldIndex.Primary || oldIndex.Constraint)) || (newIndex != nil && (newIndex.Primary || newIndex.Constraint)) {
+				script.add(phaseAddIndex, unsupportedChange(e.Name(), change))
+				continue
+			}
+			if oldIndex != nil {
+				script.add(phaseDropIndex, "DROP INDEX "+quoteSQLiteIdent(oldIndex.Name))
+			}
+			if newIndex != nil {
+				script.add(phaseAddIndex, sqliteCreateIndex(change.Table, newIndex))
+			}
+		default:
+			script.add(phaseModifyColumn, unsupportedChange(e.Name(), change))
+		}
+	}
+	return script.statements()
+}
+
+func sqliteColumnDefinition(c *types.ColumnSchema) string {
+	definition := quoteSQLiteIdent(c.Name)
+	if c.Type != "" {
+		definition += " " + c.Type
+	}
+	if !c.Nullable {
+		definition += " NOT NULL"
+	}
+	if c.Default != nil {
+		definition += " DEFAULT " + *c.Default
+	}
+	return definition
+}
+
+func sqliteCreateIndex(table string, i *types.IndexSchema) string {
+	statement := "CREATE INDEX "
+	if i.Unique {
+		statement = "CREATE UNIQUE INDEX "
+	}
+	return statement + quoteSQLiteIdent(i.Name) + " ON " + quoteSQLiteIdent(table) + " (" + quoteSQLiteIdents(i.Columns) + ")"
+}
+
+func sqliteForeignKeyDefinition(k *types.ForeignKeySchema) string {
+	definition := "FOREIGN KEY (" + quoteSQLiteIdents(k.Columns) + ") REFERENCES " + quoteSQLiteIdent(k.ReferencedTable)
+	// References to the primary key leave the columns out
+	if strings.Join(k.ReferencedColumns, "") != "" {
+		definition += " (" + quoteSQLiteIdents(k.ReferencedColumns) + ")"
+	}
+	if k.OnDelete != "" {
+		definition += " ON DELETE " + k.OnDelete
+	}
+	if k.OnUpdate != "" {
+		definition += " ON UPDATE " + k.OnUpdate
+	}
+	return definition
+}
+
+// BuildSwapCommand returns nil, the FileDatabaseExecutor swaps by renaming the file
+func (e *SQLiteEngine) BuildSwapCommand(dsn *types.DSN, sourceDB, targetDB string, sourceTables, targetTables []string) []string {
+	return nil
+}
+
+// BuildTemplateCommand returns nil, SQLite has no template databases
+func (e *SQLiteEngine) BuildTemplateCommand(dsn *types.DSN, sourceDB, targetDB string) []string {
+	return nil
+}
+
+// BuildCopyCheckCommand returns nil, the FileDatabaseExecutor copies the file
+func (e *SQLiteEngine) BuildCopyCheckCommand(dsn *types.DSN, sourceDB string) []string {
+	return nil
+}
+
+func (e *SQLiteEngine) BuildForeignKeysCommand(dsn *types.DSN, sourceDB, targetDB string) []string {
+	return nil
+}
+
+func (e *SQLiteEngine) BuildCopyCommand(dsn *types.DSN, sourceDB, targetDB string, tables, statements []string) []string {
+	return nil
+}
+
+func (e *SQLiteEngine) SystemDatabases() []string {
+	return nil
+}
+
+// DumpMarkers returns none: .dump ends with COMMIT, but .schema output has
+// no trailer at all
+func (e *SQLiteEngine) DumpMarkers() DumpMarkers {
+	return DumpMarkers{}
+}
+
+// BuildQueryCommand opens the database read-only. -safe disables the shell's
+// functions and commands that reach the host, such as writefile() and
+// readfile(), which -readonly doesn't stop. The shell has no row or time
+// limit; the caller stops it.
+func (e *SQLiteEngine) BuildQueryCommand(dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) []string {
+	return []string{"sqlite3", "-safe", "-readonly", "-bail", "-csv", "-header", "-nullvalue", `\N`, e.File(dbName), query}
+}
+
+func (e *SQLiteEngine) QueryFormat() QueryFormat {
+	return QueryFormatCSV
+}
+
+// Dialect is that of Postgres: .dump writes standard conforming strings
+func (e *SQLiteEngine) Dialect() sqldump.Dialect {
+	return sqldump.DialectPostgres
+}
+
+func (e *SQLiteEngine) Name() string {
+	return "SQLite"
+}
+
+func quoteSQLiteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteSQLiteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteSQLiteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func quoteSQLiteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,122 @@
+package engines
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+func TestSQLiteEngine_BuildDumpCommands(t *testing.T) {
+	engine := NewSQLiteEngine("/srv/app/var", ".db")
+	dsn := &types.DSN{Engine: "sqlite", Database: "data"}
+
+	tests := []struct {
+		name     string
+		spec     DumpSpec
+		expected [][]string
+	}{
+		{
+			name:     "full dump",
+			expected: [][]string{{"sqlite3", "-bail", "/srv/app/var/data.db", ".dump"}},
+		},
+		{
+			name: "tables with exclusions and structure only",
+			spec: DumpSpec{
+				Tables:        []string{"users", "posts", "audit_log", "messenger_messages"},
+				ExcludeTables: []string{"messenger_messages"},
+				StructureOnly: []string{"audit_log"},
+			},
+			expected: [][]string{{"sqlite3", "-bail", "/srv/app/var/data.db", ".dump users posts", ".schema audit_log"}},
+		},
+		{
+			name:     "schema only",
+			spec:     DumpSpec{Tables: []string{"users"}, SchemaOnly: true},
+			expected: [][]string{{"sqlite3", "-bail", "/srv/app/var/data.db", ".schema users"}},
+		},
+		{
+			name:     "data only",
+			spec:     DumpSpec{Tables: []string{"users", "audit_log"}, StructureOnly: []string{"audit_log"}, DataOnly: true},
+			expected: [][]string{{"sqlite3", "-bail", "/srv/app/var/data.db", ".dump --data-only users"}},
+		},
+		{
+			name: "everything excluded",
+			spec: DumpSpec{Tables: []string{"users"}, ExcludeTables: []string{"users"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.BuildDumpCommands(dsn, tt.spec)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("BuildDumpCommands() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSQLiteEngine_Commands(t *testing.T) {
+	engine := NewSQLiteEngine("/srv/app/var", ".db")
+	dsn := &types.DSN{Engine: "sqlite", Database: "data"}
+
+	if result := engine.BuildImportCommand(dsn, "data_feature"); !reflect.DeepEqual(result, []string{"sqlite3", "-bail", "/srv/app/var/data_feature.db"}) {
+		t.Errorf("unexpected import command %v", result)
+	}
+	if result := engine.BuildListTablesCommand(dsn, "data"); len(result) != 4 || result[2] != "/srv/app/var/data.db" {
+		t.Errorf("unexpected list tables command %v", result)
+	}
+	if result := engine.BuildTableStatsCommand(dsn, "data", nil); result != nil {
+		t.Errorf("expected no table stats command without tables, got %v", result)
+	}
+	if result := engine.BuildTableStatsCommand(dsn, "data", []string{"users", `o"dd`}); len(result) != 6 ||
+		result[4] != "/srv/app/var/data.db" ||
+		!strings.Contains(result[5], `SELECT 'users', (SELECT COUNT(*) FROM "users")`) ||
+		!strings.Contains(result[5], `(SELECT COUNT(*) FROM "o""dd")`) {
+		t.Errorf("unexpected table stats command %v", result)
+	}
+	if result := engine.BuildQueryCommand(dsn, "data", "SELECT 1", 10, time.Second); !reflect.DeepEqual(result,
+		[]string{"sqlite3", "-safe", "-readonly", "-bail", "-csv", "-header", "-nullvalue", `\N`, "/srv/app/var/data.db", "SELECT 1"}) {
+		t.Errorf("unexpected query command %v", result)
+	}
+	if engine.Env(dsn) != nil || engine.PortEnv(dsn) != nil {
+		t.Error("expected no environment")
+	}
+	if engine.Name() != "SQLite" {
+		t.Errorf("expected SQLite, got %s", engine.Name())
+	}
+}
+
+func TestSQLiteEngine_BuildAlterStatements(t *testing.T) {
+	name := types.ColumnSchema{Name: "name", Type: "TEXT", Nullable: true}
+	oldPrimary := types.IndexSchema{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id"}}
+	newPrimary := types.IndexSchema{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id", "kind"}}
+	byName := types.IndexSchema{Name: "idx_users_name", Columns: []string{"name"}}
+
+	changes := []types.SchemaChange{
+		{Kind: types.SchemaAddTable, Table: "posts", NewTable: &types.TableSchema{
+			Name:        "posts",
+			Columns:     []types.ColumnSchema{{Name: "id", Type: "INTEGER"}, {Name: "user_id", Type: "INTEGER", Nullable: true}},
+			Indexes:     []types.IndexSchema{{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id"}}},
+			ForeignKeys: []types.ForeignKeySchema{{Name: "posts_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{""}, OnDelete: "CASCADE"}},
+		}},
+		{Kind: types.SchemaAddColumn, Table: "users", Name: "name", NewColumn: &name},
+		{Kind: types.SchemaModifyColumn, Table: "users", Name: "email"},
+		{Kind: types.SchemaModifyIndex, Table: "users", Name: "PRIMARY", OldIndex: &oldPrimary, NewIndex: &newPrimary},
+		{Kind: types.SchemaAddIndex, Table: "users", Name: "idx_users_name", NewIndex: &byName},
+	}
+
+	expected := []string{
+		"CREATE TABLE \"posts\" (\n  \"id\" INTEGER NOT NULL,\n  \"user_id\" INTEGER,\n  PRIMARY KEY (\"id\"),\n  FOREIGN KEY (\"user_id\") REFERENCES \"users\" ON DELETE CASCADE\n)",
+		`ALTER TABLE "users" ADD COLUMN "name" TEXT`,
+		`-- modify_column users.email: SQLite can't alter this in place, rebuild the table`,
+		`-- modify_index users.PRIMARY: SQLite can't alter this in place, rebuild the table`,
+		`CREATE INDEX "idx_users_name" ON "users" ("name")`,
+	}
+
+	result := NewSQLiteEngine("/srv/app/var", ".db").BuildAlterStatements(changes)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("BuildAlterStatements() =\n%q\nwant\n%q", result, expected)
+	}
+}
@@ -1,13 +1,17 @@
 package executor
 
-import "github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+import (
+	"context"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
 
 type Executor interface {
 	ReadFile(path string) ([]byte, error)
 	WriteFile(path string, data []byte) error
 	FileExists(path string) bool
 
-	GitWorktreeList() ([]types.WorktreeInfo, error)
-	GitWorktreeAdd(path, branch string, newBranch bool) error
-	GitWorktreeRemove(path string) error
+	GitWorktreeList(ctx context.Context, dir string) ([]types.WorktreeInfo, error)
+	GitWorktreeAdd(ctx context.Context, path, branch string, newBranch bool) error
+	GitWorktreeRemove(ctx context.Context, path string) error
 }
@@ -0,0 +1,303 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/anonymize"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// sqliteSidecars are the suffixes of the files SQLite keeps next to a database
+var sqliteSidecars = []string{"-wal", "-shm", "-journal"}
+
+// FileDatabaseExecutor manages SQLite databases, which are plain files in the
+// directory of the engine. Dumps and imports run the sqlite3 shell on the host;
+// creating, dropping, copying and swapping databases are file operations. No
+// docker service is involved, so the service arguments are ignored.
+type FileDatabaseExecutor struct {
+	engine  *engines.SQLiteEngine
+	clients *HostDatabaseExecutor
+}
+
+func NewFileDatabaseExecutor(engine *engines.SQLiteEngine, projectRoot string) *FileDatabaseExecutor {
+	return &FileDatabaseExecutor{
+		engine:  engine,
+		clients: NewHostDatabaseExecutor(engine, projectRoot),
+	}
+}
+
+func (f *FileDatabaseExecutor) SetProgressFunc(fn types.ProgressFunc) {
+	f.clients.SetProgressFunc(fn)
+}
+
+func (f *FileDatabaseExecutor) SetAnonymizer(a *anonymize.Anonymizer) {
+	f.clients.SetAnonymizer(a)
+}
+
+// exists fails unless the database file of dbName is there; sqlite3 would
+// silently create it instead
+func (f *FileDatabaseExecutor) exists(dbName string) error {
+	file := f.engine.File(dbName)
+	if _, err := os.Stat(file); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("database %s does not exist: %s not found", dbName, file)
+		}
+		return err
+	}
+	return nil
+}
+
+// listTables makes the filter name its tables whenever some are excluded,
+// since the .dump command can only select tables, not leave them out
+func listTables(filter types.TableFilter) types.TableFilter {
+	if len(filter.Tables) == 0 && (len(filter.ExcludeTables) > 0 || len(filter.StructureOnly) > 0) {
+		filter.Tables = []string{"*"}
+	}
+	return filter
+}
+
+func (f *FileDatabaseExecutor) Dump(ctx context.Context, service string, dsn *types.DSN, destPath string, filter types.TableFilter) (*types.DumpResult, error) {
+	if err := f.exists(dsn.Database); err != nil {
+		return nil, err
+	}
+	return f.clients.Dump(ctx, service, dsn, destPath, listTables(filter))
+}
+
+// Create creates an empty file, which SQLite treats as an empty database
+func (f *FileDatabaseExecutor) Create(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.CreateResult, error) {
+	if err := os.MkdirAll(f.engine.Dir(), 0755); err != nil {
+		return nil, fmt.Errorf("create database failed: %w", err)
+	}
+
+	file, err := os.OpenFile(f.engine.File(dbName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("create database failed: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("create database failed: %w", err)
+	}
+
+	return &types.CreateResult{Database: dbName}, nil
+}
+
+func (f *FileDatabaseExecutor) Import(ctx context.Context, service string, dsn *types.DSN, sourcePath string, dbName string, tables []string) (*types.ImportResult, error) {
+	return f.clients.Import(ctx, service, dsn, sourcePath, dbName, tables)
+}
+
+func (f *FileDatabaseExecutor) Drop(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DropResult, error) {
+	if err := f.exists(dbName); err != nil {
+		return nil, fmt.Errorf("drop database failed: %w", err)
+	}
+
+	file := f.engine.File(dbName)
+	if err := os.Remove(file); err != nil {
+		return nil, fmt.Errorf("drop database failed: %w", err)
+	}
+	removeSidecars(file)
+
+	return &types.DropResult{Database: dbName}, nil
+}
+
+// List returns the database files of the engine's directory
+func (f *FileDatabaseExecutor) List(ctx context.Context, service string, dsn *types.DSN, defaultDB string) (*types.DatabaseListResult, error) {
+	entries, err := os.ReadDir(f.engine.Dir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("list databases failed: %w", err)
+	}
+
+	databases := []types.DatabaseInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != f.engine.Ext() {
+			continue
+		}
+		name = strings.TrimSuffix(name, f.engine.Ext())
+		databases = append(databases, types.DatabaseInfo{
+			Name:      name,
+			IsDefault: name == defaultDB,
+		})
+	}
+
+	return &types.DatabaseListResult{Databases: databases}, nil
+}
+
+// Pipe feeds a sqlite3 dump of sourceDB into targetDB. Copy is the faster way
+// to clone a whole database.
+func (f *FileDatabaseExecutor) Pipe(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string, filter types.TableFilter) (int64, error) {
+	if err := f.exists(sourceDB); err != nil {
+		return 0, err
+	}
+	return f.clients.Pipe(ctx, service, dsn, sourceDB, targetDB, listTables(filter))
+}
+
+// Template returns ErrCloneUnsupported, Copy clones a SQLite database
+func (f *FileDatabaseExecutor) Template(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) error {
+	return ErrCloneUnsupported
+}
+
+// Copy replaces the file of targetDB with a copy of the file of sourceDB and
+// returns the number of bytes copied
+func (f *FileDatabaseExecutor) Copy(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) (int64, error) {
+	return f.CopyFile(ctx, dsn, sourceDB, f.engine.File(targetDB))
+}
+
+// CopyFile writes a copy of the file of sourceDB to target, e.g. into a
+// worktree, replacing what is there. Pending writes of a database in WAL mode
+// are checkpointed into its main file first.
+func (f *FileDatabaseExecutor) CopyFile(ctx context.Context, dsn *types.DSN, sourceDB, target string) (int64, error) {
+	if err := f.exists(sourceDB); err != nil {
+		return 0, err
+	}
+
+	source := f.engine.File(sourceDB)
+	if _, err := os.Stat(source + "-wal"); err == nil {
+		if _, err := f.clients.query(ctx, "", dsn, []string{"sqlite3", source, "PRAGMA wal_checkpoint(TRUNCATE)"}); err != nil {
+			return 0, fmt.Errorf("checkpoint of %s failed: %w", sourceDB, err)
+		}
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return 0, fmt.Errorf("copy database failed: %w", err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return 0, fmt.Errorf("copy database failed: %w", err)
+	}
+	out, err := os.CreateTemp(filepath.Dir(target), filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("copy database failed: %w", err)
+	}
+	tmpPath := out.Name()
+
+	progress := newProgressCounter(types.StageCloning, f.clients.progress)
+	size, err := io.Copy(out, progress.Reader(&contextReader{ctx: ctx, r: in}))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		if err := interrupted(ctx, "copy database"); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("copy database failed: %w", err)
+	}
+	progress.finish()
+
+	removeSidecars(target)
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("copy database failed: %w", err)
+	}
+
+	return size, nil
+}
+
+func (f *FileDatabaseExecutor) ServerVersion(ctx context.Context, service string, dsn *types.DSN) (string, error) {
+	return f.clients.ServerVersion(ctx, service, dsn)
+}
+
+func (f *FileDatabaseExecutor) Tables(ctx context.Context, service string, dsn *types.DSN, dbName string) ([]string, error) {
+	if err := f.exists(dbName); err != nil {
+		return nil, err
+	}
+	return f.clients.Tables(ctx, service, dsn, dbName)
+}
+
+// Stats counts the rows of the tables of dbName with the sqlite3 shell. The
+// database size and last change are those of its file and sidecar files.
+func (f *FileDatabaseExecutor) Stats(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DatabaseStatsResult, error) {
+	tables, err := f.Tables(ctx, service, dsn, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []types.TableStats
+	if cmd := f.engine.BuildTableStatsCommand(dsn, dbName, tables); cmd != nil {
+		output, err := f.clients.query(ctx, service, dsn, cmd)
+		if err != nil {
+			return nil, fmt.Errorf("table statistics query failed: %w", err)
+		}
+		if stats, err = parseTableStats(output); err != nil {
+			return nil, err
+		}
+	}
+
+	result := newStatsResult(dbName, stats)
+	// The table pages leave out free pages and the schema, the files don't
+	result.Stats.Size = 0
+	file := f.engine.File(dbName)
+	for _, suffix := range append([]string{""}, sqliteSidecars...) {
+		info, err := os.Stat(file + suffix)
+		if err != nil {
+			continue
+		}
+		result.Stats.Size += info.Size()
+		if modified := info.ModTime().UTC().Format(time.RFC3339); modified > result.Stats.Modified {
+			result.Stats.Modified = modified
+		}
+	}
+
+	return result, nil
+}
+
+func (f *FileDatabaseExecutor) Schema(ctx context.Context, service string, dsn *types.DSN, dbName string) (*types.DatabaseSchema, error) {
+	if err := f.exists(dbName); err != nil {
+		return nil, err
+	}
+	return f.clients.Schema(ctx, service, dsn, dbName)
+}
+
+func (f *FileDatabaseExecutor) Query(ctx context.Context, service string, dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) (*types.QueryResult, error) {
+	if err := f.exists(dbName); err != nil {
+		return nil, err
+	}
+	return f.clients.Query(ctx, service, dsn, dbName, query, maxRows, timeout)
+}
+
+// Swap renames the file of sourceDB over the file of targetDB, which replaces
+// it atomically
+func (f *FileDatabaseExecutor) Swap(ctx context.Context, service string, dsn *types.DSN, sourceDB, targetDB string) error {
+	if err := f.exists(sourceDB); err != nil {
+		return fmt.Errorf("swap databases failed: %w", err)
+	}
+
+	target := f.engine.File(targetDB)
+	// A journal left by the replaced file would be applied to the new one
+	removeSidecars(target)
+	if err := os.Rename(f.engine.File(sourceDB), target); err != nil {
+		return fmt.Errorf("swap databases failed: %w", err)
+	}
+
+	return nil
+}
+
+func removeSidecars(file string) {
+	for _, suffix := range sqliteSidecars {
+		os.Remove(file + suffix)
+	}
+}
+
+// contextReader stops a copy once ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,323 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// sqliteExecutor returns a file executor for a directory holding the database
+// data.db with the tables users and audit_log
+func sqliteExecutor(t *testing.T) (*FileDatabaseExecutor, string, *types.DSN) {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+
+	dir := t.TempDir()
+	setup := "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);" +
+		"INSERT INTO users VALUES (1, 'a@example.com');" +
+		"CREATE TABLE audit_log (id INTEGER PRIMARY KEY, entry TEXT);" +
+		"INSERT INTO audit_log VALUES (1, 'login');"
+	if out, err := exec.Command("sqlite3", filepath.Join(dir, "data.db"), setup).CombinedOutput(); err != nil {
+		t.Fatalf("setup failed: %v: %s", err, out)
+	}
+
+	dsn := &types.DSN{Engine: "sqlite", Database: "data", Path: filepath.Join(dir, "data.db")}
+	return NewFileDatabaseExecutor(engines.NewSQLiteEngine(dir, ".db"), dir), dir, dsn
+}
+
+func TestFileDatabaseExecutor_CreateListDrop(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+	ctx := context.Background()
+
+	if _, err := fileExecutor.Create(ctx, "", dsn, "data_feature"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := fileExecutor.Create(ctx, "", dsn, "data_feature"); err == nil {
+		t.Error("expected an error creating an existing database")
+	}
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a database"), 0644)
+
+	list, err := fileExecutor.List(ctx, "", dsn, "data")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	expected := []types.DatabaseInfo{{Name: "data", IsDefault: true}, {Name: "data_feature"}}
+	if !reflect.DeepEqual(list.Databases, expected) {
+		t.Errorf("List() = %+v, want %+v", list.Databases, expected)
+	}
+
+	if _, err := fileExecutor.Drop(ctx, "", dsn, "data_feature"); err != nil {
+		t.Fatalf("Drop() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data_feature.db")); !os.IsNotExist(err) {
+		t.Error("expected the database file to be removed")
+	}
+	if _, err := fileExecutor.Drop(ctx, "", dsn, "data_feature"); err == nil {
+		t.Error("expected an error dropping a missing database")
+	}
+}
+
+func TestFileDatabaseExecutor_Stats(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+	ctx := context.Background()
+
+	insert := "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 500) " +
+		"INSERT INTO audit_log (entry) SELECT 'entry ' || i FROM n"
+	if out, err := exec.Command("sqlite3", filepath.Join(dir, "data.db"), insert).CombinedOutput(); err != nil {
+		t.Fatalf("insert failed: %v: %s", err, out)
+	}
+
+	result, err := fileExecutor.Stats(ctx, "", dsn, "data")
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if len(result.Tables) != 2 || result.Tables[0].Name != "audit_log" || result.Tables[0].Rows != 501 || result.Tables[1].Rows != 1 {
+		t.Errorf("unexpected tables %+v", result.Tables)
+	}
+	if result.Tables[0].DataSize == 0 {
+		t.Errorf("expected audit_log pages to be counted, got %+v", result.Tables[0])
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stats.Size != info.Size() || result.Stats.TableCount != 2 || result.Stats.Rows != 502 || result.Stats.Modified == "" {
+		t.Errorf("unexpected stats %+v", result.Stats)
+	}
+
+	if _, err := fileExecutor.Create(ctx, "", dsn, "data_empty"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	empty, err := fileExecutor.Stats(ctx, "", dsn, "data_empty")
+	if err != nil {
+		t.Fatalf("Stats() of an empty database error = %v", err)
+	}
+	if len(empty.Tables) != 0 || empty.Stats.TableCount != 0 {
+		t.Errorf("unexpected stats of an empty database %+v", empty)
+	}
+
+	if _, err := fileExecutor.Stats(ctx, "", dsn, "missing"); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
+
+func TestFileDatabaseExecutor_Schema(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+	ctx := context.Background()
+
+	setup := "CREATE TABLE posts (id INTEGER, user_id INTEGER NOT NULL REFERENCES users ON DELETE CASCADE, " +
+		"slug TEXT DEFAULT 'draft', UNIQUE (slug), PRIMARY KEY (id, user_id));" +
+		"CREATE INDEX idx_posts_user ON posts (user_id);"
+	if out, err := exec.Command("sqlite3", filepath.Join(dir, "data.db"), setup).CombinedOutput(); err != nil {
+		t.Fatalf("setup failed: %v: %s", err, out)
+	}
+
+	schema, err := fileExecutor.Schema(ctx, "", dsn, "data")
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+
+	var names []string
+	for _, table := range schema.Tables {
+		names = append(names, table.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"audit_log", "posts", "users"}) {
+		t.Fatalf("unexpected tables %v", names)
+	}
+
+	draft := "'draft'"
+	posts := schema.Tables[1]
+	expectedColumns := []types.ColumnSchema{
+		{Name: "id", Type: "INTEGER", Nullable: true},
+		{Name: "user_id", Type: "INTEGER"},
+		{Name: "slug", Type: "TEXT", Nullable: true, Default: &draft},
+	}
+	if !reflect.DeepEqual(posts.Columns, expectedColumns) {
+		t.Errorf("unexpected columns %+v", posts.Columns)
+	}
+	expectedIndexes := []types.IndexSchema{
+		{Name: "PRIMARY", Primary: true, Unique: true, Columns: []string{"id", "user_id"}},
+		{Name: "idx_posts_user", Columns: []string{"user_id"}},
+		{Name: "sqlite_autoindex_posts_1", Unique: true, Columns: []string{"slug"}, Constraint: true},
+	}
+	if !reflect.DeepEqual(posts.Indexes, expectedIndexes) {
+		t.Errorf("unexpected indexes %+v", posts.Indexes)
+	}
+	expectedKeys := []types.ForeignKeySchema{
+		{Name: "posts_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", OnDelete: "CASCADE", OnUpdate: "NO ACTION"},
+	}
+	if !reflect.DeepEqual(posts.ForeignKeys, expectedKeys) {
+		t.Errorf("unexpected foreign keys %+v", posts.ForeignKeys)
+	}
+
+	if _, err := fileExecutor.Schema(ctx, "", dsn, "missing"); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
+
+func TestFileDatabaseExecutor_Query(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+	ctx := context.Background()
+
+	if out, err := exec.Command("sqlite3", filepath.Join(dir, "data.db"), "INSERT INTO users VALUES (2, NULL), (3, 'c@example.com');").CombinedOutput(); err != nil {
+		t.Fatalf("setup failed: %v: %s", err, out)
+	}
+
+	result, err := fileExecutor.Query(ctx, "", dsn, "data", "SELECT id, email FROM users ORDER BY id", 2, time.Second)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Columns, []string{"id", "email"}) || !result.Truncated {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if rows := values(result.Rows); !reflect.DeepEqual(rows, [][]string{{"1", "a@example.com"}, {"2", "NULL"}}) {
+		t.Errorf("unexpected rows %q", rows)
+	}
+
+	// The database is opened read-only even if a write got past the guard
+	if _, err := fileExecutor.Query(ctx, "", dsn, "data", "DELETE FROM users", 10, time.Second); err == nil ||
+		!strings.Contains(err.Error(), "readonly") {
+		t.Errorf("expected the write to fail, got %v", err)
+	}
+
+	// The shell's file functions are disabled even if a call got past the guard
+	pwned := filepath.Join(t.TempDir(), "pwned")
+	for _, query := range []string{"SELECT writefile('" + pwned + "', 'x')", "SELECT readfile('/etc/hostname')"} {
+		if _, err := fileExecutor.Query(ctx, "", dsn, "data", query, 10, time.Second); err == nil || !strings.Contains(err.Error(), "safe mode") {
+			t.Errorf("expected %q to fail in safe mode, got %v", query, err)
+		}
+	}
+	if _, err := os.Stat(pwned); !os.IsNotExist(err) {
+		t.Errorf("expected no file to be written")
+	}
+
+	if _, err := fileExecutor.Query(ctx, "", dsn, "missing", "SELECT 1", 10, time.Second); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
+
+func TestFileDatabaseExecutor_DumpAndImport(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+	ctx := context.Background()
+
+	destPath := filepath.Join(dir, "dump.sql")
+	filter := types.TableFilter{StructureOnly: []string{"audit_log"}}
+	if _, err := fileExecutor.Dump(ctx, "", dsn, destPath, filter); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := string(data)
+	if !strings.Contains(dump, "a@example.com") || !strings.Contains(dump, "CREATE TABLE audit_log") {
+		t.Errorf("expected users data and audit_log schema in dump:\n%s", dump)
+	}
+	if strings.Contains(dump, "login") {
+		t.Errorf("expected no audit_log rows in dump:\n%s", dump)
+	}
+
+	if _, err := fileExecutor.Create(ctx, "", dsn, "data_restore"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fileExecutor.Import(ctx, "", dsn, destPath, "data_restore", nil); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	tables, err := fileExecutor.Tables(ctx, "", dsn, "data_restore")
+	if err != nil {
+		t.Fatalf("Tables() error = %v", err)
+	}
+	if !reflect.DeepEqual(tables, []string{"audit_log", "users"}) {
+		t.Errorf("Tables() = %v", tables)
+	}
+
+	if _, err := fileExecutor.Dump(ctx, "", dsn, destPath, types.TableFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	dsn.Database = "missing"
+	if _, err := fileExecutor.Dump(ctx, "", dsn, destPath, types.TableFilter{}); err == nil {
+		t.Error("expected an error dumping a missing database")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.db")); !os.IsNotExist(err) {
+		t.Error("expected no database file to be created by a failed dump")
+	}
+}
+
+func TestFileDatabaseExecutor_CopyAndSwap(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+	ctx := context.Background()
+
+	var stages []types.ProgressStage
+	fileExecutor.SetProgressFunc(func(stage types.ProgressStage, detail string) {
+		stages = append(stages, stage)
+	})
+
+	size, err := fileExecutor.Copy(ctx, "", dsn, "data", "data_copy")
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "data_copy.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != info.Size() {
+		t.Errorf("expected %d bytes copied, got %d", info.Size(), size)
+	}
+	if len(stages) == 0 || stages[0] != types.StageCloning {
+		t.Errorf("expected cloning progress, got %v", stages)
+	}
+
+	worktreeFile := filepath.Join(t.TempDir(), "var", "data.db")
+	if _, err := fileExecutor.CopyFile(ctx, dsn, "data", worktreeFile); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	if _, err := os.Stat(worktreeFile); err != nil {
+		t.Errorf("expected the worktree copy: %v", err)
+	}
+
+	if out, err := exec.Command("sqlite3", filepath.Join(dir, "data_copy.db"), "DROP TABLE audit_log").CombinedOutput(); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	if err := fileExecutor.Swap(ctx, "", dsn, "data_copy", "data"); err != nil {
+		t.Fatalf("Swap() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data_copy.db")); !os.IsNotExist(err) {
+		t.Error("expected the source file to be renamed")
+	}
+	tables, err := fileExecutor.Tables(ctx, "", dsn, "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tables, []string{"users"}) {
+		t.Errorf("expected the swapped database, got tables %v", tables)
+	}
+}
+
+func TestFileDatabaseExecutor_CopyCancelled(t *testing.T) {
+	fileExecutor, dir, dsn := sqliteExecutor(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := fileExecutor.Copy(ctx, "", dsn, "data", "data_copy"); err == nil {
+		t.Fatal("expected an error for a cancelled copy")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the source database in %s, got %d entries", dir, len(entries))
+	}
+}
@@ -1,13 +1,21 @@
 package executor
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
+// ErrNotGitRepository is returned by GitWorktreeList when neither dir nor any
+// of its parents holds a .git. A .git git can't read is an error of its own.
+var ErrNotGitRepository = errors.New("not a git repository")
+
 type GitExecutor struct{}
 
 func NewGitExecutor() Executor {
@@ -20,14 +28,17 @@ type worktreeOutput struct {
 	Branch string
 }
 
-func (g *GitExecutor) GitWorktreeList() ([]types.WorktreeInfo, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+func (g *GitExecutor) GitWorktreeList(ctx context.Context, dir string) ([]types.WorktreeInfo, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "worktree", "list", "--porcelain")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return nil, fmt.Errorf("git worktree list failed: %w", err)
+		if ctx.Err() == nil && strings.Contains(string(output), "not a git repository") && !hasGitDir(dir) {
+			return nil, ErrNotGitRepository
+		}
+		return nil, fmt.Errorf("git worktree list failed: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
 	}
 
-	cmdPath := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmdPath := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--show-toplevel")
 	toplevelOutput, err := cmdPath.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("git rev-parse failed: %w", err)
@@ -37,7 +48,25 @@ func (g *GitExecutor) GitWorktreeList() ([]types.WorktreeInfo, error) {
 	return parseWorktreeListOutput(string(output), toplevelPath)
 }
 
-func (g *GitExecutor) GitWorktreeAdd(path, branch string, newBranch bool) error {
+// hasGitDir tells whether dir or one of its parents holds a .git entry
+func hasGitDir(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for {
+		if _, err := os.Lstat(filepath.Join(abs, ".git")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return false
+		}
+		abs = parent
+	}
+}
+
+func (g *GitExecutor) GitWorktreeAdd(ctx context.Context, path, branch string, newBranch bool) error {
 	args := []string{"worktree", "add"}
 	if newBranch {
 		// Create new branch: git worktree add -b <branch> <path>
@@ -47,7 +76,7 @@ func (g *GitExecutor) GitWorktreeAdd(path, branch string, newBranch bool) error
 		args = append(args, path, branch)
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("git worktree add failed: %w\nOutput: %s", err, string(output))
@@ -56,8 +85,8 @@ func (g *GitExecutor) GitWorktreeAdd(path, branch string, newBranch bool) error
 	return nil
 }
 
-func (g *GitExecutor) GitWorktreeRemove(path string) error {
-	cmd := exec.Command("git", "worktree", "remove", path, "--force")
+func (g *GitExecutor) GitWorktreeRemove(ctx context.Context, path string) error {
+	cmd := exec.CommandContext(ctx, "git", "worktree", "remove", path, "--force")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("git worktree remove failed: %w", err)
 	}
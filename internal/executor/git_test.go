@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"context"
 	"os/exec"
 	"testing"
 )
@@ -121,7 +122,7 @@ func TestGitWorktreeList(t *testing.T) {
 			t.Skip("not in a git repository")
 		}
 
-		worktrees, err := g.GitWorktreeList()
+		worktrees, err := g.GitWorktreeList(context.Background(), ".")
 		if err != nil {
 			t.Fatalf("GitWorktreeList() error = %v", err)
 		}
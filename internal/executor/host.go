@@ -0,0 +1,23 @@
+package executor
+
+import (
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// HostDatabaseExecutor runs the engine's client tools on the host and connects
+// them to the host and port of the DSN. It serves databases that run natively,
+// in a container shared by several projects, or in a compose service without
+// client binaries. The service arguments are ignored.
+type HostDatabaseExecutor struct {
+	*DockerDatabaseExecutor
+}
+
+func NewHostDatabaseExecutor(engine engines.DatabaseEngine, projectRoot string) *HostDatabaseExecutor {
+	return &HostDatabaseExecutor{
+		DockerDatabaseExecutor: &DockerDatabaseExecutor{
+			engine:      engine,
+			projectRoot: projectRoot,
+			local:       true,
+		},
+	}
+}
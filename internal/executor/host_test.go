@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// fakeClient puts a client tool on PATH that runs the given shell body, and a
+// docker that fails
+func fakeClient(t *testing.T, name, body string) {
+	t.Helper()
+	fakeDocker(t, `echo "docker must not run" >&2; exit 1`)
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestHostDatabaseExecutor_RunsClientsOnHost(t *testing.T) {
+	fakeClient(t, "mysql", `case "$*" in
+  "-h 127.0.0.1 -u root -N -B -e SELECT VERSION()") echo "$MYSQL_PWD:$MYSQL_TCP_PORT" ;;
+  *) echo "unexpected args: $*" >&2; exit 1 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "127.0.0.1", Port: "33061", User: "root", Password: "s3cr3t", Database: "app"}
+	dbExecutor := NewHostDatabaseExecutor(engines.NewMySQLEngine(false), t.TempDir())
+
+	version, err := dbExecutor.ServerVersion(context.Background(), "database", dsn)
+	if err != nil {
+		t.Fatalf("ServerVersion() error = %v", err)
+	}
+	if version != "s3cr3t:33061" {
+		t.Errorf("expected password and port in the client environment, got %q", version)
+	}
+}
+
+func TestHostDatabaseExecutor_Dump(t *testing.T) {
+	fakeClient(t, "pg_dump", `echo "-- port $PGPORT: $*"; echo "-- PostgreSQL database dump complete"`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "db.internal", Port: "5433", User: "app", Database: "app"}
+
+	dbExecutor := NewHostDatabaseExecutor(engines.NewPostgresEngine(), dir)
+	if _, err := dbExecutor.Dump(context.Background(), "", dsn, destPath, types.TableFilter{}); err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "-- port 5433: -h db.internal -U app --no-owner app\n-- PostgreSQL database dump complete\n" {
+		t.Errorf("unexpected dump content %q", data)
+	}
+}
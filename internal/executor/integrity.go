@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// dumpEdgeSize is how much of the start and the end of a dump is kept to find
+// the markers of its dump tool
+const dumpEdgeSize = 4096
+
+// edgeWriter keeps the first and the last dumpEdgeSize bytes written to it
+type edgeWriter struct {
+	head []byte
+	tail []byte
+}
+
+func (w *edgeWriter) Write(p []byte) (int, error) {
+	if missing := dumpEdgeSize - len(w.head); missing > 0 {
+		w.head = append(w.head, p[:min(missing, len(p))]...)
+	}
+
+	if len(p) >= dumpEdgeSize {
+		w.tail = append(w.tail[:0], p[len(p)-dumpEdgeSize:]...)
+	} else {
+		w.tail = append(w.tail, p...)
+		if excess := len(w.tail) - dumpEdgeSize; excess > 0 {
+			w.tail = append(w.tail[:0], w.tail[excess:]...)
+		}
+	}
+	return len(p), nil
+}
+
+// DumpContents is what reading a dump file through tells about it
+type DumpContents struct {
+	// Checksum is "sha256:<hex>" of the file as stored
+	Checksum string
+	// Head and Tail are the start and the end of the decompressed SQL
+	Head []byte
+	Tail []byte
+}
+
+// ReadDump reads the dump at path to its end, decompressing it according to
+// its extension. A truncated compressed file fails here already. Reading
+// fails once ctx is done.
+func ReadDump(ctx context.Context, path string) (*DumpContents, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump file: %w", err)
+	}
+	defer file.Close()
+	in := &contextReader{ctx: ctx, r: file}
+
+	hasher := sha256.New()
+	_, compression, _ := types.ParseDumpFilename(filepath.Base(path))
+	sqlReader, err := decompressReader(io.TeeReader(in, hasher), compression)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := &edgeWriter{}
+	if _, err := io.Copy(edges, sqlReader); err != nil {
+		sqlReader.Close()
+		return nil, fmt.Errorf("failed to decompress dump file: %w", err)
+	}
+	if err := sqlReader.Close(); err != nil {
+		return nil, fmt.Errorf("failed to decompress dump file: %w", err)
+	}
+
+	// Compressed streams may end before the file does
+	if _, err := io.Copy(hasher, in); err != nil {
+		return nil, fmt.Errorf("failed to read dump file: %w", err)
+	}
+
+	return &DumpContents{
+		Checksum: "sha256:" + hex.EncodeToString(hasher.Sum(nil)),
+		Head:     edges.head,
+		Tail:     edges.tail,
+	}, nil
+}
+
+// OpenDump opens the dump at path for reading its SQL, decompressing it
+// according to its extension. Reading fails once ctx is done, closing the
+// reader closes the file.
+func OpenDump(ctx context.Context, path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump file: %w", err)
+	}
+
+	_, compression, _ := types.ParseDumpFilename(filepath.Base(path))
+	sqlReader, err := decompressReader(&contextReader{ctx: ctx, r: file}, compression)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &dumpReader{ReadCloser: sqlReader, file: file}, nil
+}
+
+// dumpReader closes the file under a decompressing reader with it
+type dumpReader struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (r *dumpReader) Close() error {
+	err := r.ReadCloser.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
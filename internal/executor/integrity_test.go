@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+func TestEdgeWriter(t *testing.T) {
+	w := &edgeWriter{}
+	w.Write([]byte("-- MySQL dump\n"))
+	w.Write(bytes.Repeat([]byte("x"), 3*dumpEdgeSize))
+	w.Write([]byte("\n-- Dump completed\n"))
+
+	if !bytes.HasPrefix(w.head, []byte("-- MySQL dump\nxxx")) || len(w.head) != dumpEdgeSize {
+		t.Errorf("unexpected head of %d bytes: %q...", len(w.head), w.head[:20])
+	}
+	if !bytes.HasSuffix(w.tail, []byte("xxx\n-- Dump completed\n")) || len(w.tail) != dumpEdgeSize {
+		t.Errorf("unexpected tail of %d bytes", len(w.tail))
+	}
+}
+
+func TestReadDump(t *testing.T) {
+	fakeDocker(t, `echo "-- MySQL dump 10.13"; echo "CREATE TABLE t (id int);"; echo "-- Dump completed"`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql.gz")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	result, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{})
+	if err != nil {
+		t.Fatalf("Dump() error = %v", err)
+	}
+
+	contents, err := ReadDump(context.Background(), destPath)
+	if err != nil {
+		t.Fatalf("ReadDump() error = %v", err)
+	}
+	if contents.Checksum != result.Checksum {
+		t.Errorf("ReadDump() checksum %s, Dump() recorded %s", contents.Checksum, result.Checksum)
+	}
+	if !strings.HasPrefix(string(contents.Head), "-- MySQL dump") || !strings.HasSuffix(string(contents.Tail), "-- Dump completed\n") {
+		t.Errorf("unexpected head %q and tail %q", contents.Head, contents.Tail)
+	}
+
+	raw, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(dir, "truncated.sql.gz")
+	if err := os.WriteFile(truncated, raw[:len(raw)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadDump(context.Background(), truncated); err == nil {
+		t.Error("ReadDump() of a truncated gzip file succeeded")
+	}
+}
+
+func TestOpenDump(t *testing.T) {
+	dir := t.TempDir()
+	sql := "CREATE TABLE t (id int);\n"
+
+	var compressed bytes.Buffer
+	w, err := compressWriter(&compressed, types.CompressionGzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(sql))
+	w.Close()
+
+	files := map[string][]byte{
+		"app.sql":    []byte(sql),
+		"app.sql.gz": compressed.Bytes(),
+	}
+	for name, data := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := OpenDump(context.Background(), path)
+			if err != nil {
+				t.Fatalf("OpenDump() error = %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read error = %v", err)
+			}
+			if err := r.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+			if string(got) != sql {
+				t.Errorf("OpenDump() read %q, want %q", got, sql)
+			}
+		})
+	}
+
+	if _, err := OpenDump(context.Background(), filepath.Join(dir, "missing.sql")); err == nil {
+		t.Error("OpenDump() of a missing file succeeded")
+	}
+}
+
+func TestDockerDatabaseExecutor_DumpRejectsIncompleteOutput(t *testing.T) {
+	fakeDocker(t, `echo "-- MySQL dump 10.13"; echo "INSERT INTO t VALUES (1),("`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "app.sql")
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, dir)
+	_, err := dbExecutor.Dump(context.Background(), "database", dsn, destPath, types.TableFilter{})
+	if err == nil || !strings.Contains(err.Error(), "incomplete") {
+		t.Fatalf("Dump() error = %v, want incomplete dump", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("incomplete dump left files behind: %v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// progressInterval is how many bytes must pass between two progress reports
+const progressInterval = 4 << 20
+
+// progressCounter counts bytes passing through a stream and reports the
+// running total to a ProgressFunc every progressInterval bytes.
+type progressCounter struct {
+	stage    types.ProgressStage
+	fn       types.ProgressFunc
+	n        int64
+	reported int64
+}
+
+func newProgressCounter(stage types.ProgressStage, fn types.ProgressFunc) *progressCounter {
+	return &progressCounter{stage: stage, fn: fn}
+}
+
+func (p *progressCounter) add(n int) {
+	p.n += int64(n)
+	if p.fn != nil && p.n-p.reported >= progressInterval {
+		p.report()
+	}
+}
+
+// finish reports the final total if it has not been reported yet
+func (p *progressCounter) finish() {
+	if p.fn != nil && p.n != p.reported {
+		p.report()
+	}
+}
+
+func (p *progressCounter) report() {
+	p.reported = p.n
+	p.fn(p.stage, fmt.Sprintf("%d bytes", p.n))
+}
+
+// Writer wraps w so that every write is counted
+func (p *progressCounter) Writer(w io.Writer) io.Writer {
+	return &countingWriter{w: w, counter: p}
+}
+
+// Reader wraps r so that every read is counted
+func (p *progressCounter) Reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, counter: p}
+}
+
+type countingWriter struct {
+	w       io.Writer
+	counter *progressCounter
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.counter.add(n)
+	return n, err
+}
+
+type countingReader struct {
+	r       io.Reader
+	counter *progressCounter
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.counter.add(n)
+	return n, err
+}
@@ -0,0 +1,150 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// Query runs query, a statement core.CheckReadOnlyQuery accepted, on dbName
+// and returns at most maxRows of its rows. The engine's command makes the
+// transaction read-only and bounds the statement by timeout; the client is
+// stopped as soon as a row beyond maxRows arrives.
+func (d *DockerDatabaseExecutor) Query(ctx context.Context, service string, dsn *types.DSN, dbName, query string, maxRows int, timeout time.Duration) (*types.QueryResult, error) {
+	start := time.Now()
+
+	readCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	execCmd := d.command(readCtx, service, dsn, d.engine.BuildQueryCommand(dsn, dbName, query, maxRows, timeout))
+	var stderr bytes.Buffer
+	execCmd.Stderr = &stderr
+	stdout, err := execCmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	if err := execCmd.Start(); err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	result, readErr := readQueryResult(stdout, d.engine.QueryFormat(), maxRows)
+	if readErr != nil || result.Truncated {
+		stop()
+	}
+	waitErr := execCmd.Wait()
+
+	if err := interrupted(ctx, "query"); err != nil {
+		return nil, err
+	}
+	switch {
+	case readErr != nil:
+		return nil, fmt.Errorf("unexpected query output: %w\nStderr: %s", readErr, stderr.String())
+	// A client stopped for the rows it had left exits with an error
+	case waitErr != nil && !result.Truncated:
+		return nil, fmt.Errorf("query failed: %w\nStderr: %s", waitErr, stderr.String())
+	}
+
+	result.Database = dbName
+	result.MaxRows = maxRows
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// readQueryResult reads the header and up to maxRows rows of the output of a
+// query command. It stops reading at the first row beyond maxRows.
+func readQueryResult(r io.Reader, format engines.QueryFormat, maxRows int) (*types.QueryResult, error) {
+	next, null := csvRecords(r), `\N`
+	if format == engines.QueryFormatTSV {
+		next, null = tsvRecords(r), "NULL"
+	}
+
+	result := &types.QueryResult{Columns: []string{}, Rows: [][]*string{}}
+	for header := true; ; header = false {
+		record, err := next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header {
+			result.Columns = record
+			continue
+		}
+		if len(record) != len(result.Columns) {
+			return nil, fmt.Errorf("row %d has %d fields, expected %d", len(result.Rows)+1, len(record), len(result.Columns))
+		}
+		if len(result.Rows) == maxRows {
+			result.Truncated = true
+			return result, nil
+		}
+
+		row := make([]*string, len(record))
+		for i := range record {
+			if record[i] != null {
+				row[i] = &record[i]
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+}
+
+func csvRecords(r io.Reader) func() ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	return reader.Read
+}
+
+// tsvRecords reads the batch output of the mysql client, one line per record
+func tsvRecords(r io.Reader) func() ([]string, error) {
+	reader := bufio.NewReader(r)
+	return func() ([]string, error) {
+		line, err := reader.ReadString('\n')
+		if line == "" {
+			return nil, err
+		}
+		fields := strings.Split(strings.TrimSuffix(line, "\n"), "\t")
+		for i, field := range fields {
+			fields[i] = unescapeBatch(field)
+		}
+		return fields, nil
+	}
+}
+
+// unescapeBatch decodes a field the mysql client escaped in batch mode
+func unescapeBatch(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' || i+1 == len(field) {
+			b.WriteByte(field[i])
+			continue
+		}
+		i++
+		switch field[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '0':
+			b.WriteByte(0)
+		default:
+			b.WriteByte(field[i])
+		}
+	}
+	return b.String()
+}
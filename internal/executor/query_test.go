@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// values turns the rows of a query result into strings, NULL for nil
+func values(rows [][]*string) [][]string {
+	result := make([][]string, len(rows))
+	for i, row := range rows {
+		for _, value := range row {
+			if value == nil {
+				result[i] = append(result[i], "NULL")
+			} else {
+				result[i] = append(result[i], *value)
+			}
+		}
+	}
+	return result
+}
+
+func TestReadQueryResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    engines.QueryFormat
+		output    string
+		columns   []string
+		rows      [][]string
+		truncated bool
+	}{
+		{
+			name:    "tsv unescapes fields",
+			format:  engines.QueryFormatTSV,
+			output:  "id\tnote\n1\tline\\none\\ttab\n2\tNULL\n",
+			columns: []string{"id", "note"},
+			rows:    [][]string{{"1", "line\none\ttab"}, {"2", "NULL"}},
+		},
+		{
+			name:    "csv keeps quoted fields",
+			format:  engines.QueryFormatCSV,
+			output:  "id,note\n1,\"a, \"\"b\"\"\nc\"\n2,\\N\n",
+			columns: []string{"id", "note"},
+			rows:    [][]string{{"1", "a, \"b\"\nc"}, {"2", "NULL"}},
+		},
+		{
+			name:      "rows beyond the cap are cut",
+			format:    engines.QueryFormatCSV,
+			output:    "id\n1\n2\n3\n4\n",
+			columns:   []string{"id"},
+			rows:      [][]string{{"1"}, {"2"}},
+			truncated: true,
+		},
+		{
+			name:    "empty output",
+			format:  engines.QueryFormatTSV,
+			output:  "",
+			columns: []string{},
+			rows:    [][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := readQueryResult(strings.NewReader(tt.output), tt.format, 2)
+			if err != nil {
+				t.Fatalf("readQueryResult() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Columns, tt.columns) {
+				t.Errorf("columns = %q, want %q", result.Columns, tt.columns)
+			}
+			if rows := values(result.Rows); !reflect.DeepEqual(rows, tt.rows) {
+				t.Errorf("rows = %q, want %q", rows, tt.rows)
+			}
+			if result.Truncated != tt.truncated {
+				t.Errorf("truncated = %v, want %v", result.Truncated, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestReadQueryResult_FieldCountMismatch(t *testing.T) {
+	_, err := readQueryResult(strings.NewReader("id\tname\n1\n"), engines.QueryFormatTSV, 10)
+	if err == nil || !strings.Contains(err.Error(), "row 1 has 1 fields, expected 2") {
+		t.Errorf("expected field count error, got %v", err)
+	}
+}
+
+func TestDockerDatabaseExecutor_Query(t *testing.T) {
+	fakeDocker(t, `case "$*" in
+  *"START TRANSACTION READ ONLY; SELECT id, email FROM users; ROLLBACK"*) printf 'id\temail\n1\ta@example.com\n2\tNULL\n3\tc@example.com\n' ;;
+  *) echo "unexpected: $*" >&2; exit 1 ;;
+esac`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	result, err := dbExecutor.Query(context.Background(), "database", dsn, "app_feature", "SELECT id, email FROM users", 2, time.Second)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if result.Database != "app_feature" || result.MaxRows != 2 || !result.Truncated {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if rows := values(result.Rows); !reflect.DeepEqual(rows, [][]string{{"1", "a@example.com"}, {"2", "NULL"}}) {
+		t.Errorf("unexpected rows %q", rows)
+	}
+}
+
+func TestDockerDatabaseExecutor_QueryFailed(t *testing.T) {
+	fakeDocker(t, `echo "ERROR 1146 (42S02): Table 'app.missing' doesn't exist" >&2; exit 1`)
+
+	dsn := &types.DSN{Host: "database", User: "root", Password: "secret", Database: "app"}
+	dbExecutor := NewDockerDatabaseExecutor(engines.NewMySQLEngine(false), Compose{}, t.TempDir())
+
+	_, err := dbExecutor.Query(context.Background(), "database", dsn, "app", "SELECT * FROM missing", 10, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "query failed") || !strings.Contains(err.Error(), "Table 'app.missing' doesn't exist") {
+		t.Errorf("expected query failure with stderr, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/executor/engines"
+)
+
+// dumpCommands resolves the patterns of filter against the tables of
+// dsn.Database and returns the engine's dump commands for the result
+func (d *DockerDatabaseExecutor) dumpCommands(ctx context.Context, service string, dsn *types.DSN, filter types.TableFilter) ([][]string, error) {
+	var existing []string
+	if hasTablePattern(filter.Tables, filter.ExcludeTables, filter.StructureOnly) {
+		tables, err := d.Tables(ctx, service, dsn, dsn.Database)
+		if err != nil {
+			return nil, err
+		}
+		existing = tables
+	}
+
+	spec := engines.DumpSpec{
+		Tables:        ExpandTablePatterns(filter.Tables, existing),
+		ExcludeTables: ExpandTablePatterns(filter.ExcludeTables, existing),
+		StructureOnly: ExpandTablePatterns(filter.StructureOnly, existing),
+		SchemaOnly:    filter.SchemaOnly,
+		DataOnly:      filter.DataOnly,
+	}
+	if len(filter.Tables) > 0 && len(spec.Tables) == 0 {
+		return nil, fmt.Errorf("no tables in %s match %s", dsn.Database, strings.Join(filter.Tables, ", "))
+	}
+
+	commands := d.engine.BuildDumpCommands(dsn, spec)
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("nothing to dump: every selected table of %s is excluded", dsn.Database)
+	}
+
+	return commands, nil
+}
+
+func hasTablePattern(lists ...[]string) bool {
+	for _, list := range lists {
+		for _, name := range list {
+			if isTablePattern(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTablePattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// ExpandTablePatterns replaces glob patterns such as "messenger_*" with the
+// matching names from tables, keeping plain names as they are and dropping duplicates
+func ExpandTablePatterns(patterns, tables []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if !isTablePattern(pattern) {
+			add(pattern)
+			continue
+		}
+		for _, table := range tables {
+			if ok, _ := path.Match(pattern, table); ok {
+				add(table)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandTablePatterns(t *testing.T) {
+	tables := []string{"users", "messenger_messages", "messenger_failed", "audit_log", "audit_log_archive"}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{"plain names are kept", []string{"users", "missing"}, []string{"users", "missing"}},
+		{"glob", []string{"messenger_*"}, []string{"messenger_messages", "messenger_failed"}},
+		{"duplicates removed", []string{"audit_*", "audit_log"}, []string{"audit_log", "audit_log_archive"}},
+		{"no match", []string{"cache_*"}, nil},
+		{"empty", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandTablePatterns(tt.patterns, tables); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ExpandTablePatterns(%v) = %v, want %v", tt.patterns, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package executor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// volumeHelperImage runs the throwaway containers that read and write volumes
+const volumeHelperImage = "alpine:3"
+
+// VolumeExecutor stops and starts a compose service and archives its named
+// volume through throwaway containers of the runtime. The archives are gzipped
+// tarballs streamed through the client, so no host directory is mounted and
+// they belong to the user running haive.
+type VolumeExecutor struct {
+	compose     Compose
+	projectRoot string
+}
+
+func NewVolumeExecutor(compose Compose, projectRoot string) *VolumeExecutor {
+	return &VolumeExecutor{compose: compose, projectRoot: projectRoot}
+}
+
+func (v *VolumeExecutor) command(ctx context.Context, cmdline []string) *exec.Cmd {
+	execCmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	execCmd.Dir = v.projectRoot
+	execCmd.Cancel = func() error {
+		return execCmd.Process.Signal(os.Interrupt)
+	}
+	execCmd.WaitDelay = pipeWaitDelay
+	return execCmd
+}
+
+// run runs cmdline and wraps a failure with its output for op
+func (v *VolumeExecutor) run(ctx context.Context, op string, cmdline []string) error {
+	output, err := v.command(ctx, cmdline).CombinedOutput()
+	if err != nil {
+		if err := interrupted(ctx, op); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s failed: %w\nOutput: %s", op, err, string(output))
+	}
+	return nil
+}
+
+func (v *VolumeExecutor) StopService(ctx context.Context, service string) error {
+	return v.run(ctx, "stop service", v.compose.Command("stop", service))
+}
+
+func (v *VolumeExecutor) StartService(ctx context.Context, service string) error {
+	return v.run(ctx, "start service", v.compose.Command("start", service))
+}
+
+// VolumeExists reports whether the runtime knows the volume
+func (v *VolumeExecutor) VolumeExists(ctx context.Context, volume string) (bool, error) {
+	var stderr bytes.Buffer
+	execCmd := v.command(ctx, v.compose.ContainerCommand("volume", "inspect", volume))
+	execCmd.Stderr = &stderr
+	if err := execCmd.Run(); err != nil {
+		if err := interrupted(ctx, "inspect volume"); err != nil {
+			return false, err
+		}
+		if _, ok := err.(*exec.ExitError); ok && strings.Contains(strings.ToLower(stderr.String()), "no such volume") {
+			return false, nil
+		}
+		return false, fmt.Errorf("inspect volume failed: %w\nStderr: %s", err, stderr.String())
+	}
+	return true, nil
+}
+
+// Archive writes a gzipped tarball of the volume to destPath. Like a dump, it
+// goes to a temporary file first, so a failure leaves no partial archive.
+func (v *VolumeExecutor) Archive(ctx context.Context, volume, destPath string) (int64, error) {
+	file, err := os.CreateTemp(filepath.Dir(destPath), filepath.Base(destPath)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	tmpPath := file.Name()
+
+	var stderr bytes.Buffer
+	execCmd := v.command(ctx, v.compose.ContainerCommand(
+		"run", "--rm", "-v", volume+":/volume:ro", volumeHelperImage,
+		"tar", "czf", "-", "-C", "/volume", ".",
+	))
+	execCmd.Stdout = file
+	execCmd.Stderr = &stderr
+
+	err = execCmd.Run()
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive file: %w", closeErr)
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		if err := interrupted(ctx, "archive volume"); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("archive volume failed: %w\nStderr: %s", err, stderr.String())
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to move archive into place: %w", err)
+	}
+
+	stat, err := os.Stat(destPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat archive file: %w", err)
+	}
+	return stat.Size(), nil
+}
+
+// Extract replaces the contents of the volume with the archive at sourcePath.
+// Check the archive with VerifyArchive first: a truncated file fails only
+// after the volume was emptied.
+func (v *VolumeExecutor) Extract(ctx context.Context, volume, sourcePath string) error {
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer file.Close()
+
+	execCmd := v.command(ctx, v.compose.ContainerCommand(
+		"run", "--rm", "-i", "-v", volume+":/volume", volumeHelperImage,
+		"sh", "-c", "find /volume -mindepth 1 -delete && tar xzf - -C /volume",
+	))
+	execCmd.Stdin = file
+	output, err := execCmd.CombinedOutput()
+	if err != nil {
+		if err := interrupted(ctx, "restore volume"); err != nil {
+			return err
+		}
+		return fmt.Errorf("restore volume failed: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// VerifyArchive reads the gzipped tarball at path to its end
+func VerifyArchive(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("archive %s is corrupt: %w", filepath.Base(path), err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		_, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			_, err = io.Copy(io.Discard, tr)
+		}
+		if err != nil {
+			return fmt.Errorf("archive %s is corrupt: %w", filepath.Base(path), err)
+		}
+	}
+	return gz.Close()
+}
@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeArchive writes a gzipped tarball holding one file to path
+func writeArchive(t *testing.T, path string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	content := []byte("ibdata")
+	if err := tw.WriteHeader(&tar.Header{Name: "./ibdata1", Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyArchive(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.tar.gz")
+	data := writeArchive(t, valid)
+
+	if err := VerifyArchive(valid); err != nil {
+		t.Errorf("VerifyArchive() on a valid archive = %v", err)
+	}
+
+	truncated := filepath.Join(dir, "truncated.tar.gz")
+	if err := os.WriteFile(truncated, data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyArchive(truncated); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("VerifyArchive() on a truncated archive = %v, want corrupt error", err)
+	}
+
+	plain := filepath.Join(dir, "plain.tar.gz")
+	if err := os.WriteFile(plain, []byte("CREATE TABLE t (id int);\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyArchive(plain); err == nil {
+		t.Error("VerifyArchive() on a non-gzip file succeeded")
+	}
+}
+
+func TestVolumeExecutor_Archive(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "source.tar.gz")
+	writeArchive(t, source)
+	fakeDocker(t, `case "$*" in
+  "run --rm -v app_db-data:/volume:ro alpine:3 tar czf - -C /volume .") cat "`+source+`" ;;
+  *) echo "unexpected: $*" >&2; exit 1 ;;
+esac`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "seeded.tar.gz")
+	v := NewVolumeExecutor(Compose{Runtime: "docker", Files: []string{"compose.yaml"}}, dir)
+
+	size, err := v.Archive(context.Background(), "app_db-data", destPath)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	stat, err := os.Stat(destPath)
+	if err != nil {
+		t.Fatalf("archive not written: %v", err)
+	}
+	if size != stat.Size() || size == 0 {
+		t.Errorf("Archive() size = %d, file has %d bytes", size, stat.Size())
+	}
+	if err := VerifyArchive(destPath); err != nil {
+		t.Errorf("written archive is invalid: %v", err)
+	}
+}
+
+func TestVolumeExecutor_ArchiveFailureLeavesNoFile(t *testing.T) {
+	fakeDocker(t, `echo "partial"; echo "Unable to find image" >&2; exit 125`)
+
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "seeded.tar.gz")
+	v := NewVolumeExecutor(Compose{Runtime: "docker", Files: []string{"compose.yaml"}}, dir)
+
+	if _, err := v.Archive(context.Background(), "app_db-data", destPath); err == nil {
+		t.Fatal("Archive() succeeded, want error")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("failed archive left files behind: %v", entries)
+	}
+}
+
+func TestVolumeExecutor_VolumeExists(t *testing.T) {
+	fakeDocker(t, `case "$3" in
+  app_db-data) echo "[{}]" ;;
+  *) echo "Error response from daemon: get $3: no such volume" >&2; exit 1 ;;
+esac`)
+
+	v := NewVolumeExecutor(Compose{Runtime: "docker", Files: []string{"compose.yaml"}}, t.TempDir())
+
+	exists, err := v.VolumeExists(context.Background(), "app_db-data")
+	if err != nil || !exists {
+		t.Errorf("VolumeExists(app_db-data) = %v, %v, want true", exists, err)
+	}
+
+	exists, err = v.VolumeExists(context.Background(), "missing")
+	if err != nil || exists {
+		t.Errorf("VolumeExists(missing) = %v, %v, want false", exists, err)
+	}
+}
@@ -1,18 +1,106 @@
 package executor
 
-import "github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
 
-func GitWorktreeList() ([]types.WorktreeInfo, error) {
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
+)
+
+// GitWorktreeList returns the worktrees of the repository at dir
+func GitWorktreeList(ctx context.Context, dir string) ([]types.WorktreeInfo, error) {
 	executor := NewGitExecutor()
-	return executor.GitWorktreeList()
+	return executor.GitWorktreeList(ctx, dir)
 }
 
-func GitWorktreeAdd(path, branch string, newBranch bool) error {
+func GitWorktreeAdd(ctx context.Context, path, branch string, newBranch bool) error {
 	executor := NewGitExecutor()
-	return executor.GitWorktreeAdd(path, branch, newBranch)
+	return executor.GitWorktreeAdd(ctx, path, branch, newBranch)
 }
 
-func GitWorktreeRemove(path string) error {
+func GitWorktreeRemove(ctx context.Context, path string) error {
 	executor := NewGitExecutor()
-	return executor.GitWorktreeRemove(path)
+	return executor.GitWorktreeRemove(ctx, path)
+}
+
+// GitHead returns the checked out branch and commit hash of the repository at dir.
+// The branch is "detached" when HEAD does not point at a branch.
+func GitHead(ctx context.Context, dir string) (branch, commit string, err error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	commit = strings.TrimSpace(string(output))
+
+	cmd = exec.CommandContext(ctx, "git", "-C", dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	output, err = cmd.Output()
+	if err != nil {
+		return "detached", commit, nil
+	}
+
+	return strings.TrimSpace(string(output)), commit, nil
+}
+
+// GitBranches returns the local branches of the repository at dir
+func GitBranches(ctx context.Context, dir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
+
+// GitCheckedOutBranches returns the branches the HEAD reflog of the
+// repository at dir moved from or to, deleted branches included, as far back
+// as the reflog goes
+func GitCheckedOutBranches(ctx context.Context, dir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "reflog", "show", "--format=%gs", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git reflog failed: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var branches []string
+	for _, line := range strings.Split(string(output), "\n") {
+		rest, ok := strings.CutPrefix(line, "checkout: moving from ")
+		if !ok {
+			continue
+		}
+		from, to, ok := strings.Cut(rest, " to ")
+		if !ok {
+			continue
+		}
+		for _, branch := range []string{from, to} {
+			if !seen[branch] {
+				seen[branch] = true
+				branches = append(branches, branch)
+			}
+		}
+	}
+
+	return branches, nil
+}
+
+// GitConfigValue returns the value of key as the worktree at dir sees it,
+// "" when the key is not set
+func GitConfigValue(ctx context.Context, dir, key string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "config", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		// git config exits with 1 for a missing key
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("git config failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
 }
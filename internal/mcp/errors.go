@@ -3,6 +3,7 @@ package mcp
 import (
 	"fmt"
 
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
@@ -14,6 +15,11 @@ const (
 	ErrCodeDbNotAllowed  = -32005
 	ErrCodeDbIsDefault   = -32006
 	ErrCodeFileNotFound  = -32007
+	ErrCodeAlreadyExists = -32008
+	ErrCodeCanceled      = -32009
+	ErrCodeTimeout       = -32010
+	ErrCodeServiceShared = -32011
+	ErrCodeDumpInvalid   = -32012
 )
 
 func toMCPCode(code types.ErrCode) int {
@@ -32,15 +38,27 @@ func toMCPCode(code types.ErrCode) int {
 		return ErrCodeDbIsDefault
 	case types.ErrFileNotFound:
 		return ErrCodeFileNotFound
+	case types.ErrAlreadyExists:
+		return ErrCodeAlreadyExists
+	case types.ErrCanceled:
+		return ErrCodeCanceled
+	case types.ErrTimeout:
+		return ErrCodeTimeout
+	case types.ErrServiceShared:
+		return ErrCodeServiceShared
+	case types.ErrDumpInvalid:
+		return ErrCodeDumpInvalid
 	default:
 		return -32000
 	}
 }
 
+// toMCPError converts err for the MCP client. Messages are redacted, since
+// executor errors carry the raw output of the database client.
 func toMCPError(err error) error {
 	if cmdErr, ok := err.(*types.CommandError); ok {
 		return fmt.Errorf("mcp error %d: %s (data: {\"code\":\"%s\"})",
-			toMCPCode(cmdErr.Code), cmdErr.Message, cmdErr.Code)
+			toMCPCode(cmdErr.Code), cmdErr.Error(), cmdErr.Code)
 	}
-	return fmt.Errorf("mcp error -32000: %s", err.Error())
+	return fmt.Errorf("mcp error -32000: %s", redact.String(err.Error()))
 }
@@ -2,12 +2,13 @@ package mcp
 
 import (
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core"
 )
 
 func Run() error {
 	s := server.NewMCPServer(
 		"mcp-project-manager",
-		"1.0.0",
+		core.Version,
 		server.WithToolCapabilities(true),
 	)
 
@@ -14,13 +14,49 @@ func registerDatabaseTools(s *server.MCPServer) {
 	s.AddTool(mcp.NewTool("db.list",
 		mcp.WithDescription("List all databases in the container"),
 		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithBoolean("stats", mcp.Description("Include size on disk, table count, row estimate and last change of each database (optional, defaults to false)")),
+		mcp.WithBoolean("migrations", mcp.Description("Compare each database with the project's Doctrine migrations: pending and unknown versions (optional, defaults to false)")),
 	), handleDbList)
 
+	s.AddTool(mcp.NewTool("db.stats",
+		mcp.WithDescription("Show the size of a database and its largest tables with row estimates, data and index sizes"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("database", mcp.Description("Database name (optional, defaults to DSN database)")),
+		mcp.WithNumber("limit", mcp.Description("Number of tables to list (optional, defaults to 20, 0 lists all)")),
+	), handleDbStats)
+
+	s.AddTool(mcp.NewTool("db.diff",
+		mcp.WithDescription("Compare the schemas of two databases: tables, columns, types, indexes and foreign keys. Read-only"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("from", mcp.Description("Database the changes apply to (optional, defaults to DSN database)")),
+		mcp.WithString("to", mcp.Required(), mcp.Description("Database whose schema the changes lead to")),
+		mcp.WithBoolean("statements", mcp.Description("Include the ALTER statements that turn the schema of from into that of to (optional, defaults to false)")),
+	), handleDbDiff)
+
+	s.AddTool(mcp.NewTool("db.migrations",
+		mcp.WithDescription("Compare the Doctrine migrations executed on a database with the migration classes of the project: pending migrations and executed versions without a class. Read-only"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("database", mcp.Description("Database name (optional, defaults to DSN database)")),
+	), handleDbMigrations)
+
+	s.AddTool(mcp.NewTool("db.query",
+		mcp.WithDescription("Run a single read-only SELECT, SHOW, EXPLAIN or DESCRIBE statement and return its columns and rows. Writes are rejected and the statement runs in a read-only transaction"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("query", mcp.Required(), mcp.Description("The SQL statement, e.g. SELECT id, email FROM user ORDER BY id DESC")),
+		mcp.WithString("database", mcp.Description("Database name (optional, defaults to DSN database)")),
+		mcp.WithNumber("max_rows", mcp.Description("Maximum number of rows to return (optional, defaults to 100, at most 1000)")),
+	), handleDbQuery)
+
 	s.AddTool(mcp.NewTool("db.dump",
 		mcp.WithDescription("Dump a database to a SQL file"),
 		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
 		mcp.WithString("database", mcp.Description("Database name (optional, defaults to DSN database)")),
-		mcp.WithArray("tables", mcp.Description("Specific tables to dump (optional)")),
+		mcp.WithArray("tables", mcp.Description("Specific tables to dump, glob patterns allowed (optional)")),
+		mcp.WithArray("exclude_tables", mcp.Description("Tables to leave out, glob patterns like messenger_* allowed (optional)")),
+		mcp.WithArray("structure_only", mcp.Description("Tables to dump without their rows, e.g. large log tables (optional)")),
+		mcp.WithBoolean("schema_only", mcp.Description("Dump table definitions only (optional, defaults to false)")),
+		mcp.WithBoolean("data_only", mcp.Description("Dump rows only, without CREATE statements (optional, defaults to false)")),
+		mcp.WithBoolean("anonymize", mcp.Description("Apply the database.anonymize rules to the dumped rows (optional, defaults to false)")),
 	), handleDbDump)
 
 	s.AddTool(mcp.NewTool("db.import",
@@ -28,6 +64,7 @@ func registerDatabaseTools(s *server.MCPServer) {
 		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
 		mcp.WithString("database", mcp.Required(), mcp.Description("Target database name")),
 		mcp.WithString("sql_path", mcp.Required(), mcp.Description("Path to SQL file")),
+		mcp.WithArray("tables", mcp.Description("Only import these tables from the file, glob patterns allowed; the other tables are left as they are (optional)")),
 	), handleDbImport)
 
 	s.AddTool(mcp.NewTool("db.create",
@@ -43,22 +80,171 @@ func registerDatabaseTools(s *server.MCPServer) {
 		mcp.WithBoolean("confirm", mcp.Required(), mcp.Description("Must be true to confirm destructive operation")),
 	), handleDbDrop)
 
+	s.AddTool(mcp.NewTool("db.orphans",
+		mcp.WithDescription("Find databases named after worktrees or branches that no longer exist, e.g. after git worktree remove, and optionally drop them (destructive with drop). Drops run the preDrop hooks"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithArray("keep", mcp.Description("Databases to leave alone, glob patterns allowed (optional)")),
+		mcp.WithBoolean("drop", mcp.Description("Drop the orphaned databases (optional, defaults to false)")),
+		mcp.WithBoolean("confirm", mcp.Description("Must be true to confirm destructive operation when drop is set")),
+	), handleDbOrphans)
+
 	s.AddTool(mcp.NewTool("db.clone",
 		mcp.WithDescription("Clone a database (dump + create + import)"),
 		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
 		mcp.WithString("source", mcp.Description("Source database (optional, defaults to DSN database)")),
 		mcp.WithString("target", mcp.Required(), mcp.Description("Target database name")),
+		mcp.WithArray("tables", mcp.Description("Only copy these tables, glob patterns allowed (optional)")),
+		mcp.WithArray("exclude_tables", mcp.Description("Tables to leave out, glob patterns like messenger_* allowed (optional)")),
+		mcp.WithArray("structure_only", mcp.Description("Tables to copy without their rows, e.g. large log tables (optional)")),
+		mcp.WithBoolean("schema_only", mcp.Description("Copy table definitions only (optional, defaults to false)")),
+		mcp.WithBoolean("anonymize", mcp.Description("Apply the database.anonymize rules to the copied rows (optional, defaults to false)")),
 	), handleDbClone)
 
 	s.AddTool(mcp.NewTool("db.dumps",
-		mcp.WithDescription("List available SQL dump files"),
+		mcp.WithDescription("List available SQL dump files with their recorded metadata (database, engine, git branch, checksum)"),
 		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
 	), handleDbDumps)
+
+	s.AddTool(mcp.NewTool("db.dumps_prune",
+		mcp.WithDescription("Report the dump files the database.retention policy would remove, and remove them with confirm (destructive with confirm)"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithBoolean("dry_run", mcp.Description("Only report which dumps would be removed, even with confirm (optional, defaults to true without confirm)")),
+		mcp.WithBoolean("confirm", mcp.Description("Must be true to remove the dumps, otherwise they are only reported")),
+	), handleDbDumpsPrune)
+
+	s.AddTool(mcp.NewTool("db.dumps_verify",
+		mcp.WithDescription("Check every dump, snapshot and volume snapshot in the dumps directory: readable, matching its recorded checksum and not truncated"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+	), handleDbDumpsVerify)
+
+	s.AddTool(mcp.NewTool("db.dump_inspect",
+		mcp.WithDescription("Read a dump file without importing it: its tables with approximate row counts, sizes and CREATE statements, the dumped server version and charset"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("file", mcp.Required(), mcp.Description("Dump file relative to the dumps directory, as listed by db.dumps")),
+	), handleDbDumpInspect)
+
+	s.AddTool(mcp.NewTool("db.snapshot",
+		mcp.WithDescription("Save a named snapshot of a database"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snapshot name (letters, digits, '.', '_' and '-')")),
+		mcp.WithString("database", mcp.Description("Database name (optional, defaults to DSN database)")),
+	), handleDbSnapshot)
+
+	s.AddTool(mcp.NewTool("db.restore",
+		mcp.WithDescription("Restore a database from a named snapshot (destructive). The snapshot is imported into a scratch database and only swapped in after a successful import"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snapshot name")),
+		mcp.WithString("database", mcp.Description("Database name (optional, defaults to DSN database)")),
+		mcp.WithBoolean("confirm", mcp.Required(), mcp.Description("Must be true to confirm destructive operation")),
+	), handleDbRestore)
+
+	s.AddTool(mcp.NewTool("db.snapshots",
+		mcp.WithDescription("List named snapshots per database"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("database", mcp.Description("Only list snapshots of this database (optional)")),
+	), handleDbSnapshots)
+
+	s.AddTool(mcp.NewTool("db.trash",
+		mcp.WithDescription("List the backups taken before drops and imports when database.backup_before_destructive is set, newest first"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+	), handleDbTrash)
+
+	s.AddTool(mcp.NewTool("db.undo",
+		mcp.WithDescription("Restore the latest backup in the trash into the database it was taken of, undoing a drop or import (destructive, replaces what the database holds now). The backup leaves the trash"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("database", mcp.Description("Only consider backups of this database (optional, defaults to the latest backup of any database)")),
+		mcp.WithBoolean("confirm", mcp.Required(), mcp.Description("Must be true to confirm destructive operation")),
+	), handleDbUndo)
+
+	s.AddTool(mcp.NewTool("db.volume_snapshot",
+		mcp.WithDescription("Stop the database service and archive its named data volume into the dumps directory. Refused while other worktrees share the service"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Snapshot name (letters, digits, '.', '_' and '-')")),
+	), handleDbVolumeSnapshot)
+
+	s.AddTool(mcp.NewTool("db.volume_restore",
+		mcp.WithDescription("Stop the database service and replace its data volume with a volume snapshot (destructive, replaces every database of the service). Refused while other worktrees share the service"),
+		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Volume snapshot name")),
+		mcp.WithBoolean("confirm", mcp.Required(), mcp.Description("Must be true to confirm destructive operation")),
+	), handleDbVolumeRestore)
 }
 
 func handleDbList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	projectRoot := getProjectRoot(request)
-	result, err := commands.ListDBs(projectRoot)
+	args := request.GetArguments()
+	stats, _ := args["stats"].(bool)
+	migrations, _ := args["migrations"].(bool)
+	result, err := commands.ListDBs(ctx, projectRoot, stats, migrations)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	database, _ := args["database"].(string)
+	limit := 20
+	if v, ok := args["limit"].(float64); ok {
+		limit = int(v)
+	}
+
+	result, err := commands.DatabaseStats(ctx, projectRoot, database, limit)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbDiff(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	from, _ := args["from"].(string)
+	to, _ := args["to"].(string)
+	statements, _ := args["statements"].(bool)
+
+	result, err := commands.DiffSchemas(ctx, projectRoot, from, to, statements)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbMigrations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	database, _ := request.GetArguments()["database"].(string)
+
+	result, err := commands.DatabaseMigrations(ctx, projectRoot, database)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	query, _ := args["query"].(string)
+	database, _ := args["database"].(string)
+	maxRows := 0
+	if v, ok := args["max_rows"].(float64); ok {
+		maxRows = int(v)
+	}
+
+	result, err := commands.QueryDB(ctx, projectRoot, database, query, maxRows)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -76,16 +262,12 @@ func handleDbDump(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallTo
 		database = v
 	}
 
-	var tables []string
-	if v, ok := args["tables"].([]interface{}); ok {
-		for _, t := range v {
-			if s, ok := t.(string); ok {
-				tables = append(tables, s)
-			}
-		}
-	}
+	opts := types.DumpOptions{TableFilter: tableFilterArgs(args)}
+	opts.DataOnly, _ = args["data_only"].(bool)
+	opts.Anonymize, _ = args["anonymize"].(bool)
+	opts.Progress = progressNotifier(ctx, request)
 
-	result, err := commands.Dump(projectRoot, database, tables)
+	result, err := commands.Dump(ctx, projectRoot, database, opts)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -94,14 +276,58 @@ func handleDbDump(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallTo
 	return mcp.NewToolResultText(string(data)), nil
 }
 
+// tableFilterArgs reads the table selection arguments shared by db.dump and db.clone
+func tableFilterArgs(args map[string]interface{}) types.TableFilter {
+	var filter types.TableFilter
+	filter.Tables = stringArrayArg(args, "tables")
+	filter.ExcludeTables = stringArrayArg(args, "exclude_tables")
+	filter.StructureOnly = stringArrayArg(args, "structure_only")
+	filter.SchemaOnly, _ = args["schema_only"].(bool)
+	return filter
+}
+
+// progressNotifier returns a ProgressFunc that sends the client progress
+// notifications for request, nil when the client asked for none
+func progressNotifier(ctx context.Context, request mcp.CallToolRequest) types.ProgressFunc {
+	s := server.ServerFromContext(ctx)
+	if s == nil || request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+
+	token := request.Params.Meta.ProgressToken
+	reports := 0
+	return func(stage types.ProgressStage, detail string) {
+		reports++
+		// A client that went away only misses the report
+		_ = s.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      reports,
+			"message":       string(stage) + ": " + detail,
+		})
+	}
+}
+
+func stringArrayArg(args map[string]interface{}, key string) []string {
+	var values []string
+	if v, ok := args[key].([]interface{}); ok {
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
 func handleDbImport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	projectRoot := getProjectRoot(request)
 	args := request.GetArguments()
 
 	database := args["database"].(string)
 	sqlPath := args["sql_path"].(string)
+	tables := stringArrayArg(args, "tables")
 
-	result, err := commands.ImportDB(projectRoot, database, sqlPath)
+	result, err := commands.ImportDB(ctx, projectRoot, database, sqlPath, tables, progressNotifier(ctx, request))
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -115,7 +341,7 @@ func handleDbCreate(ctx context.Context, request mcp.CallToolRequest) (*mcp.Call
 	args := request.GetArguments()
 	database := args["database"].(string)
 
-	result, err := commands.CreateDB(projectRoot, database)
+	result, err := commands.CreateDB(ctx, projectRoot, database)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -138,7 +364,30 @@ func handleDbDrop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallTo
 	projectRoot := getProjectRoot(request)
 	database := args["database"].(string)
 
-	result, err := commands.DropDB(projectRoot, database)
+	result, err := commands.DropDB(ctx, projectRoot, database)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbOrphans(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	drop, _ := args["drop"].(bool)
+	confirm, _ := args["confirm"].(bool)
+	if drop && !confirm {
+		return nil, toMCPError(&types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "confirm must be true to drop orphaned databases",
+		})
+	}
+
+	projectRoot := getProjectRoot(request)
+
+	result, err := commands.OrphanDBs(ctx, projectRoot, stringArrayArg(args, "keep"), drop)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -156,8 +405,10 @@ func handleDbClone(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 		source = v
 	}
 	target := args["target"].(string)
+	opts := types.CloneOptions{TableFilter: tableFilterArgs(args)}
+	opts.Anonymize, _ = args["anonymize"].(bool)
 
-	result, err := commands.CloneDB(projectRoot, source, target)
+	result, err := commands.CloneDB(ctx, projectRoot, source, target, opts)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -176,3 +427,183 @@ func handleDbDumps(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallT
 	data, _ := json.MarshalIndent(result, "", "  ")
 	return mcp.NewToolResultText(string(data)), nil
 }
+
+func handleDbDumpsPrune(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	// Without confirm nothing is removed
+	dryRun, _ := args["dry_run"].(bool)
+	confirm, _ := args["confirm"].(bool)
+
+	result, err := commands.PruneDumps(projectRoot, dryRun || !confirm)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbDumpsVerify(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	result, err := commands.VerifyDumps(ctx, projectRoot)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbDumpInspect(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	file := args["file"].(string)
+
+	result, err := commands.InspectDump(ctx, projectRoot, file)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	name := args["name"].(string)
+	database := ""
+	if v, ok := args["database"].(string); ok {
+		database = v
+	}
+
+	result, err := commands.Snapshot(ctx, projectRoot, database, name)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbRestore(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	confirm, _ := args["confirm"].(bool)
+	if !confirm {
+		return nil, toMCPError(&types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "confirm must be true to restore database",
+		})
+	}
+
+	projectRoot := getProjectRoot(request)
+	name := args["name"].(string)
+	database := ""
+	if v, ok := args["database"].(string); ok {
+		database = v
+	}
+
+	result, err := commands.RestoreSnapshot(ctx, projectRoot, database, name)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbSnapshots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	database := ""
+	if v, ok := args["database"].(string); ok {
+		database = v
+	}
+
+	result, err := commands.ListSnapshots(projectRoot, database)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbTrash(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+
+	result, err := commands.ListTrash(projectRoot)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbUndo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	confirm, _ := args["confirm"].(bool)
+	if !confirm {
+		return nil, toMCPError(&types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "confirm must be true to undo",
+		})
+	}
+
+	projectRoot := getProjectRoot(request)
+	database, _ := args["database"].(string)
+
+	result, err := commands.Undo(ctx, projectRoot, database)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbVolumeSnapshot(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectRoot := getProjectRoot(request)
+	args := request.GetArguments()
+
+	name := args["name"].(string)
+
+	result, err := commands.VolumeSnapshot(ctx, projectRoot, name)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func handleDbVolumeRestore(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	confirm, _ := args["confirm"].(bool)
+	if !confirm {
+		return nil, toMCPError(&types.CommandError{
+			Code:    types.ErrConfigInvalid,
+			Message: "confirm must be true to restore volume",
+		})
+	}
+
+	projectRoot := getProjectRoot(request)
+	name := args["name"].(string)
+
+	result, err := commands.VolumeRestore(ctx, projectRoot, name)
+	if err != nil {
+		return nil, toMCPError(err)
+	}
+
+	data, _ := json.MarshalIndent(result, "", "  ")
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -37,7 +37,7 @@ func handleWorkflowCreate(ctx context.Context, request mcp.CallToolRequest) (*mc
 		newBranch = "true"
 	}
 
-	result, err := commands.CreateIsolatedWorktree(projectRoot, branch, newBranch, "")
+	result, err := commands.CreateIsolatedWorktree(ctx, projectRoot, branch, newBranch, "")
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -65,7 +65,7 @@ func handleWorkflowRemove(ctx context.Context, request mcp.CallToolRequest) (*mc
 		dropDB = v
 	}
 
-	result, err := commands.RemoveIsolatedWorktree(projectRoot, branch, dropDB)
+	result, err := commands.RemoveIsolatedWorktree(ctx, projectRoot, branch, dropDB)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -14,6 +14,7 @@ func registerWorktreeTools(s *server.MCPServer) {
 	s.AddTool(mcp.NewTool("worktree.list",
 		mcp.WithDescription("List all git worktrees"),
 		mcp.WithString("project_root", mcp.Description("Project root directory (optional, defaults to cwd)")),
+		mcp.WithBoolean("migrations", mcp.Description("Compare the Doctrine migrations of each worktree with the database it uses: pending and unknown versions (optional, defaults to false)")),
 	), handleWorktreeList)
 
 	s.AddTool(mcp.NewTool("worktree.create",
@@ -33,7 +34,8 @@ func registerWorktreeTools(s *server.MCPServer) {
 
 func handleWorktreeList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	projectRoot := getProjectRoot(request)
-	result, err := commands.List(projectRoot)
+	migrations, _ := request.GetArguments()["migrations"].(bool)
+	result, err := commands.List(ctx, projectRoot, migrations)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -52,7 +54,7 @@ func handleWorktreeCreate(ctx context.Context, request mcp.CallToolRequest) (*mc
 		newBranch = v
 	}
 
-	result, err := commands.Create(projectRoot, branch, newBranch)
+	result, err := commands.Create(ctx, projectRoot, branch, newBranch)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
@@ -75,7 +77,7 @@ func handleWorktreeRemove(ctx context.Context, request mcp.CallToolRequest) (*mc
 	projectRoot := getProjectRoot(request)
 	branch := args["branch"].(string)
 
-	result, err := commands.Remove(projectRoot, branch)
+	result, err := commands.Remove(ctx, projectRoot, branch)
 	if err != nil {
 		return nil, toMCPError(err)
 	}
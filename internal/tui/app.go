@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -10,6 +11,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/commands"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/redact"
+	"github.com/mkrowiarz/mcp-symfony-stack/internal/core/types"
 )
 
 type inputMode string
@@ -59,6 +62,32 @@ type Model struct {
 	confirmPath   string
 
 	importDumpName string
+
+	running *operation
+}
+
+// operation tracks the command that is currently running so Ctrl+C can cancel it
+type operation struct {
+	cancel context.CancelFunc
+}
+
+// start returns the context of a new operation
+func (o *operation) start() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	o.cancel = cancel
+	return ctx
+}
+
+func (o *operation) active() bool {
+	return o.cancel != nil
+}
+
+// stop cancels the operation if it is still running
+func (o *operation) stop() {
+	if o.cancel != nil {
+		o.cancel()
+		o.cancel = nil
+	}
 }
 
 const maxVisibleLines = 6
@@ -70,6 +99,7 @@ func NewModel() Model {
 		projectName:   "Loading...",
 		selectedIndex: map[int]int{1: 0, 2: 0, 3: 0, 4: 0},
 		scrollOffset:  map[int]int{1: 0, 2: 0, 3: 0, 4: 0},
+		running:       &operation{},
 	}
 }
 
@@ -80,7 +110,7 @@ func (m Model) Init() tea.Cmd {
 func (m Model) loadProject() tea.Msg {
 	info, err := commands.Info(m.projectRoot)
 	if err != nil {
-		return projectLoadedMsg{name: "Error", ptype: err.Error(), status: "✗"}
+		return projectLoadedMsg{name: "Error", ptype: redact.String(err.Error()), status: "✗"}
 	}
 
 	name := "Project"
@@ -94,7 +124,7 @@ func (m Model) loadProject() tea.Msg {
 }
 
 func (m Model) loadWorktrees() tea.Msg {
-	result, err := commands.List(m.projectRoot)
+	result, err := commands.List(context.Background(), m.projectRoot, false)
 	if err != nil {
 		return worktreesLoadedMsg{worktrees: []worktreeInfo{}}
 	}
@@ -111,17 +141,24 @@ func (m Model) loadWorktrees() tea.Msg {
 }
 
 func (m Model) loadDatabases() tea.Msg {
-	result, err := commands.ListDBs(m.projectRoot)
+	result, err := commands.ListDBs(context.Background(), m.projectRoot, true, false)
 	if err != nil {
 		return databasesLoadedMsg{databases: []databaseInfo{}}
 	}
 
 	var dbis []databaseInfo
 	for _, db := range result.Databases {
-		dbis = append(dbis, databaseInfo{
+		dbi := databaseInfo{
 			name:      db.Name,
 			isDefault: db.IsDefault,
-		})
+		}
+		if db.Stats != nil {
+			dbi.stats = fmt.Sprintf("%s, %d tables", formatSize(db.Stats.Size), db.Stats.TableCount)
+			if db.Stats.Modified != "" {
+				dbi.stats += ", " + formatDate(db.Stats.Modified)
+			}
+		}
+		dbis = append(dbis, dbi)
 	}
 	return databasesLoadedMsg{databases: dbis}
 }
@@ -135,9 +172,10 @@ func (m Model) loadDumps() tea.Msg {
 	var dis []dumpInfo
 	for _, d := range result.Dumps {
 		dis = append(dis, dumpInfo{
-			name: d.Name,
-			size: formatSize(d.Size),
-			date: formatDate(d.Modified),
+			name:     d.Name,
+			database: d.Database,
+			size:     formatSize(d.Size),
+			date:     formatDate(d.Modified),
 		})
 	}
 	return dumpsLoadedMsg{dumps: dis}
@@ -198,6 +236,9 @@ func (m Model) statusBarText() string {
 	if m.confirmMode != confirmModeNone {
 		return "Confirm " + string(m.confirmMode) + "? [y]es [n]o"
 	}
+	if m.running.active() {
+		return "[Ctrl+C]cancel"
+	}
 	switch m.focusedPane {
 	case 2:
 		return "[n]ew [x]remove [o]pen [?]help [q]uit"
@@ -211,8 +252,9 @@ func (m Model) statusBarText() string {
 }
 
 func (m Model) dumpDatabase(dbName string) tea.Cmd {
+	ctx := m.running.start()
 	return func() tea.Msg {
-		result, err := commands.Dump(m.projectRoot, dbName, nil)
+		result, err := commands.Dump(ctx, m.projectRoot, dbName, types.DumpOptions{})
 		if err != nil {
 			return dumpFinishedMsg{err: err}
 		}
@@ -221,8 +263,9 @@ func (m Model) dumpDatabase(dbName string) tea.Cmd {
 }
 
 func (m Model) cloneDatabase(sourceDB, targetDB string) tea.Cmd {
+	ctx := m.running.start()
 	return func() tea.Msg {
-		result, err := commands.CloneDB(m.projectRoot, sourceDB, targetDB)
+		result, err := commands.CloneDB(ctx, m.projectRoot, sourceDB, targetDB, types.CloneOptions{})
 		if err != nil {
 			return cloneFinishedMsg{err: err}
 		}
@@ -231,8 +274,9 @@ func (m Model) cloneDatabase(sourceDB, targetDB string) tea.Cmd {
 }
 
 func (m Model) dropDatabase(dbName string) tea.Cmd {
+	ctx := m.running.start()
 	return func() tea.Msg {
-		result, err := commands.DropDB(m.projectRoot, dbName)
+		result, err := commands.DropDB(ctx, m.projectRoot, dbName)
 		if err != nil {
 			return dropFinishedMsg{err: err}
 		}
@@ -241,8 +285,9 @@ func (m Model) dropDatabase(dbName string) tea.Cmd {
 }
 
 func (m Model) importDump(dbName, dumpName string) tea.Cmd {
+	ctx := m.running.start()
 	return func() tea.Msg {
-		result, err := commands.ImportDB(m.projectRoot, dbName, dumpName)
+		result, err := commands.ImportDB(ctx, m.projectRoot, dbName, dumpName, nil, nil)
 		if err != nil {
 			return importFinishedMsg{err: err}
 		}
@@ -251,8 +296,9 @@ func (m Model) importDump(dbName, dumpName string) tea.Cmd {
 }
 
 func (m Model) createWorktree(branch string) tea.Cmd {
+	ctx := m.running.start()
 	return func() tea.Msg {
-		result, err := commands.Create(m.projectRoot, branch, true)
+		result, err := commands.Create(ctx, m.projectRoot, branch, true)
 		if err != nil {
 			return worktreeCreatedMsg{err: err}
 		}
@@ -261,8 +307,9 @@ func (m Model) createWorktree(branch string) tea.Cmd {
 }
 
 func (m Model) removeWorktree(branch string) tea.Cmd {
+	ctx := m.running.start()
 	return func() tea.Msg {
-		result, err := commands.Remove(m.projectRoot, branch)
+		result, err := commands.Remove(ctx, m.projectRoot, branch)
 		if err != nil {
 			return worktreeRemovedMsg{err: err}
 		}
@@ -309,6 +356,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.dumps = msg.dumps
 
 	case dumpFinishedMsg:
+		m.running.stop()
 		m.showModal = false
 		if msg.err != nil {
 			m.showError = true
@@ -323,6 +371,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 
 	case cloneFinishedMsg:
+		m.running.stop()
 		m.showModal = false
 		if msg.err != nil {
 			m.showError = true
@@ -337,6 +386,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}))
 
 	case dropFinishedMsg:
+		m.running.stop()
 		m.showModal = false
 		m.confirmMode = confirmModeNone
 		if msg.err != nil {
@@ -352,6 +402,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}))
 
 	case importFinishedMsg:
+		m.running.stop()
 		m.showModal = false
 		m.inputMode = inputModeNone
 		if msg.err != nil {
@@ -367,6 +418,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}))
 
 	case worktreeCreatedMsg:
+		m.running.stop()
 		m.showModal = false
 		m.inputMode = inputModeNone
 		if msg.err != nil {
@@ -382,6 +434,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}))
 
 	case worktreeRemovedMsg:
+		m.running.stop()
 		m.showModal = false
 		m.confirmMode = confirmModeNone
 		if msg.err != nil {
@@ -421,6 +474,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleConfirmMode(msg)
 		}
 
+		// Quitting would leave the database tools running, so cancel the operation first
+		if m.running.active() && (msg.String() == "ctrl+c" || msg.String() == "q") {
+			m.running.stop()
+			m.modalTitle = "Cancelling..."
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -528,11 +588,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if idx < len(m.dumps) {
 					d := m.dumps[idx]
 					m.inputMode = inputModeImport
-					baseName := d.name
-					if len(baseName) > 4 && baseName[len(baseName)-4:] == ".sql" {
-						baseName = baseName[:len(baseName)-4]
-					}
-					m.inputValue = baseName
+					m.inputValue = d.database
 					m.inputPrompt = "Import " + d.name + " to database: "
 					m.importDumpName = d.name
 				}
@@ -641,7 +697,11 @@ func (m Model) View() string {
 		if db.isDefault {
 			prefix = "* "
 		}
-		dbItems = append(dbItems, prefix+db.name)
+		item := prefix + db.name
+		if db.stats != "" {
+			item += "  (" + db.stats + ")"
+		}
+		dbItems = append(dbItems, item)
 	}
 	dbPane := m.renderListPane("Databases", dbItems, 3, paneWidth)
 
@@ -839,7 +899,7 @@ func (m Model) renderErrorOverlay(baseView string) string {
 		maxWidth = 40
 	}
 
-	wrappedMsg := wrapText(m.errorMessage, maxWidth-6)
+	wrappedMsg := wrapText(redact.String(m.errorMessage), maxWidth-6)
 	errorContent := fmt.Sprintf("✗ Error\n\n%s\n\nPress any key to dismiss", wrappedMsg)
 
 	errorBox := modalStyle.
@@ -23,6 +23,7 @@ type databasesLoadedMsg struct {
 type databaseInfo struct {
 	name      string
 	isDefault bool
+	stats     string // size and table count, empty if unknown
 }
 
 type dumpsLoadedMsg struct {
@@ -30,9 +31,10 @@ type dumpsLoadedMsg struct {
 }
 
 type dumpInfo struct {
-	name string
-	size string
-	date string
+	name     string
+	database string
+	size     string
+	date     string
 }
 
 type dumpFinishedMsg struct {